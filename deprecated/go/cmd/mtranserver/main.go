@@ -1,10 +1,12 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 
+	"github.com/xxnuo/MTranServer/internal/cli"
 	"github.com/xxnuo/MTranServer/internal/config"
 	"github.com/xxnuo/MTranServer/internal/logger"
 	"github.com/xxnuo/MTranServer/internal/server"
@@ -42,10 +44,17 @@ func main() {
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "MTranServer %s - Ultra-low resource consumption, ultra-fast offline translation server\n\n", version.GetVersion())
 		fmt.Fprintf(os.Stderr, "Usage:\n")
-		fmt.Fprintf(os.Stderr, "  %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s [options]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s translate --to <lang> [--from <lang>] [--format text|html|markdown] [file|-]...\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s subtitle --to <lang> [--from <lang>] [--merge] [--output <file>] [file|-]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s catalog --to <lang> [--from <lang>] [--all] [--output <file>] [file|-]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s i18n --to <lang> [--from <lang>] [--existing <file>] [--output <file>] [file|-]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s models export --pairs <from-to>[,...] <bundle.tar.zst>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s models import <bundle.tar.zst>\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nEnvironment Variables:\n")
+		fmt.Fprintf(os.Stderr, "  MT_CONFIG              Config file path (YAML or TOML)\n")
 		fmt.Fprintf(os.Stderr, "  MT_LOG_LEVEL           Log level (debug, info, warn, error)\n")
 		fmt.Fprintf(os.Stderr, "  MT_CONFIG_DIR          Configuration directory\n")
 		fmt.Fprintf(os.Stderr, "  MT_MODEL_DIR           Model directory\n")
@@ -54,18 +63,61 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  MT_ENABLE_UI           Enable Web UI (true/false)\n")
 		fmt.Fprintf(os.Stderr, "  MT_OFFLINE             Enable offline mode (true/false)\n")
 		fmt.Fprintf(os.Stderr, "  MT_WORKER_IDLE_TIMEOUT Worker idle timeout in seconds\n")
+		fmt.Fprintf(os.Stderr, "  MT_WORKER_MAX_INFLIGHT Maximum concurrent requests per worker connection\n")
 		fmt.Fprintf(os.Stderr, "  MT_API_TOKEN           API access token\n")
+		fmt.Fprintf(os.Stderr, "  MT_ADMIN_TOKEN         Admin API access token (defaults to MT_API_TOKEN, admin API is disabled when neither is set)\n")
+		fmt.Fprintf(os.Stderr, "  MT_KEYS_FILE           API keys file (JSON) with per-key scopes and limits\n")
+		fmt.Fprintf(os.Stderr, "  MT_RATE_LIMIT_RPS      Requests per second per API key or client IP\n")
+		fmt.Fprintf(os.Stderr, "  MT_RATE_LIMIT_CHARS    Characters per minute per API key or client IP\n")
+		fmt.Fprintf(os.Stderr, "  MT_QUOTA_DAILY         Daily character quota per API key or client IP\n")
+		fmt.Fprintf(os.Stderr, "  MT_QUOTA_MONTHLY       Monthly character quota per API key or client IP\n")
+		fmt.Fprintf(os.Stderr, "  MT_CACHE_SIZE          Maximum number of cached translations (0 to disable)\n")
+		fmt.Fprintf(os.Stderr, "  MT_CACHE_TTL           Cached translation TTL in seconds\n")
+		fmt.Fprintf(os.Stderr, "  MT_CACHE_PERSIST       Persist translation cache across restarts (true/false)\n")
+		fmt.Fprintf(os.Stderr, "  MT_PROTECT             Spans kept untranslated: url,email,identifier,number,template,printf or none (default)\n")
+		fmt.Fprintf(os.Stderr, "  MT_SPLIT_SENTENCES     Split plain text into sentences: 0 (default), 1 or nonewlines\n")
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
 		fmt.Fprintf(os.Stderr, "  %s --host 127.0.0.1 --port 8080\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --ui --offline\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  MT_PORT=9000 %s\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --config /etc/mtranserver.yaml\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  echo 'Hello' | %s translate --from en --to de\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s translate --to ja --format markdown README.md\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s subtitle --to zh-Hans --merge --output movie.zh.srt movie.srt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s catalog --from en --to de --output de.po messages.pot\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s i18n --from en --to de --existing de.json --output de.json en.json\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nMore information: https://github.com/xxnuo/MTranServer\n")
 	}
 
 	cfg := config.GetConfig()
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "translate":
+			runCommand(cli.RunTranslate, os.Args[2:])
+			return
+		case "subtitle":
+			runCommand(cli.RunSubtitle, os.Args[2:])
+			return
+		case "catalog":
+			runCommand(cli.RunCatalog, os.Args[2:])
+			return
+		case "i18n":
+			runCommand(cli.RunI18n, os.Args[2:])
+			return
+		case "models":
+			runCommand(cli.RunModels, os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
+	if err := config.Load(flag.CommandLine); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	logger.SetLevel(cfg.LogLevel)
 
 	if *versionFlag || *versionShortFlag {
@@ -80,3 +132,13 @@ func main() {
 
 	services.CleanupAllEngines()
 }
+
+func runCommand(run func(args []string) error, args []string) {
+	if err := run(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"time"
+)
+
+// Authenticator 根据旧式单一令牌（API 令牌、管理令牌）和密钥存储校验请求令牌。
+// 令牌通过函数读取，配置热更新后立即生效
+type Authenticator struct {
+	APIToken   func() string
+	AdminToken func() string
+	Keys       *Store
+}
+
+// Required 判断访问指定路由分组是否需要令牌。
+// 未配置任何令牌和密钥时翻译和插件接口保持开放，管理接口总是需要令牌
+func (a *Authenticator) Required(scope string) bool {
+	if scope == ScopeAdmin {
+		return true
+	}
+	if a.Keys != nil && a.Keys.Len() > 0 {
+		return true
+	}
+	return a.apiToken() != ""
+}
+
+// Enabled 判断路由分组是否可以访问。管理接口只有在配置了管理令牌、API 令牌
+// 或具有 admin 权限的密钥时才启用
+func (a *Authenticator) Enabled(scope string) bool {
+	if scope != ScopeAdmin {
+		return true
+	}
+	if a.adminToken() != "" {
+		return true
+	}
+	return a.Keys != nil && a.Keys.AllowsScope(ScopeAdmin)
+}
+
+// Authenticate 校验令牌并返回对应的密钥。
+// 旧式 API 令牌视为可访问所有分组的密钥，未设置管理令牌时也可访问管理接口
+func (a *Authenticator) Authenticate(token string) (*Key, error) {
+	if token == "" {
+		return nil, ErrMissingToken
+	}
+
+	if a.Keys != nil {
+		if key, ok := a.Keys.Lookup(token); ok {
+			if key.Expired(time.Now()) {
+				return nil, ErrKeyExpired
+			}
+			return key, nil
+		}
+	}
+
+	apiToken := a.apiToken()
+	adminToken := a.adminToken()
+
+	if apiToken != "" && token == apiToken {
+		scopes := []string{ScopeTranslate, ScopePlugins, ScopeMetrics}
+		if adminToken == apiToken {
+			scopes = append(scopes, ScopeAdmin)
+		}
+		return &Key{Name: "api-token", Token: token, Scopes: scopes}, nil
+	}
+	if adminToken != "" && token == adminToken {
+		return &Key{Name: "admin-token", Token: token, Scopes: []string{ScopeAdmin}}, nil
+	}
+
+	return nil, ErrInvalidToken
+}
+
+func (a *Authenticator) apiToken() string {
+	if a.APIToken == nil {
+		return ""
+	}
+	return a.APIToken()
+}
+
+// adminToken 返回管理令牌，未设置时使用 API 令牌
+func (a *Authenticator) adminToken() string {
+	if a.AdminToken != nil {
+		if token := a.AdminToken(); token != "" {
+			return token
+		}
+	}
+	return a.apiToken()
+}
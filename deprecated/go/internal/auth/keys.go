@@ -0,0 +1,211 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 路由分组，Key.Scopes 中使用
+const (
+	ScopeTranslate = "translate" // /translate、/translate/batch、/languages、/cache/stats、/glossaries
+	ScopePlugins   = "plugins"   // /imme、/kiss、/deepl、/google、/hcfy 等插件兼容接口
+	ScopeMetrics   = "metrics"   // /metrics
+	ScopeAdmin     = "admin"     // /admin 管理接口
+	ScopeAll       = "*"
+)
+
+var knownScopes = map[string]bool{
+	ScopeTranslate: true,
+	ScopePlugins:   true,
+	ScopeMetrics:   true,
+	ScopeAdmin:     true,
+	ScopeAll:       true,
+}
+
+var (
+	ErrMissingToken = errors.New("missing API key")
+	ErrInvalidToken = errors.New("invalid API key")
+	ErrKeyExpired   = errors.New("API key has expired")
+)
+
+// Key 一个命名的 API 密钥
+type Key struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	// Scopes 允许访问的路由分组，为空时允许除 admin 外的所有分组
+	Scopes []string `json:"scopes,omitempty"`
+	// Pairs 允许的语言对，如 "en-zh-Hans"、"*-en"、"ja-*"，为空时不限制
+	Pairs []string `json:"pairs,omitempty"`
+	// ExpiresAt 过期时间，为空时永不过期
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// 限流和字符配额，0 表示使用全局设置
+	RequestsPerSecond   int `json:"requests_per_second,omitempty"`
+	CharactersPerMinute int `json:"characters_per_minute,omitempty"`
+	DailyCharacters     int `json:"daily_characters,omitempty"`
+	MonthlyCharacters   int `json:"monthly_characters,omitempty"`
+}
+
+// AllowsScope 判断密钥是否可以访问指定路由分组
+func (k *Key) AllowsScope(scope string) bool {
+	if len(k.Scopes) == 0 {
+		return scope != ScopeAdmin
+	}
+	for _, s := range k.Scopes {
+		if s == ScopeAll || s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsPair 判断密钥是否可以翻译指定语言对。
+// 源语言为 auto 时实际语言未知，只有源语言为 * 的规则才能匹配
+func (k *Key) AllowsPair(fromLang, toLang string) bool {
+	if len(k.Pairs) == 0 {
+		return true
+	}
+	for _, pattern := range k.Pairs {
+		if matchPair(pattern, fromLang, toLang) {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired 判断密钥在 now 时是否已过期
+func (k *Key) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+func matchPair(pattern, fromLang, toLang string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if target, ok := strings.CutPrefix(pattern, "*-"); ok {
+		return target == "*" || target == toLang
+	}
+	if source, ok := strings.CutSuffix(pattern, "-*"); ok {
+		return source == fromLang
+	}
+	return pattern == fromLang+"-"+toLang
+}
+
+// Store 密钥存储，按令牌索引，可在运行时整体替换
+type Store struct {
+	mu   sync.RWMutex
+	keys map[string]*Key
+}
+
+// NewStore 创建密钥存储，校验失败时返回错误
+func NewStore(keys []Key) (*Store, error) {
+	s := &Store{}
+	if err := s.Replace(keys); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Replace 用新的密钥列表替换当前内容，校验失败时保留原内容
+func (s *Store) Replace(keys []Key) error {
+	index := make(map[string]*Key, len(keys))
+	names := make(map[string]bool, len(keys))
+
+	for i := range keys {
+		key := keys[i]
+		if key.Name == "" {
+			return fmt.Errorf("key #%d: name is required", i+1)
+		}
+		if names[key.Name] {
+			return fmt.Errorf("key %q: duplicate name", key.Name)
+		}
+		if key.Token == "" {
+			return fmt.Errorf("key %q: token is required", key.Name)
+		}
+		if _, ok := index[key.Token]; ok {
+			return fmt.Errorf("key %q: token is already used by another key", key.Name)
+		}
+		for _, scope := range key.Scopes {
+			if !knownScopes[scope] {
+				return fmt.Errorf("key %q: unknown scope %q", key.Name, scope)
+			}
+		}
+		if key.RequestsPerSecond < 0 || key.CharactersPerMinute < 0 || key.DailyCharacters < 0 || key.MonthlyCharacters < 0 {
+			return fmt.Errorf("key %q: limits must not be negative", key.Name)
+		}
+		for _, pair := range key.Pairs {
+			if pair != "*" && !strings.Contains(pair, "-") {
+				return fmt.Errorf("key %q: pair %q must be in the form from-to", key.Name, pair)
+			}
+		}
+
+		names[key.Name] = true
+		index[key.Token] = &key
+	}
+
+	s.mu.Lock()
+	s.keys = index
+	s.mu.Unlock()
+	return nil
+}
+
+// Len 返回密钥数量
+func (s *Store) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.keys)
+}
+
+// AllowsScope 判断是否有密钥可以访问指定路由分组
+func (s *Store) AllowsScope(scope string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, key := range s.keys {
+		if key.AllowsScope(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// Lookup 按令牌查找密钥
+func (s *Store) Lookup(token string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, ok := s.keys[token]
+	return key, ok
+}
+
+type keysFile struct {
+	Keys []Key `json:"keys"`
+}
+
+// LoadFile 从 JSON 文件加载密钥，格式为 {"keys": [...]}。path 为空时清空密钥
+func (s *Store) LoadFile(path string) error {
+	if path == "" {
+		return s.Replace(nil)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read keys file: %w", err)
+	}
+
+	var kf keysFile
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return fmt.Errorf("failed to parse keys file %s: %w", path, err)
+	}
+
+	if err := s.Replace(kf.Keys); err != nil {
+		return fmt.Errorf("keys file %s: %w", path, err)
+	}
+	return nil
+}
+
+// DefaultStore 服务使用的全局密钥存储
+var DefaultStore = &Store{}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyAllowsScope(t *testing.T) {
+	unscoped := &Key{Name: "default", Token: "t"}
+	assert.True(t, unscoped.AllowsScope(ScopeTranslate))
+	assert.True(t, unscoped.AllowsScope(ScopePlugins))
+	assert.False(t, unscoped.AllowsScope(ScopeAdmin))
+
+	plugins := &Key{Name: "plugins", Token: "t", Scopes: []string{ScopePlugins}}
+	assert.True(t, plugins.AllowsScope(ScopePlugins))
+	assert.False(t, plugins.AllowsScope(ScopeTranslate))
+
+	all := &Key{Name: "all", Token: "t", Scopes: []string{ScopeAll}}
+	assert.True(t, all.AllowsScope(ScopeAdmin))
+}
+
+func TestKeyAllowsPair(t *testing.T) {
+	key := &Key{Name: "k", Token: "t", Pairs: []string{"en-zh-Hans", "*-de", "ja-*"}}
+
+	tests := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{"en", "zh-Hans", true},
+		{"zh-Hans", "en", false},
+		{"fr", "de", true},
+		{"auto", "de", true},
+		{"auto", "zh-Hans", false},
+		{"ja", "ko", true},
+		{"ko", "ja", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.allowed, key.AllowsPair(tt.from, tt.to), "%s -> %s", tt.from, tt.to)
+	}
+
+	assert.True(t, (&Key{}).AllowsPair("en", "fr"))
+}
+
+func TestKeyExpired(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	assert.False(t, (&Key{}).Expired(now))
+	assert.True(t, (&Key{ExpiresAt: &past}).Expired(now))
+	assert.False(t, (&Key{ExpiresAt: &future}).Expired(now))
+}
+
+func TestStoreValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []Key
+		errMsg string
+	}{
+		{"missing name", []Key{{Token: "a"}}, "name is required"},
+		{"missing token", []Key{{Name: "a"}}, "token is required"},
+		{"duplicate name", []Key{{Name: "a", Token: "1"}, {Name: "a", Token: "2"}}, "duplicate name"},
+		{"duplicate token", []Key{{Name: "a", Token: "1"}, {Name: "b", Token: "1"}}, "already used"},
+		{"unknown scope", []Key{{Name: "a", Token: "1", Scopes: []string{"everything"}}}, "unknown scope"},
+		{"invalid pair", []Key{{Name: "a", Token: "1", Pairs: []string{"english"}}}, "from-to"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewStore(tt.keys)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestStoreLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "keys": [
+    {"name": "team-a", "token": "aaa", "scopes": ["translate"], "pairs": ["en-zh-Hans"]},
+    {"name": "ops", "token": "bbb", "scopes": ["admin", "metrics"], "expires_at": "2099-01-01T00:00:00Z"}
+  ]
+}`), 0644))
+
+	s := &Store{}
+	require.NoError(t, s.LoadFile(path))
+	assert.Equal(t, 2, s.Len())
+
+	key, ok := s.Lookup("aaa")
+	require.True(t, ok)
+	assert.Equal(t, "team-a", key.Name)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"keys": [{"name": "broken"}]}`), 0644))
+	assert.Error(t, s.LoadFile(path))
+	assert.Equal(t, 2, s.Len(), "invalid file should keep current keys")
+
+	require.NoError(t, s.LoadFile(""))
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestAuthenticator(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	store, err := NewStore([]Key{
+		{Name: "team-a", Token: "aaa", Scopes: []string{ScopeTranslate}},
+		{Name: "old", Token: "old", ExpiresAt: &past},
+	})
+	require.NoError(t, err)
+
+	a := &Authenticator{
+		APIToken:   func() string { return "api" },
+		AdminToken: func() string { return "admin" },
+		Keys:       store,
+	}
+
+	key, err := a.Authenticate("aaa")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", key.Name)
+
+	key, err = a.Authenticate("api")
+	require.NoError(t, err)
+	assert.True(t, key.AllowsScope(ScopePlugins))
+	assert.False(t, key.AllowsScope(ScopeAdmin))
+
+	key, err = a.Authenticate("admin")
+	require.NoError(t, err)
+	assert.True(t, key.AllowsScope(ScopeAdmin))
+	assert.False(t, key.AllowsScope(ScopeTranslate))
+
+	_, err = a.Authenticate("old")
+	assert.ErrorIs(t, err, ErrKeyExpired)
+	_, err = a.Authenticate("")
+	assert.ErrorIs(t, err, ErrMissingToken)
+	_, err = a.Authenticate("nope")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestAuthenticatorRequired(t *testing.T) {
+	open := &Authenticator{Keys: &Store{}}
+	assert.False(t, open.Required(ScopeTranslate))
+	assert.True(t, open.Required(ScopeAdmin))
+	assert.True(t, open.Enabled(ScopeTranslate))
+	assert.False(t, open.Enabled(ScopeAdmin), "admin API is disabled without any token")
+
+	adminKey, err := NewStore([]Key{{Name: "ops", Token: "ops", Scopes: []string{ScopeAdmin}}})
+	require.NoError(t, err)
+	assert.True(t, (&Authenticator{Keys: adminKey}).Enabled(ScopeAdmin))
+	translateKey, err := NewStore([]Key{{Name: "team", Token: "team"}})
+	require.NoError(t, err)
+	assert.False(t, (&Authenticator{Keys: translateKey}).Enabled(ScopeAdmin))
+
+	adminOnly := &Authenticator{AdminToken: func() string { return "admin" }}
+	assert.False(t, adminOnly.Required(ScopeTranslate))
+	assert.True(t, adminOnly.Required(ScopeAdmin))
+
+	legacy := &Authenticator{APIToken: func() string { return "api" }}
+	assert.True(t, legacy.Required(ScopeAdmin))
+	key, err := legacy.Authenticate("api")
+	require.NoError(t, err)
+	assert.True(t, key.AllowsScope(ScopeAdmin), "API token is also the admin token when none is set")
+}
@@ -0,0 +1,61 @@
+package catalog
+
+import (
+	"regexp"
+
+	"github.com/xxnuo/MTranServer/internal/textfile"
+)
+
+// Format 翻译目录格式
+type Format string
+
+const (
+	FormatPO    Format = "po"
+	FormatXLIFF Format = "xliff"
+)
+
+// htmlTag 判断文本是否包含标签，包含时按 HTML 翻译以保留标签
+var htmlTag = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+
+var formats = textfile.NewFormats("catalog",
+	textfile.Format{Name: string(FormatPO), Aliases: []string{"pot"}, ContentType: "text/x-gettext-translation; charset=utf-8"},
+	textfile.Format{Name: string(FormatXLIFF), Aliases: []string{"xlf"}, ContentType: "application/x-xliff+xml; charset=utf-8"},
+)
+
+// ParseFormat 解析格式名称，支持 po、pot、xliff 和 xlf
+func ParseFormat(name string) (Format, error) {
+	f, err := formats.Parse(name)
+	return Format(f), err
+}
+
+// ContentType 返回格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	return formats.ContentType(string(f))
+}
+
+// Unit 一个待翻译的源文本，译文通过 Catalog.Apply 写回
+type Unit struct {
+	Text string
+	HTML bool
+
+	entry int
+	form  int
+}
+
+// Catalog 解析后的翻译目录。未修改的部分按原文输出，保留注释和顺序
+type Catalog interface {
+	Format() Format
+	// Units 返回需要翻译的源文本，all 为 false 时只包含未翻译的条目
+	Units(all bool) []Unit
+	// Apply 写入译文并把条目标记为待审核（PO 为 fuzzy，XLIFF 为 needs-review-translation）
+	Apply(units []Unit, results []string)
+	String() string
+}
+
+// Parse 解析 PO/POT 或 XLIFF 1.2，以 < 开头的视为 XLIFF
+func Parse(data string) (Catalog, error) {
+	if textfile.HasPrefix(data, "<") {
+		return parseXLIFF(data)
+	}
+	return parsePO(data)
+}
@@ -0,0 +1,218 @@
+package catalog
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const samplePO = `# Translation template.
+msgid ""
+msgstr ""
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+#. Greeting shown on the home page
+#: src/home.c:12
+msgid "Hello, %s!"
+msgstr ""
+
+#: src/home.c:20
+#, c-format
+msgid "Already done"
+msgstr "Schon erledigt"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] ""
+msgstr[1] ""
+
+#~ msgid "Old"
+#~ msgstr "Alt"
+`
+
+func translateAll(units []Unit) []string {
+	results := make([]string, len(units))
+	for i, u := range units {
+		results[i] = "T:" + u.Text
+	}
+	return results
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"po": FormatPO, ".pot": FormatPO, "XLIFF": FormatXLIFF, ".xlf": FormatXLIFF} {
+		got, err := ParseFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseFormat("json")
+	assert.Error(t, err)
+}
+
+func TestPORoundTrip(t *testing.T) {
+	c, err := Parse(samplePO)
+	require.NoError(t, err)
+	assert.Equal(t, FormatPO, c.Format())
+	assert.Equal(t, samplePO, c.String())
+}
+
+func TestPOUnits(t *testing.T) {
+	c, err := Parse(samplePO)
+	require.NoError(t, err)
+
+	var texts []string
+	for _, u := range c.Units(false) {
+		texts = append(texts, u.Text)
+	}
+	assert.Equal(t, []string{"Hello, %s!", "%d file", "%d files"}, texts)
+
+	texts = nil
+	for _, u := range c.Units(true) {
+		texts = append(texts, u.Text)
+	}
+	assert.Equal(t, []string{"Hello, %s!", "Already done", "%d file", "%d files"}, texts)
+}
+
+func TestPOApply(t *testing.T) {
+	c, err := Parse(samplePO)
+	require.NoError(t, err)
+
+	units := c.Units(true)
+	c.Apply(units, translateAll(units))
+
+	want := strings.NewReplacer(
+		"#: src/home.c:12\nmsgid \"Hello, %s!\"\nmsgstr \"\"",
+		"#: src/home.c:12\n#, fuzzy\nmsgid \"Hello, %s!\"\nmsgstr \"T:Hello, %s!\"",
+		"#, c-format\nmsgid \"Already done\"\nmsgstr \"Schon erledigt\"",
+		"#, fuzzy, c-format\nmsgid \"Already done\"\nmsgstr \"T:Already done\"",
+		"msgid \"%d file\"",
+		"#, fuzzy\nmsgid \"%d file\"",
+		"msgstr[0] \"\"\nmsgstr[1] \"\"",
+		"msgstr[0] \"T:%d file\"\nmsgstr[1] \"T:%d files\"",
+	).Replace(samplePO)
+	assert.Equal(t, want, c.String())
+}
+
+func TestPOFlagsBeforePreviousMsgid(t *testing.T) {
+	c, err := Parse("#: a.c:1\n#| msgid \"Helo\"\nmsgid \"Hello\"\nmsgstr \"\"\n")
+	require.NoError(t, err)
+
+	units := c.Units(false)
+	c.Apply(units, []string{"Hallo"})
+	assert.Equal(t, "#: a.c:1\n#, fuzzy\n#| msgid \"Helo\"\nmsgid \"Hello\"\nmsgstr \"Hallo\"\n", c.String())
+}
+
+func TestPOSinglePluralForm(t *testing.T) {
+	po := "msgid \"\"\nmsgstr \"Plural-Forms: nplurals=1; plural=0;\\n\"\n\nmsgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"\"\n"
+	c, err := Parse(po)
+	require.NoError(t, err)
+
+	units := c.Units(false)
+	require.Len(t, units, 2)
+	c.Apply(units, []string{"%d 个文件", "%d 个文件们"})
+	assert.Contains(t, c.String(), "msgstr[0] \"%d 个文件们\"\n")
+	assert.NotContains(t, c.String(), "msgstr[1]")
+}
+
+func TestPOMultiline(t *testing.T) {
+	po := "msgid \"\"\n\"Line one\\n\"\n\"Line \\\"two\\\"\"\nmsgstr \"\"\n"
+	c, err := Parse(po)
+	require.NoError(t, err)
+
+	units := c.Units(false)
+	require.Len(t, units, 1)
+	assert.Equal(t, "Line one\nLine \"two\"", units[0].Text)
+
+	c.Apply(units, []string{"Zeile eins\nZeile \"zwei\""})
+	assert.Equal(t, "#, fuzzy\nmsgid \"\"\n\"Line one\\n\"\n\"Line \\\"two\\\"\"\nmsgstr \"\"\n\"Zeile eins\\n\"\n\"Zeile \\\"zwei\\\"\"\n", c.String())
+}
+
+func TestPOInvalid(t *testing.T) {
+	_, err := Parse("# comment only\n")
+	assert.EqualError(t, err, "no PO entries found")
+
+	_, err = Parse("msgid \"Hello\"\n")
+	assert.ErrorContains(t, err, "entry must have both msgid and msgstr")
+
+	_, err = Parse("msgid \"Hello\nmsgstr \"\"\n")
+	assert.ErrorContains(t, err, "invalid string")
+}
+
+const sampleXLIFF = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff version="1.2" xmlns="urn:oasis:names:tc:xliff:document:1.2">
+  <file source-language="en" target-language="de" datatype="plaintext" original="app">
+    <body>
+      <trans-unit id="greeting">
+        <source>Hello, {name} &amp; friends!</source>
+        <note>Shown on login</note>
+      </trans-unit>
+      <trans-unit id="done">
+        <source>Done</source>
+        <target state="translated">Fertig</target>
+      </trans-unit>
+      <trans-unit id="link">
+        <source>Click <g id="1">here</g></source>
+        <target state="new"/>
+      </trans-unit>
+      <trans-unit id="brand" translate="no">
+        <source>MTranServer</source>
+      </trans-unit>
+    </body>
+  </file>
+</xliff>
+`
+
+func TestXLIFFUnits(t *testing.T) {
+	c, err := Parse(sampleXLIFF)
+	require.NoError(t, err)
+	assert.Equal(t, FormatXLIFF, c.Format())
+	assert.Equal(t, sampleXLIFF, c.String())
+
+	units := c.Units(false)
+	require.Len(t, units, 2)
+	assert.Equal(t, Unit{Text: "Hello, {name} & friends!", entry: 0}, units[0])
+	assert.Equal(t, Unit{Text: `Click <g id="1">here</g>`, HTML: true, entry: 2}, units[1])
+
+	assert.Len(t, c.Units(true), 3)
+}
+
+func TestXLIFFApply(t *testing.T) {
+	c, err := Parse(sampleXLIFF)
+	require.NoError(t, err)
+
+	units := c.Units(true)
+	c.Apply(units, []string{"Hallo, {name} & Freunde!", "Erledigt", `Klicken Sie <g id="1">hier</g>`})
+
+	want := strings.NewReplacer(
+		"<source>Hello, {name} &amp; friends!</source>\n",
+		"<source>Hello, {name} &amp; friends!</source>\n        <target state=\"needs-review-translation\">Hallo, {name} &amp; Freunde!</target>\n",
+		`<target state="translated">Fertig</target>`,
+		`<target state="needs-review-translation">Erledigt</target>`,
+		`<target state="new"/>`,
+		`<target state="needs-review-translation">Klicken Sie <g id="1">hier</g></target>`,
+	).Replace(sampleXLIFF)
+	assert.Equal(t, want, c.String())
+}
+
+func TestXLIFFNamespacePrefix(t *testing.T) {
+	data := `<x:xliff version="1.2" xmlns:x="urn:oasis:names:tc:xliff:document:1.2"><x:file><x:body><x:trans-unit id="a"><x:source>Hi</x:source></x:trans-unit></x:body></x:file></x:xliff>`
+	c, err := Parse(data)
+	require.NoError(t, err)
+
+	units := c.Units(false)
+	c.Apply(units, []string{"Hallo"})
+	assert.Contains(t, c.String(), `<x:source>Hi</x:source><x:target state="needs-review-translation">Hallo</x:target></x:trans-unit>`)
+}
+
+func TestXLIFFInvalid(t *testing.T) {
+	_, err := Parse(`<xliff version="2.0"><file><unit id="a"><segment><source>Hi</source></segment></unit></file></xliff>`)
+	assert.EqualError(t, err, "unsupported XLIFF version 2.0, only 1.2 is supported")
+
+	_, err = Parse(`<xliff version="1.2"><file><body></body></file></xliff>`)
+	assert.EqualError(t, err, "no XLIFF trans-units found")
+
+	_, err = Parse(`<xliff version="1.2"><file>`)
+	assert.ErrorContains(t, err, "invalid XLIFF")
+}
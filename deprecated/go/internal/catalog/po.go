@@ -0,0 +1,375 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/textfile"
+)
+
+var pluralForms = regexp.MustCompile(`nplurals\s*=\s*(\d+)`)
+
+// poEntry PO 文件中以空行分隔的一个条目
+type poEntry struct {
+	lines []string
+
+	msgctxt     *string
+	msgid       string
+	msgidPlural *string
+	msgstr      []string
+	// msgstrLine 第一行 msgstr 在 lines 中的位置，之后的行都属于 msgstr
+	msgstrLine int
+	flagsLine  int
+	flags      []string
+	obsolete   bool
+	modified   bool
+}
+
+func (e *poEntry) header() bool {
+	return e.msgid == "" && e.msgctxt == nil
+}
+
+func (e *poEntry) translated() bool {
+	for _, s := range e.msgstr {
+		if s != "" {
+			return true
+		}
+	}
+	return false
+}
+
+type poFile struct {
+	entries  []*poEntry
+	nplurals int
+	enc      textfile.Encoding
+}
+
+func parsePO(data string) (*poFile, error) {
+	f := &poFile{}
+	data, f.enc = textfile.Decode(data)
+
+	var current []string
+	lineNo := 0
+	flush := func() error {
+		if len(current) == 0 {
+			return nil
+		}
+		entry, err := parsePOEntry(current)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo-len(current), err)
+		}
+		f.entries = append(f.entries, entry)
+		current = nil
+		return nil
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		lineNo++
+		if strings.TrimSpace(line) == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	lineNo++
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	entries := 0
+	for _, e := range f.entries {
+		if e.obsolete {
+			continue
+		}
+		if e.header() {
+			if m := pluralForms.FindStringSubmatch(strings.Join(e.msgstr, "")); m != nil {
+				f.nplurals, _ = strconv.Atoi(m[1])
+			}
+			continue
+		}
+		entries++
+	}
+	if entries == 0 {
+		return nil, errors.New("no PO entries found")
+	}
+
+	return f, nil
+}
+
+func parsePOEntry(lines []string) (*poEntry, error) {
+	e := &poEntry{lines: lines, msgstrLine: -1, flagsLine: -1}
+
+	var target *string
+	hasMsgid := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "#") {
+			if strings.HasPrefix(trimmed, "#~") {
+				e.obsolete = true
+			}
+			if strings.HasPrefix(trimmed, "#,") {
+				e.flagsLine = i
+				for _, flag := range strings.Split(trimmed[2:], ",") {
+					if flag = strings.TrimSpace(flag); flag != "" {
+						e.flags = append(e.flags, flag)
+					}
+				}
+			}
+			continue
+		}
+		if e.obsolete {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, `"`) {
+			if target == nil {
+				return nil, fmt.Errorf("unexpected string %s", trimmed)
+			}
+			s, err := unquotePO(trimmed)
+			if err != nil {
+				return nil, err
+			}
+			*target += s
+			continue
+		}
+
+		keyword, value, ok := strings.Cut(trimmed, " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %s", trimmed)
+		}
+		s, err := unquotePO(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			e.msgctxt = &s
+			target = e.msgctxt
+		case keyword == "msgid":
+			e.msgid = s
+			target = &e.msgid
+			hasMsgid = true
+		case keyword == "msgid_plural":
+			e.msgidPlural = &s
+			target = e.msgidPlural
+		case keyword == "msgstr" || strings.HasPrefix(keyword, "msgstr["):
+			n := 0
+			if keyword != "msgstr" {
+				n, err = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(keyword, "msgstr["), "]"))
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid keyword %s", keyword)
+				}
+			}
+			if e.msgstrLine < 0 {
+				e.msgstrLine = i
+			}
+			for len(e.msgstr) <= n {
+				e.msgstr = append(e.msgstr, "")
+			}
+			e.msgstr[n] = s
+			target = &e.msgstr[n]
+		default:
+			return nil, fmt.Errorf("unknown keyword %s", keyword)
+		}
+	}
+
+	if !e.obsolete && (!hasMsgid || e.msgstrLine < 0) {
+		if hasMsgid || e.msgstrLine >= 0 {
+			return nil, errors.New("entry must have both msgid and msgstr")
+		}
+		// 只有注释的块原样保留
+		e.obsolete = true
+	}
+	return e, nil
+}
+
+func (f *poFile) Format() Format {
+	return FormatPO
+}
+
+// Units 复数条目返回两个单元：msgid 和 msgid_plural
+func (f *poFile) Units(all bool) []Unit {
+	var units []Unit
+	for i, e := range f.entries {
+		if e.obsolete || e.header() || (!all && e.translated()) {
+			continue
+		}
+		units = append(units, Unit{Text: e.msgid, HTML: htmlTag.MatchString(e.msgid), entry: i})
+		if e.msgidPlural != nil {
+			units = append(units, Unit{Text: *e.msgidPlural, HTML: htmlTag.MatchString(*e.msgidPlural), entry: i, form: 1})
+		}
+	}
+	return units
+}
+
+func (f *poFile) Apply(units []Unit, results []string) {
+	singular := make(map[int]string)
+	plural := make(map[int]string)
+	for i, unit := range units {
+		if unit.form == 0 {
+			singular[unit.entry] = results[i]
+		} else {
+			plural[unit.entry] = results[i]
+		}
+	}
+
+	for i, result := range singular {
+		e := f.entries[i]
+		if e.msgidPlural == nil {
+			e.msgstr = []string{result}
+		} else {
+			e.msgstr = f.pluralStrings(e, result, plural[i])
+		}
+		if !e.hasFlag("fuzzy") {
+			e.flags = append([]string{"fuzzy"}, e.flags...)
+		}
+		e.modified = true
+	}
+}
+
+// pluralStrings 第一个复数形式使用 msgid 的译文，其余使用 msgid_plural 的译文。
+// 只有一种复数形式的语言（如中文、日语）使用 msgid_plural 的译文
+func (f *poFile) pluralStrings(e *poEntry, singular, plural string) []string {
+	n := f.nplurals
+	if n <= 0 {
+		n = max(len(e.msgstr), 2)
+	}
+	if n == 1 {
+		return []string{plural}
+	}
+
+	forms := make([]string, n)
+	forms[0] = singular
+	for i := 1; i < n; i++ {
+		forms[i] = plural
+	}
+	return forms
+}
+
+func (e *poEntry) hasFlag(flag string) bool {
+	for _, f := range e.flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *poFile) String() string {
+	var sb strings.Builder
+	for i, e := range f.entries {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		for _, line := range e.render() {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+
+	return f.enc.Encode(sb.String())
+}
+
+func (e *poEntry) render() []string {
+	if !e.modified {
+		return e.lines
+	}
+
+	flagsLine := "#, " + strings.Join(e.flags, ", ")
+	lines := make([]string, 0, len(e.lines)+1)
+
+	// 没有 flags 行时插入到 #| 注释之前，或者注释的末尾
+	insertAt := -1
+	if e.flagsLine < 0 {
+		insertAt = len(e.lines[:e.msgstrLine])
+		for i, line := range e.lines[:e.msgstrLine] {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "#|") || !strings.HasPrefix(trimmed, "#") {
+				insertAt = i
+				break
+			}
+		}
+	}
+
+	for i, line := range e.lines[:e.msgstrLine] {
+		if i == insertAt {
+			lines = append(lines, flagsLine)
+		}
+		if i == e.flagsLine {
+			line = flagsLine
+		}
+		lines = append(lines, line)
+	}
+	if insertAt == e.msgstrLine {
+		lines = append(lines, flagsLine)
+	}
+
+	if e.msgidPlural == nil {
+		return append(lines, quotePO("msgstr", e.msgstr[0])...)
+	}
+	for i, s := range e.msgstr {
+		lines = append(lines, quotePO(fmt.Sprintf("msgstr[%d]", i), s)...)
+	}
+	return lines
+}
+
+func unquotePO(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+
+	s = s[1 : len(s)-1]
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case 'r':
+			sb.WriteByte('\r')
+		case 'a':
+			sb.WriteByte('\a')
+		case 'b':
+			sb.WriteByte('\b')
+		case 'f':
+			sb.WriteByte('\f')
+		case 'v':
+			sb.WriteByte('\v')
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String(), nil
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+// quotePO 按 gettext 的习惯输出字符串，包含换行时每行一段
+func quotePO(keyword, s string) []string {
+	parts := strings.SplitAfter(s, "\n")
+	if parts[len(parts)-1] == "" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) <= 1 {
+		return []string{keyword + ` "` + poEscaper.Replace(s) + `"`}
+	}
+
+	lines := []string{keyword + ` ""`}
+	for _, part := range parts {
+		lines = append(lines, `"`+poEscaper.Replace(part)+`"`)
+	}
+	return lines
+}
@@ -0,0 +1,223 @@
+package catalog
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// reviewState 机器翻译的 target 使用的 XLIFF 1.2 状态
+const reviewState = "needs-review-translation"
+
+var (
+	stateAttr   = regexp.MustCompile(`\s+state\s*=\s*(?:"[^"]*"|'[^']*')`)
+	elementName = regexp.MustCompile(`^<([^\s/>]+)`)
+	xmlEscaper  = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+)
+
+// xliffUnit 一个 trans-unit，偏移均为在原文中的字节位置
+type xliffUnit struct {
+	// source 为 <source> 的原始内容（XML 片段）
+	source string
+	// sourceStart 为 <source 标签的开始位置，sourceEnd 为 </source> 之后的位置
+	sourceStart int
+	sourceEnd   int
+	// targetStart/targetEnd 为整个 <target> 元素的范围，没有 target 时为 -1
+	targetStart int
+	targetEnd   int
+	// targetTag 为 <target ...> 开始标签的原文
+	targetTag string
+	target    string
+	state     string
+
+	result   string
+	modified bool
+}
+
+func (u *xliffUnit) translated() bool {
+	if u.targetStart < 0 || strings.TrimSpace(u.target) == "" {
+		return false
+	}
+	return u.state != "new" && u.state != "needs-translation"
+}
+
+// xliffFile XLIFF 1.2 文件，修改时只替换或插入 target 元素，其余内容原样保留
+type xliffFile struct {
+	data  string
+	units []*xliffUnit
+}
+
+func parseXLIFF(data string) (*xliffFile, error) {
+	f := &xliffFile{data: data}
+
+	d := xml.NewDecoder(strings.NewReader(data))
+	var stack []string
+	var unit *xliffUnit
+	skip := false
+	var elementStart int
+	var contentStart int
+
+	for {
+		before := int(d.InputOffset())
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLIFF: %w", err)
+		}
+		after := int(d.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			stack = append(stack, t.Name.Local)
+
+			switch {
+			case t.Name.Local == "xliff" && parent == "":
+				if version := attr(t, "version"); version != "" && !strings.HasPrefix(version, "1.") {
+					return nil, fmt.Errorf("unsupported XLIFF version %s, only 1.2 is supported", version)
+				}
+			case t.Name.Local == "trans-unit":
+				unit = &xliffUnit{targetStart: -1, targetEnd: -1}
+				skip = attr(t, "translate") == "no"
+			case unit != nil && parent == "trans-unit" && (t.Name.Local == "source" || t.Name.Local == "target"):
+				elementStart = before
+				contentStart = after
+				if t.Name.Local == "target" {
+					unit.targetTag = data[before:after]
+					unit.state = attr(t, "state")
+				}
+			}
+
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+			parent := ""
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+
+			switch {
+			case t.Name.Local == "trans-unit" && unit != nil:
+				if !skip && unit.sourceEnd > 0 {
+					f.units = append(f.units, unit)
+				}
+				unit = nil
+			case unit != nil && parent == "trans-unit" && t.Name.Local == "source":
+				unit.source = data[contentStart:before]
+				unit.sourceStart = elementStart
+				unit.sourceEnd = after
+			case unit != nil && parent == "trans-unit" && t.Name.Local == "target":
+				unit.target = data[contentStart:before]
+				unit.targetStart = elementStart
+				unit.targetEnd = after
+			}
+		}
+	}
+
+	if len(f.units) == 0 {
+		return nil, errors.New("no XLIFF trans-units found")
+	}
+	return f, nil
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (f *xliffFile) Format() Format {
+	return FormatXLIFF
+}
+
+// Units 源文本包含行内标记（<g>、<x/> 等）时按 HTML 翻译原始 XML，否则翻译反转义后的纯文本
+func (f *xliffFile) Units(all bool) []Unit {
+	var units []Unit
+	for i, u := range f.units {
+		if strings.TrimSpace(u.source) == "" || (!all && u.translated()) {
+			continue
+		}
+		if strings.Contains(u.source, "<") {
+			units = append(units, Unit{Text: u.source, HTML: true, entry: i})
+		} else {
+			units = append(units, Unit{Text: html.UnescapeString(u.source), entry: i})
+		}
+	}
+	return units
+}
+
+func (f *xliffFile) Apply(units []Unit, results []string) {
+	for i, unit := range units {
+		u := f.units[unit.entry]
+		if unit.HTML {
+			u.result = results[i]
+		} else {
+			u.result = xmlEscaper.Replace(results[i])
+		}
+		u.modified = true
+	}
+}
+
+func (f *xliffFile) String() string {
+	type edit struct {
+		start, end int
+		text       string
+	}
+
+	var edits []edit
+	for _, u := range f.units {
+		if !u.modified {
+			continue
+		}
+
+		if u.targetStart >= 0 {
+			name := elementName.FindStringSubmatch(u.targetTag)[1]
+			edits = append(edits, edit{u.targetStart, u.targetEnd, targetStartTag(u.targetTag) + u.result + "</" + name + ">"})
+			continue
+		}
+
+		// 没有 target 时插入到 </source> 之后，沿用 source 的缩进和命名空间前缀
+		name := strings.Replace(elementName.FindStringSubmatch(f.data[u.sourceStart:])[1], "source", "target", 1)
+		lineStart := strings.LastIndexByte(f.data[:u.sourceStart], '\n') + 1
+		prefix := ""
+		if indent := f.data[lineStart:u.sourceStart]; lineStart > 0 && strings.TrimSpace(indent) == "" {
+			prefix = "\n" + indent
+			if lineStart >= 2 && f.data[lineStart-2] == '\r' {
+				prefix = "\r" + prefix
+			}
+		}
+		text := fmt.Sprintf(`%s<%s state="%s">%s</%s>`, prefix, name, reviewState, u.result, name)
+		edits = append(edits, edit{u.sourceEnd, u.sourceEnd, text})
+	}
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var sb strings.Builder
+	last := 0
+	for _, e := range edits {
+		sb.WriteString(f.data[last:e.start])
+		sb.WriteString(e.text)
+		last = e.end
+	}
+	sb.WriteString(f.data[last:])
+	return sb.String()
+}
+
+// targetStartTag 把原 target 开始标签的 state 改为待审核，自闭合标签改为开始标签
+func targetStartTag(tag string) string {
+	tag = strings.TrimSuffix(strings.TrimSuffix(tag, ">"), "/")
+	tag = stateAttr.ReplaceAllString(tag, "")
+	return strings.TrimRight(tag, " \t\r\n") + ` state="` + reviewState + `">`
+}
@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// fileCommand 整文件翻译子命令：解析文件、翻译并按原格式写到 --output 或标准输出
+type fileCommand struct {
+	name     string
+	fileType *services.FileType
+	// usage 用法中的专有选项，description 为用法下方的说明
+	usage       string
+	description string
+	// options 注册专有选项，返回的函数在解析参数后生成翻译选项
+	options func(fs *flag.FlagSet) func() (services.FileOptions, error)
+}
+
+// RunSubtitle 执行 subtitle 子命令：翻译 SRT 或 WebVTT 字幕
+func RunSubtitle(args []string) error {
+	return runFileCommand(args, fileCommand{
+		name:        "subtitle",
+		fileType:    services.SubtitleFile,
+		usage:       "[--merge]",
+		description: "Translates SRT or WebVTT subtitles",
+		options: func(fs *flag.FlagSet) func() (services.FileOptions, error) {
+			merge := fs.Bool("merge", false, "Merge cues that split sentences before translating")
+			return func() (services.FileOptions, error) {
+				return services.FileOptions{Merge: *merge}, nil
+			}
+		},
+	})
+}
+
+// RunCatalog 执行 catalog 子命令：翻译 PO/POT 或 XLIFF 文件
+func RunCatalog(args []string) error {
+	return runFileCommand(args, fileCommand{
+		name:     "catalog",
+		fileType: services.CatalogFile,
+		usage:    "[--all]",
+		description: "Translates gettext PO/POT or XLIFF 1.2 catalogs. " +
+			"Machine translations are marked fuzzy (PO) or needs-review-translation (XLIFF)",
+		options: func(fs *flag.FlagSet) func() (services.FileOptions, error) {
+			all := fs.Bool("all", false, "Translate all entries instead of only untranslated ones")
+			return func() (services.FileOptions, error) {
+				return services.FileOptions{All: *all}, nil
+			}
+		},
+	})
+}
+
+// RunI18n 执行 i18n 子命令：翻译 JSON/YAML 资源文件
+func RunI18n(args []string) error {
+	return runFileCommand(args, fileCommand{
+		name:        "i18n",
+		fileType:    services.I18nFile,
+		usage:       "[--existing <file>]",
+		description: "Translates string values in JSON or YAML locale files",
+		options: func(fs *flag.FlagSet) func() (services.FileOptions, error) {
+			existing := fs.String("existing", "", "Existing target file; only keys missing from it are translated (may be the same as --output)")
+			return func() (services.FileOptions, error) {
+				if *existing == "" {
+					return services.FileOptions{}, nil
+				}
+				data, err := os.ReadFile(*existing)
+				if err != nil {
+					return services.FileOptions{}, fmt.Errorf("failed to read %s: %w", *existing, err)
+				}
+				return services.FileOptions{Existing: string(data)}, nil
+			}
+		},
+	})
+}
+
+func runFileCommand(args []string, cmd fileCommand) error {
+	cfg := config.GetConfig()
+
+	fs := newFlagSet(cmd.name)
+	from := fs.String("from", "auto", "Source language")
+	to := fs.String("to", "", "Target language")
+	output := fs.String("output", "", "Output file (default: standard output)")
+	options := cmd.options(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+		fmt.Fprintf(fs.Output(), "  %s %s --to <lang> [--from <lang>] %s [--output <file>] [file|-]\n\n", os.Args[0], cmd.name, cmd.usage)
+		fmt.Fprintf(fs.Output(), "%s, reading from standard input when no file or \"-\" is given.\n\n", cmd.description)
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.Load(fs); err != nil {
+		return err
+	}
+
+	if *to == "" {
+		fs.Usage()
+		return errors.New("--to is required")
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("at most one input file is allowed")
+	}
+
+	input := "-"
+	if fs.NArg() == 1 {
+		input = fs.Arg(0)
+	}
+
+	data, err := readInput(input)
+	if err != nil {
+		return err
+	}
+
+	opts, err := options()
+	if err != nil {
+		return err
+	}
+
+	file, err := cmd.fileType.Parse(data, "", opts)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", input, err)
+	}
+	if *output != "" {
+		if format, err := cmd.fileType.ParseFormat(filepath.Ext(*output)); err == nil && format != file.Format() {
+			return fmt.Errorf("input is %s but output file extension is %s", file.Format(), filepath.Ext(*output))
+		}
+	}
+
+	fromLang := utils.NormalizeLanguageCode(*from)
+	toLang := utils.NormalizeLanguageCode(*to)
+
+	// 标准输出只用于翻译结果
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(cfg.LogLevel)
+
+	if err := initRuntime(cfg); err != nil {
+		return err
+	}
+	defer services.SaveCache()
+	defer services.CleanupAllEngines()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	n, err := file.Translate(ctx, fromLang, toLang)
+	if err != nil {
+		return fmt.Errorf("failed to translate %s: %w", input, err)
+	}
+	logger.Info("Translated %d %s", n, cmd.fileType.Unit)
+
+	out, err := file.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", file.Format(), err)
+	}
+	if *output == "" {
+		_, err := os.Stdout.Write(out)
+		return err
+	}
+	if err := os.WriteFile(*output, out, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunFileCommandArgs(t *testing.T) {
+	err := RunSubtitle([]string{"--from", "en"})
+	assert.EqualError(t, err, "--to is required")
+
+	err = RunCatalog([]string{"--to", "de", "a.po", "b.po"})
+	assert.EqualError(t, err, "at most one input file is allowed")
+
+	err = RunI18n([]string{"--to", "de", "--existing", filepath.Join(t.TempDir(), "missing.json"), "-"})
+	assert.ErrorContains(t, err, "failed to read")
+}
+
+func TestRunFileCommandInvalidInput(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		return path
+	}
+
+	tests := []struct {
+		run  func([]string) error
+		args []string
+		err  string
+	}{
+		{RunSubtitle, []string{write("input.srt", "not a subtitle\n")}, "no subtitle cues found"},
+		{RunCatalog, []string{write("input.po", "# only a comment\n")}, "no PO entries found"},
+		{RunI18n, []string{write("input.json", `{"a": }`)}, "invalid JSON"},
+		{
+			RunSubtitle,
+			[]string{"--output", filepath.Join(dir, "output.srt"), write("input.vtt", "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n")},
+			"input is vtt but output file extension is .srt",
+		},
+		{
+			RunI18n,
+			[]string{"--output", filepath.Join(dir, "de.yml"), write("en.json", `{"a": "Apple"}`)},
+			"input is json but output file extension is .yml",
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.run(append([]string{"--to", "de"}, tt.args...))
+		assert.ErrorContains(t, err, tt.err)
+	}
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func modelsUsage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n")
+	fmt.Fprintf(os.Stderr, "  %s models export --pairs <from-to>[,<from-to>...] <bundle.tar.zst>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "  %s models import <bundle.tar.zst>\n", os.Args[0])
+}
+
+// RunModels 执行 models 子命令：导出或导入离线模型包
+func RunModels(args []string) error {
+	if len(args) == 0 {
+		modelsUsage()
+		return errors.New("missing models command")
+	}
+
+	switch args[0] {
+	case "export":
+		return runModelsExport(args[1:])
+	case "import":
+		return runModelsImport(args[1:])
+	case "-h", "-help", "--help", "help":
+		modelsUsage()
+		return nil
+	default:
+		modelsUsage()
+		return fmt.Errorf("unknown models command: %s", args[0])
+	}
+}
+
+func runModelsExport(args []string) error {
+	cfg := config.GetConfig()
+
+	fs := newFlagSet("models export")
+	pairsFlag := fs.String("pairs", "", "Comma-separated language pairs to export, e.g. en-de,de-en")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+		fmt.Fprintf(fs.Output(), "  %s models export --pairs <from-to>[,<from-to>...] <bundle.tar.zst>\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.Load(fs); err != nil {
+		return err
+	}
+
+	if *pairsFlag == "" || fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("--pairs and exactly one bundle path are required")
+	}
+
+	logger.SetLevel(cfg.LogLevel)
+
+	if err := models.InitRecords(); err != nil {
+		return fmt.Errorf("failed to initialize records: %w", err)
+	}
+
+	var pairs []models.BundlePair
+	for _, p := range strings.Split(*pairsFlag, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		from, to, err := models.ParseLanguagePair(p)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, models.BundlePair{From: from, To: to})
+	}
+
+	if err := models.ExportBundle(fs.Arg(0), pairs); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d language pair(s) to %s\n", len(pairs), fs.Arg(0))
+	return nil
+}
+
+func runModelsImport(args []string) error {
+	cfg := config.GetConfig()
+
+	fs := newFlagSet("models import")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+		fmt.Fprintf(fs.Output(), "  %s models import <bundle.tar.zst>\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.Load(fs); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("exactly one bundle path is required")
+	}
+
+	logger.SetLevel(cfg.LogLevel)
+
+	if err := models.InitRecords(); err != nil {
+		return fmt.Errorf("failed to initialize records: %w", err)
+	}
+
+	pairs, err := models.ImportBundle(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	for _, pair := range pairs {
+		fmt.Fprintf(os.Stderr, "Imported %s -> %s\n", pair.From, pair.To)
+	}
+	return nil
+}
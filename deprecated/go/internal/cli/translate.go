@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/manager"
+	"github.com/xxnuo/MTranServer/internal/models"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// newFlagSet 创建子命令参数集，并继承全局配置参数（--model-dir、--offline 等）
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	flag.CommandLine.VisitAll(func(f *flag.Flag) {
+		fs.Var(f.Value, f.Name, f.Usage)
+	})
+	return fs
+}
+
+// RunTranslate 执行 translate 子命令：在进程内启动引擎翻译文件或标准输入，结果写到标准输出
+func RunTranslate(args []string) error {
+	cfg := config.GetConfig()
+
+	fs := newFlagSet("translate")
+	from := fs.String("from", "auto", "Source language")
+	to := fs.String("to", "", "Target language")
+	isHTML := fs.Bool("html", false, "Treat input as HTML (same as --format html)")
+	format := fs.String("format", "text", "Input format: text, html or markdown")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n")
+		fmt.Fprintf(fs.Output(), "  %s translate --to <lang> [--from <lang>] [--format text|html|markdown] [file|-]...\n\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "Reads from standard input when no file or \"-\" is given.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := config.Load(fs); err != nil {
+		return err
+	}
+
+	if *to == "" {
+		fs.Usage()
+		return errors.New("--to is required")
+	}
+	if *isHTML {
+		*format = "html"
+	}
+	switch *format {
+	case "text", "html", "markdown":
+	default:
+		fs.Usage()
+		return fmt.Errorf("unsupported format: %s", *format)
+	}
+
+	fromLang := utils.NormalizeLanguageCode(*from)
+	toLang := utils.NormalizeLanguageCode(*to)
+
+	// 标准输出只用于翻译结果
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(cfg.LogLevel)
+
+	if err := initRuntime(cfg); err != nil {
+		return err
+	}
+	defer services.SaveCache()
+	defer services.CleanupAllEngines()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	inputs := fs.Args()
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+
+	for _, input := range inputs {
+		text, err := readInput(input)
+		if err != nil {
+			return err
+		}
+
+		result, err := translateText(ctx, fromLang, toLang, text, *format)
+		if err != nil {
+			return fmt.Errorf("failed to translate %s: %w", input, err)
+		}
+
+		if _, err := io.WriteString(os.Stdout, result); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func initRuntime(cfg *config.Config) error {
+	if err := models.InitRecords(); err != nil {
+		return fmt.Errorf("failed to initialize records: %w", err)
+	}
+
+	if err := os.MkdirAll(cfg.ModelDir, 0755); err != nil {
+		return fmt.Errorf("failed to create model directory: %w", err)
+	}
+
+	if err := manager.EnsureWorkerBinary(cfg); err != nil {
+		return fmt.Errorf("failed to initialize worker binary: %w", err)
+	}
+
+	services.InitCache()
+	return nil
+}
+
+func readInput(name string) (string, error) {
+	if name == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return string(data), nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return string(data), nil
+}
+
+// translateText 翻译整段输入。纯文本按空行切分为段落后翻译，段落内的换行和分句交给翻译服务处理；
+// 源语言为 auto 时对整个输入检测一次，避免各段落检测为不同的语言。HTML 整体翻译，Markdown 只翻译正文
+func translateText(ctx context.Context, fromLang, toLang, text, format string) (string, error) {
+	switch format {
+	case "html":
+		return services.TranslateWithPivot(ctx, fromLang, toLang, text, true)
+	case "markdown":
+		return services.TranslateMarkdown(ctx, fromLang, toLang, text, nil)
+	}
+
+	if fromLang == "auto" {
+		if lang := services.DetectLanguage(text); lang != "" {
+			fromLang = lang
+		}
+	}
+	return translateParagraphs(text, func(paragraphs []string) ([]string, []error) {
+		return services.TranslateMany(ctx, fromLang, toLang, paragraphs, false)
+	})
+}
+
+// paragraphPattern 匹配一个段落：连续的非空行，不含首尾空白
+var paragraphPattern = regexp.MustCompile(`\S(?:[^\n]*\S)?(?:[ \t\r]*\n[ \t\r]*\S(?:[^\n]*\S)?)*`)
+
+// translateParagraphs 翻译 text 中的各个段落，段落之间的空行和首尾空白原样保留
+func translateParagraphs(text string, translate func([]string) ([]string, []error)) (string, error) {
+	spans := paragraphPattern.FindAllStringIndex(text, -1)
+	paragraphs := make([]string, len(spans))
+	for i, span := range spans {
+		paragraphs[i] = text[span[0]:span[1]]
+	}
+
+	results, errs := translate(paragraphs)
+	if err := services.FirstError(errs); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	last := 0
+	for i, span := range spans {
+		sb.WriteString(text[last:span[0]])
+		sb.WriteString(results[i])
+		last = span[1]
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), nil
+}
@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/config"
+)
+
+func TestNewFlagSetInheritsGlobalFlags(t *testing.T) {
+	config.GetConfig()
+
+	fs := newFlagSet("test")
+	assert.NotNil(t, fs.Lookup("model-dir"))
+	assert.NotNil(t, fs.Lookup("offline"))
+}
+
+func TestRunTranslateRequiresTarget(t *testing.T) {
+	err := RunTranslate([]string{"--from", "en"})
+	assert.EqualError(t, err, "--to is required")
+}
+
+func TestRunTranslateFormat(t *testing.T) {
+	err := RunTranslate([]string{"--to", "de", "--format", "rst"})
+	assert.EqualError(t, err, "unsupported format: rst")
+}
+
+func TestReadInputFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	require.NoError(t, os.WriteFile(path, []byte("Hello\nWorld\n"), 0644))
+
+	text, err := readInput(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello\nWorld\n", text)
+
+	_, err = readInput(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestTranslateParagraphs(t *testing.T) {
+	text := "\nThis paragraph is hard-wrapped\nat forty columns, mid sentence.\n\n  \nSecond paragraph.\n"
+
+	var got []string
+	result, err := translateParagraphs(text, func(paragraphs []string) ([]string, []error) {
+		got = paragraphs
+		out := make([]string, len(paragraphs))
+		for i, p := range paragraphs {
+			out[i] = strings.ToUpper(p)
+		}
+		return out, make([]error, len(paragraphs))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"This paragraph is hard-wrapped\nat forty columns, mid sentence.", "Second paragraph."}, got)
+	assert.Equal(t, "\nTHIS PARAGRAPH IS HARD-WRAPPED\nAT FORTY COLUMNS, MID SENTENCE.\n\n  \nSECOND PARAGRAPH.\n", result)
+
+	_, err = translateParagraphs("a\n\nb", func(paragraphs []string) ([]string, []error) {
+		return make([]string, 2), []error{nil, errors.New("failed")}
+	})
+	assert.ErrorContains(t, err, "item 1: failed")
+
+	result, err = translateParagraphs("one\r\ntwo \r\n\r\nthree", func(paragraphs []string) ([]string, []error) {
+		assert.Equal(t, []string{"one\r\ntwo", "three"}, paragraphs)
+		return []string{"1", "3"}, make([]error, 2)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1 \r\n\r\n3", result)
+}
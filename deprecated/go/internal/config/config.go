@@ -4,6 +4,7 @@ import (
 	"flag"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/xxnuo/MTranServer/internal/utils"
 )
@@ -14,25 +15,83 @@ type Config struct {
 	ConfigDir string
 	ModelDir  string
 
+	// ConfigFile 可选的 YAML/TOML 配置文件路径
+	ConfigFile string
+
 	Host               string
 	Port               string
 	EnableWebUI        bool
 	EnableOfflineMode  bool
 	WorkerIdleTimeout  int
 	WorkersPerLanguage int
+	WorkerMaxInFlight  int
 	APIToken           string
+	AdminToken         string
+	// KeysFile 可选的 JSON 密钥文件，定义多个命名 API 密钥及其权限
+	KeysFile string
+
+	// RateLimits 每个客户端（API 密钥或 IP）的默认限流和字符配额
+	RateLimits RateLimits
+
+	CacheSize    int
+	CacheTTL     int
+	CachePersist bool
+
+	// Protect 翻译前替换为标记、翻译后原样还原的内置规则，逗号分隔，none 表示不启用
+	Protect string
+	// ProtectPatterns 额外需要保护的正则表达式，只能在配置文件中设置
+	ProtectPatterns []string
+
+	// SplitSentences 默认分句模式，取值与 DeepL 的 split_sentences 相同：0 不分句，1 按标点和换行分句，nonewlines 只按标点分句
+	SplitSentences string
+
+	// Pairs 按语言对覆盖 worker 参数，键为 "from-to"
+	Pairs map[string]PairConfig
+
+	// mu 保护可热更新的字段
+	mu sync.RWMutex
+}
+
+// PairConfig 单个语言对的覆盖配置，零值表示使用全局配置
+type PairConfig struct {
+	WorkersPerLanguage int
+	WorkerIdleTimeout  int
+	WorkerMaxInFlight  int
 }
 
+// RateLimits 限流和配额设置，0 表示不限制
+type RateLimits struct {
+	RequestsPerSecond   int
+	CharactersPerMinute int
+	DailyCharacters     int
+	MonthlyCharacters   int
+}
+
+// DefaultProtect 默认不启用保护规则，由 -protect、MT_PROTECT 或配置文件开启
+const DefaultProtect = "none"
+
+// ProtectRules 可在 Protect 中使用的内置规则
+var ProtectRules = []string{"url", "email", "identifier", "number", "template", "printf"}
+
 var (
 	GlobalConfig *Config = nil
 )
 
-// GetConfig 加载配置，优先级：命令行参数 > 环境变量 > 默认值
+// GetConfig 加载配置，优先级：命令行参数 > 环境变量 > 配置文件 > 默认值。
+// 配置文件在参数解析后由 Load 合并
 func GetConfig() *Config {
 	if GlobalConfig != nil {
 		return GlobalConfig
 	}
 	cfg := &Config{}
+	bindFlags(flag.CommandLine, cfg)
+
+	GlobalConfig = cfg
+	return cfg
+}
+
+// bindFlags 将所有配置项注册到 fs，默认值取自环境变量
+func bindFlags(fs *flag.FlagSet, cfg *Config) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		panic(err)
@@ -41,17 +100,27 @@ func GetConfig() *Config {
 	cfg.ConfigDir = filepath.Join(cfg.HomeDir, "server")
 	cfg.ModelDir = filepath.Join(cfg.HomeDir, "models")
 
-	flag.StringVar(&cfg.LogLevel, "log-level", utils.GetEnv("MT_LOG_LEVEL", "warn"), "Log level (debug, info, warn, error)")
-	flag.StringVar(&cfg.ConfigDir, "config-dir", utils.GetEnv("MT_CONFIG_DIR", cfg.ConfigDir), "Config directory")
-	flag.StringVar(&cfg.ModelDir, "model-dir", utils.GetEnv("MT_MODEL_DIR", cfg.ModelDir), "Model directory")
-	flag.StringVar(&cfg.Host, "host", utils.GetEnv("MT_HOST", "0.0.0.0"), "Server host address")
-	flag.StringVar(&cfg.Port, "port", utils.GetEnv("MT_PORT", "8989"), "Server port")
-	flag.BoolVar(&cfg.EnableWebUI, "ui", utils.GetBoolEnv("MT_ENABLE_UI", true), "Enable web UI")
-	flag.BoolVar(&cfg.EnableOfflineMode, "offline", utils.GetBoolEnv("MT_OFFLINE", false), "Enable offline mode")
-	flag.IntVar(&cfg.WorkerIdleTimeout, "worker-idle-timeout", utils.GetIntEnv("MT_WORKER_IDLE_TIMEOUT", 60), "Worker idle timeout in seconds")
-	flag.IntVar(&cfg.WorkersPerLanguage, "workers-per-language", utils.GetIntEnv("MT_WORKERS_PER_LANGUAGE", 1), "Number of workers per language pair")
-	flag.StringVar(&cfg.APIToken, "api-token", utils.GetEnv("MT_API_TOKEN", ""), "API access token")
-
-	GlobalConfig = cfg
-	return cfg
+	fs.StringVar(&cfg.ConfigFile, "config", utils.GetEnv("MT_CONFIG", ""), "Config file path (YAML or TOML)")
+	fs.StringVar(&cfg.LogLevel, "log-level", utils.GetEnv("MT_LOG_LEVEL", "warn"), "Log level (debug, info, warn, error)")
+	fs.StringVar(&cfg.ConfigDir, "config-dir", utils.GetEnv("MT_CONFIG_DIR", cfg.ConfigDir), "Config directory")
+	fs.StringVar(&cfg.ModelDir, "model-dir", utils.GetEnv("MT_MODEL_DIR", cfg.ModelDir), "Model directory")
+	fs.StringVar(&cfg.Host, "host", utils.GetEnv("MT_HOST", "0.0.0.0"), "Server host address")
+	fs.StringVar(&cfg.Port, "port", utils.GetEnv("MT_PORT", "8989"), "Server port")
+	fs.BoolVar(&cfg.EnableWebUI, "ui", utils.GetBoolEnv("MT_ENABLE_UI", true), "Enable web UI")
+	fs.BoolVar(&cfg.EnableOfflineMode, "offline", utils.GetBoolEnv("MT_OFFLINE", false), "Enable offline mode")
+	fs.IntVar(&cfg.WorkerIdleTimeout, "worker-idle-timeout", utils.GetIntEnv("MT_WORKER_IDLE_TIMEOUT", 60), "Worker idle timeout in seconds")
+	fs.IntVar(&cfg.WorkersPerLanguage, "workers-per-language", utils.GetIntEnv("MT_WORKERS_PER_LANGUAGE", 1), "Number of workers per language pair")
+	fs.IntVar(&cfg.WorkerMaxInFlight, "worker-max-inflight", utils.GetIntEnv("MT_WORKER_MAX_INFLIGHT", 4), "Maximum concurrent requests per worker connection")
+	fs.StringVar(&cfg.APIToken, "api-token", utils.GetEnv("MT_API_TOKEN", ""), "API access token")
+	fs.StringVar(&cfg.AdminToken, "admin-token", utils.GetEnv("MT_ADMIN_TOKEN", ""), "Admin API access token (defaults to API token, admin API is disabled when neither is set)")
+	fs.StringVar(&cfg.KeysFile, "keys-file", utils.GetEnv("MT_KEYS_FILE", ""), "API keys file (JSON) with per-key scopes, language pairs and expiry")
+	fs.IntVar(&cfg.RateLimits.RequestsPerSecond, "rate-limit-rps", utils.GetIntEnv("MT_RATE_LIMIT_RPS", 0), "Requests per second per API key or client IP (0 to disable)")
+	fs.IntVar(&cfg.RateLimits.CharactersPerMinute, "rate-limit-chars", utils.GetIntEnv("MT_RATE_LIMIT_CHARS", 0), "Characters per minute per API key or client IP (0 to disable)")
+	fs.IntVar(&cfg.RateLimits.DailyCharacters, "quota-daily", utils.GetIntEnv("MT_QUOTA_DAILY", 0), "Daily character quota per API key or client IP (0 to disable)")
+	fs.IntVar(&cfg.RateLimits.MonthlyCharacters, "quota-monthly", utils.GetIntEnv("MT_QUOTA_MONTHLY", 0), "Monthly character quota per API key or client IP (0 to disable)")
+	fs.IntVar(&cfg.CacheSize, "cache-size", utils.GetIntEnv("MT_CACHE_SIZE", 10000), "Maximum number of cached translations (0 to disable)")
+	fs.IntVar(&cfg.CacheTTL, "cache-ttl", utils.GetIntEnv("MT_CACHE_TTL", 86400), "Cached translation TTL in seconds (0 for no expiry)")
+	fs.BoolVar(&cfg.CachePersist, "cache-persist", utils.GetBoolEnv("MT_CACHE_PERSIST", false), "Persist translation cache to config directory")
+	fs.StringVar(&cfg.SplitSentences, "split-sentences", utils.GetEnv("MT_SPLIT_SENTENCES", "0"), "Split plain text into sentences before translation: 0, 1 or nonewlines")
+	fs.StringVar(&cfg.Protect, "protect", utils.GetEnv("MT_PROTECT", DefaultProtect), "Comma-separated spans kept untranslated: url, email, identifier, number, template, printf or none")
 }
@@ -0,0 +1,456 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// envNames 可由配置文件设置的配置项及其环境变量，键为命令行参数名。
+// 配置文件中的键名为参数名中的 "-" 替换为 "_"，例如 worker_idle_timeout
+var envNames = map[string]string{
+	"log-level":            "MT_LOG_LEVEL",
+	"config-dir":           "MT_CONFIG_DIR",
+	"model-dir":            "MT_MODEL_DIR",
+	"host":                 "MT_HOST",
+	"port":                 "MT_PORT",
+	"ui":                   "MT_ENABLE_UI",
+	"offline":              "MT_OFFLINE",
+	"worker-idle-timeout":  "MT_WORKER_IDLE_TIMEOUT",
+	"workers-per-language": "MT_WORKERS_PER_LANGUAGE",
+	"worker-max-inflight":  "MT_WORKER_MAX_INFLIGHT",
+	"api-token":            "MT_API_TOKEN",
+	"admin-token":          "MT_ADMIN_TOKEN",
+	"keys-file":            "MT_KEYS_FILE",
+	"rate-limit-rps":       "MT_RATE_LIMIT_RPS",
+	"rate-limit-chars":     "MT_RATE_LIMIT_CHARS",
+	"quota-daily":          "MT_QUOTA_DAILY",
+	"quota-monthly":        "MT_QUOTA_MONTHLY",
+	"cache-size":           "MT_CACHE_SIZE",
+	"cache-ttl":            "MT_CACHE_TTL",
+	"cache-persist":        "MT_CACHE_PERSIST",
+	"protect":              "MT_PROTECT",
+	"split-sentences":      "MT_SPLIT_SENTENCES",
+}
+
+var (
+	loadMu      sync.Mutex
+	loadedFlags *flag.FlagSet
+	explicit    map[string]bool
+)
+
+type fileConfig struct {
+	options         map[string]string
+	pairs           map[string]PairConfig
+	protectPatterns []string
+}
+
+// Load 在命令行参数解析后合并配置文件并校验最终配置。
+// fs 为已解析的参数集，其中显式设置的参数优先于环境变量和配置文件
+func Load(fs *flag.FlagSet) error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	cfg := GetConfig()
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	resolved, resolvedFlags, err := resolve(cfg.ConfigFile, fs, set)
+	if err != nil {
+		return err
+	}
+
+	for name := range envNames {
+		if f := fs.Lookup(name); f != nil {
+			if err := f.Value.Set(resolvedFlags.Lookup(name).Value.String()); err != nil {
+				return fmt.Errorf("invalid value for %s: %w", name, err)
+			}
+		}
+	}
+
+	cfg.mu.Lock()
+	cfg.Pairs = resolved.Pairs
+	cfg.ProtectPatterns = resolved.ProtectPatterns
+	cfg.mu.Unlock()
+
+	loadedFlags = fs
+	explicit = set
+	return nil
+}
+
+// Reload 重新读取配置文件，只更新可在运行时安全修改的配置项：
+// 日志级别、空闲超时、每个语言对的 worker 数量、API 令牌、密钥文件、限流配额、保护规则、分句模式和语言对覆盖配置
+func Reload() error {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	cfg := GetConfig()
+	if loadedFlags == nil {
+		return errors.New("config has not been loaded")
+	}
+
+	resolved, _, err := resolve(cfg.ConfigFile, loadedFlags, explicit)
+	if err != nil {
+		return err
+	}
+
+	cfg.mu.Lock()
+	cfg.LogLevel = resolved.LogLevel
+	cfg.WorkerIdleTimeout = resolved.WorkerIdleTimeout
+	cfg.WorkersPerLanguage = resolved.WorkersPerLanguage
+	cfg.APIToken = resolved.APIToken
+	cfg.AdminToken = resolved.AdminToken
+	cfg.KeysFile = resolved.KeysFile
+	cfg.RateLimits = resolved.RateLimits
+	cfg.Protect = resolved.Protect
+	cfg.ProtectPatterns = resolved.ProtectPatterns
+	cfg.SplitSentences = resolved.SplitSentences
+	cfg.Pairs = resolved.Pairs
+	cfg.mu.Unlock()
+
+	return nil
+}
+
+// resolve 按 命令行参数 > 环境变量 > 配置文件 > 默认值 计算完整配置并校验
+func resolve(path string, fs *flag.FlagSet, set map[string]bool) (*Config, *flag.FlagSet, error) {
+	resolved := &Config{}
+	resolvedFlags := flag.NewFlagSet("config", flag.ContinueOnError)
+	bindFlags(resolvedFlags, resolved)
+
+	if path != "" {
+		fc, err := readConfigFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for name, value := range fc.options {
+			if set[name] || os.Getenv(envNames[name]) != "" {
+				continue
+			}
+			if err := resolvedFlags.Set(name, value); err != nil {
+				return nil, nil, fmt.Errorf("config file %s: invalid value %q for %s", path, value, configKey(name))
+			}
+		}
+		resolved.Pairs = fc.pairs
+		resolved.ProtectPatterns = fc.protectPatterns
+	}
+
+	for name := range set {
+		if _, ok := envNames[name]; !ok {
+			continue
+		}
+		if err := resolvedFlags.Lookup(name).Value.Set(fs.Lookup(name).Value.String()); err != nil {
+			return nil, nil, fmt.Errorf("invalid value for --%s: %w", name, err)
+		}
+	}
+
+	if err := resolved.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return resolved, resolvedFlags, nil
+}
+
+func configKey(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+func readConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var raw map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	fc := &fileConfig{
+		options: make(map[string]string),
+	}
+
+	for key, value := range raw {
+		if key == "pairs" {
+			pairs, err := parsePairs(value)
+			if err != nil {
+				return nil, fmt.Errorf("config file %s: %w", path, err)
+			}
+			fc.pairs = pairs
+			continue
+		}
+		if key == "protect_patterns" {
+			patterns, err := parseStrings(value)
+			if err != nil {
+				return nil, fmt.Errorf("config file %s: protect_patterns %w", path, err)
+			}
+			fc.protectPatterns = patterns
+			continue
+		}
+
+		name := strings.ReplaceAll(key, "_", "-")
+		if _, ok := envNames[name]; !ok {
+			return nil, fmt.Errorf("config file %s: unknown option %q", path, key)
+		}
+
+		switch value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("config file %s: option %q must be a single value", path, key)
+		}
+		fc.options[name] = fmt.Sprint(value)
+	}
+
+	return fc, nil
+}
+
+func parsePairs(value interface{}) (map[string]PairConfig, error) {
+	entries, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("pairs must be a table keyed by language pair, e.g. en-zh-Hans")
+	}
+
+	pairs := make(map[string]PairConfig, len(entries))
+	for pair, entry := range entries {
+		options, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("pairs.%s must be a table", pair)
+		}
+
+		var pc PairConfig
+		for key, v := range options {
+			n, ok := toInt(v)
+			if !ok {
+				return nil, fmt.Errorf("pairs.%s.%s must be an integer", pair, key)
+			}
+			switch key {
+			case "workers_per_language":
+				pc.WorkersPerLanguage = n
+			case "worker_idle_timeout":
+				pc.WorkerIdleTimeout = n
+			case "worker_max_inflight":
+				pc.WorkerMaxInFlight = n
+			default:
+				return nil, fmt.Errorf("pairs.%s: unknown option %q", pair, key)
+			}
+		}
+		pairs[pair] = pc
+	}
+	return pairs, nil
+}
+
+func parseStrings(value interface{}) ([]string, error) {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("must be a list of strings")
+	}
+
+	list := make([]string, len(items))
+	for i, item := range items {
+		s, ok := item.(string)
+		if !ok {
+			return nil, errors.New("must be a list of strings")
+		}
+		list[i] = s
+	}
+	return list, nil
+}
+
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		if n == math.Trunc(n) {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// Validate 校验配置，返回第一个不合法的配置项
+func (c *Config) Validate() error {
+	switch strings.ToLower(c.LogLevel) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		return fmt.Errorf("invalid config: log_level must be one of debug, info, warn, error, got %q", c.LogLevel)
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("invalid config: port must be between 1 and 65535, got %q", c.Port)
+	}
+
+	if c.WorkerIdleTimeout < 1 {
+		return fmt.Errorf("invalid config: worker_idle_timeout must be at least 1 second, got %d", c.WorkerIdleTimeout)
+	}
+	if c.WorkersPerLanguage < 1 {
+		return fmt.Errorf("invalid config: workers_per_language must be at least 1, got %d", c.WorkersPerLanguage)
+	}
+	if c.WorkerMaxInFlight < 1 {
+		return fmt.Errorf("invalid config: worker_max_inflight must be at least 1, got %d", c.WorkerMaxInFlight)
+	}
+	if c.RateLimits.RequestsPerSecond < 0 || c.RateLimits.CharactersPerMinute < 0 ||
+		c.RateLimits.DailyCharacters < 0 || c.RateLimits.MonthlyCharacters < 0 {
+		return errors.New("invalid config: rate limits and quotas must not be negative")
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("invalid config: cache_size must not be negative, got %d", c.CacheSize)
+	}
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("invalid config: cache_ttl must not be negative, got %d", c.CacheTTL)
+	}
+
+	switch c.SplitSentences {
+	case "0", "1", "nonewlines":
+	default:
+		return fmt.Errorf("invalid config: split_sentences must be one of 0, 1, nonewlines, got %q", c.SplitSentences)
+	}
+
+	for _, rule := range strings.Split(c.Protect, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule != "" && rule != "none" && !slices.Contains(ProtectRules, rule) {
+			return fmt.Errorf("invalid config: unknown protect rule %q, must be one of %s", rule, strings.Join(ProtectRules, ", "))
+		}
+	}
+	for _, pattern := range c.ProtectPatterns {
+		if pattern == "" {
+			return errors.New("invalid config: protect_patterns must not contain empty patterns")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid config: protect_patterns: %w", err)
+		}
+	}
+
+	for pair, pc := range c.Pairs {
+		from, to, ok := strings.Cut(pair, "-")
+		if !ok || from == "" || to == "" {
+			return fmt.Errorf("invalid config: pairs key %q must be in the form from-to", pair)
+		}
+		if pc.WorkersPerLanguage < 0 || pc.WorkerIdleTimeout < 0 || pc.WorkerMaxInFlight < 0 {
+			return fmt.Errorf("invalid config: pairs.%s values must not be negative", pair)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) pairConfig(fromLang, toLang string) PairConfig {
+	return c.Pairs[fmt.Sprintf("%s-%s", fromLang, toLang)]
+}
+
+// GetLogLevel 返回当前日志级别
+func (c *Config) GetLogLevel() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.LogLevel
+}
+
+// GetAPIToken 返回当前 API 令牌
+func (c *Config) GetAPIToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.APIToken
+}
+
+// GetAdminToken 返回管理接口令牌，未设置时使用 API 令牌
+func (c *Config) GetAdminToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.AdminToken != "" {
+		return c.AdminToken
+	}
+	return c.APIToken
+}
+
+// GetKeysFile 返回当前密钥文件路径
+func (c *Config) GetKeysFile() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.KeysFile
+}
+
+// GetRateLimits 返回每个客户端的默认限流和配额
+func (c *Config) GetRateLimits() RateLimits {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.RateLimits
+}
+
+// GetProtect 返回启用的内置保护规则和额外的正则表达式
+func (c *Config) GetProtect() ([]string, []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var rules []string
+	for _, rule := range strings.Split(c.Protect, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule != "" && rule != "none" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, c.ProtectPatterns
+}
+
+// GetSplitSentences 返回默认分句模式
+func (c *Config) GetSplitSentences() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SplitSentences
+}
+
+// GetWorkerIdleTimeout 返回语言对的 worker 空闲超时（秒）
+func (c *Config) GetWorkerIdleTimeout(fromLang, toLang string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if pc := c.pairConfig(fromLang, toLang); pc.WorkerIdleTimeout > 0 {
+		return pc.WorkerIdleTimeout
+	}
+	return c.WorkerIdleTimeout
+}
+
+// GetWorkersPerLanguage 返回语言对引擎池的 worker 数量
+func (c *Config) GetWorkersPerLanguage(fromLang, toLang string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if pc := c.pairConfig(fromLang, toLang); pc.WorkersPerLanguage > 0 {
+		return pc.WorkersPerLanguage
+	}
+	return c.WorkersPerLanguage
+}
+
+// GetWorkerMaxInFlight 返回语言对每个 worker 连接的最大并发请求数
+func (c *Config) GetWorkerMaxInFlight(fromLang, toLang string) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if pc := c.pairConfig(fromLang, toLang); pc.WorkerMaxInFlight > 0 {
+		return pc.WorkerMaxInFlight
+	}
+	return c.WorkerMaxInFlight
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestConfig(t *testing.T, args ...string) (*Config, *flag.FlagSet) {
+	t.Helper()
+
+	oldConfig := GlobalConfig
+	t.Cleanup(func() {
+		GlobalConfig = oldConfig
+		loadedFlags = nil
+		explicit = nil
+	})
+
+	cfg := &Config{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	bindFlags(fs, cfg)
+	require.NoError(t, fs.Parse(args))
+
+	GlobalConfig = cfg
+	return cfg, fs
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadYAMLPrecedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+log_level: debug
+port: 9000
+host: 127.0.0.1
+worker_idle_timeout: 120
+ui: false
+pairs:
+  en-zh-Hans:
+    workers_per_language: 3
+`)
+	t.Setenv("MT_HOST", "10.0.0.1")
+
+	cfg, fs := newTestConfig(t, "--config", path, "--port", "7000")
+	require.NoError(t, Load(fs))
+
+	assert.Equal(t, "7000", cfg.Port, "flag should override file")
+	assert.Equal(t, "10.0.0.1", cfg.Host, "env should override file")
+	assert.Equal(t, "debug", cfg.LogLevel)
+	assert.Equal(t, 120, cfg.WorkerIdleTimeout)
+	assert.False(t, cfg.EnableWebUI)
+	assert.Equal(t, 3, cfg.GetWorkersPerLanguage("en", "zh-Hans"))
+	assert.Equal(t, 1, cfg.GetWorkersPerLanguage("en", "de"))
+}
+
+func TestLoadTOML(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+cache_size = 500
+api_token = "secret"
+
+[pairs.en-de]
+worker_idle_timeout = 30
+`)
+
+	cfg, fs := newTestConfig(t, "--config", path)
+	require.NoError(t, Load(fs))
+
+	assert.Equal(t, 500, cfg.CacheSize)
+	assert.Equal(t, "secret", cfg.GetAPIToken())
+	assert.Equal(t, "secret", cfg.GetAdminToken())
+	assert.Equal(t, 30, cfg.GetWorkerIdleTimeout("en", "de"))
+	assert.Equal(t, 60, cfg.GetWorkerIdleTimeout("en", "ja"))
+}
+
+func TestLoadProtect(t *testing.T) {
+	cfg, fs := newTestConfig(t)
+	require.NoError(t, Load(fs))
+	rules, patterns := cfg.GetProtect()
+	assert.Empty(t, rules)
+	assert.Empty(t, patterns)
+
+	path := writeConfigFile(t, "config.yaml", "protect: url, email\nprotect_patterns:\n  - 'TICKET-\\d+'\n")
+
+	cfg, fs = newTestConfig(t, "--config", path)
+	require.NoError(t, Load(fs))
+
+	rules, patterns = cfg.GetProtect()
+	assert.Equal(t, []string{"url", "email"}, rules)
+	assert.Equal(t, []string{`TICKET-\d+`}, patterns)
+
+	cfg, fs = newTestConfig(t, "--protect", "none")
+	require.NoError(t, Load(fs))
+	rules, _ = cfg.GetProtect()
+	assert.Empty(t, rules)
+}
+
+func TestLoadSplitSentences(t *testing.T) {
+	cfg, fs := newTestConfig(t)
+	require.NoError(t, Load(fs))
+	assert.Equal(t, "0", cfg.GetSplitSentences())
+
+	cfg, fs = newTestConfig(t, "--split-sentences", "nonewlines")
+	require.NoError(t, Load(fs))
+	assert.Equal(t, "nonewlines", cfg.GetSplitSentences())
+}
+
+func TestLoadRejectsInvalidConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		errMsg  string
+	}{
+		{"unknown option", "c.yaml", "worker_count: 2\n", `unknown option "worker_count"`},
+		{"invalid type", "c.yaml", "cache_size: lots\n", "invalid value"},
+		{"validation", "c.yaml", "workers_per_language: 0\n", "workers_per_language must be at least 1"},
+		{"invalid port", "c.toml", "port = 70000\n", "port must be between 1 and 65535"},
+		{"unsupported format", "c.json", "{}", "unsupported config file format"},
+		{"invalid pair key", "c.yaml", "pairs:\n  english:\n    workers_per_language: 2\n", "must be in the form from-to"},
+		{"unknown protect rule", "c.yaml", "protect: url,phone\n", `unknown protect rule "phone"`},
+		{"invalid protect pattern", "c.yaml", "protect_patterns:\n  - \"[a-z\"\n", "protect_patterns"},
+		{"protect patterns not a list", "c.yaml", "protect_patterns: abc\n", "must be a list of strings"},
+		{"invalid split sentences", "c.yaml", "split_sentences: always\n", "split_sentences must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.file, tt.content)
+			_, fs := newTestConfig(t, "--config", path)
+
+			err := Load(fs)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestReloadUpdatesLiveFields(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "log_level: info\napi_token: old\ncache_size: 100\n")
+
+	cfg, fs := newTestConfig(t, "--config", path, "--worker-idle-timeout", "90")
+	require.NoError(t, Load(fs))
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: error\napi_token: new\ncache_size: 200\nworker_idle_timeout: 10\n"), 0644))
+	require.NoError(t, Reload())
+
+	assert.Equal(t, "error", cfg.GetLogLevel())
+	assert.Equal(t, "new", cfg.GetAPIToken())
+	assert.Equal(t, 90, cfg.GetWorkerIdleTimeout("en", "de"), "explicit flag should survive reload")
+	assert.Equal(t, 100, cfg.CacheSize, "cache size is not reloaded live")
+
+	require.NoError(t, os.WriteFile(path, []byte("log_level: verbose\n"), 0644))
+	assert.Error(t, Reload())
+	assert.Equal(t, "error", cfg.GetLogLevel(), "invalid reload should keep current settings")
+}
@@ -63,45 +63,64 @@ const docTemplate = `{
                 }
             }
         },
-        "/deepl": {
-            "post": {
-                "description": "兼容 DeepL API v2 的翻译接口",
-                "consumes": [
+        "/admin/engines": {
+            "get": {
+                "description": "返回所有语言对引擎池及其 worker 的端口、PID、状态、最后使用时间和空闲截止时间",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "管理"
+                ],
+                "summary": "列出已加载的引擎",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/admin/engines/{from}/{to}": {
+            "post": {
+                "description": "预先下载模型并启动指定语言对的引擎池，需要中转时同时启动两段引擎",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "插件"
+                    "管理"
                 ],
-                "summary": "DeepL 翻译兼容接口",
+                "summary": "预热引擎",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "API Token",
-                        "name": "token",
-                        "in": "query"
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "DeepL 翻译请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.DeeplTranslateRequest"
-                        }
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.DeeplTranslateResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -109,8 +128,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -127,48 +146,44 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/google/language/translate/v2": {
-            "post": {
-                "description": "兼容 Google Translate API v2 的翻译接口",
-                "consumes": [
-                    "application/json"
-                ],
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "停止指定语言对的引擎池并释放所有 worker",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "插件"
+                    "管理"
                 ],
-                "summary": "Google 翻译兼容接口",
+                "summary": "停止引擎",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "API Key",
-                        "name": "key",
-                        "in": "query"
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "Google 翻译请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.GoogleTranslateRequest"
-                        }
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handlers.GoogleTranslateResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -176,92 +191,65 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                    {
+                        "ApiKeyQuery": []
                     }
-                }
+                ]
             }
         },
-        "/google/translate_a/single": {
+        "/admin/engines/{from}/{to}/workers/{port}/logs": {
             "get": {
-                "description": "兼容 Google translate_a/single 的翻译接口",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "返回单个 worker 最近输出的日志",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "插件"
+                    "管理"
                 ],
-                "summary": "Google translate_a/single 兼容接口",
+                "summary": "获取 worker 日志",
                 "parameters": [
                     {
                         "type": "string",
-                        "default": "gtx",
-                        "description": "客户端类型",
-                        "name": "client",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "en",
-                        "description": "源语言代码",
-                        "name": "sl",
-                        "in": "query",
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "example": "zh-CN",
-                        "description": "目标语言代码",
-                        "name": "tl",
-                        "in": "query",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "default": "t",
-                        "description": "数据类型",
-                        "name": "dt",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "example": "Hello, world!",
-                        "description": "待翻译文本",
-                        "name": "q",
-                        "in": "query",
+                        "type": "integer",
+                        "description": "worker 端口",
+                        "name": "port",
+                        "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "API Key",
-                        "name": "key",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {}
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "400": {
@@ -273,62 +261,67 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
+                    {
+                        "ApiKeyQuery": []
                     }
-                }
+                ]
             }
         },
-        "/hcfy": {
+        "/admin/engines/{from}/{to}/workers/{port}/restart": {
             "post": {
-                "description": "兼容划词翻译自定义翻译源接口",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "在原端口上重启引擎池中的单个 worker",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "插件"
+                    "管理"
                 ],
-                "summary": "划词翻译兼容接口",
+                "summary": "重启 worker",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "API Token",
-                        "name": "token",
-                        "in": "query"
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "划词翻译请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.HcfyTranslateRequest"
-                        }
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "worker 端口",
+                        "name": "port",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.HcfyTranslateResponse"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -340,8 +333,17 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -358,22 +360,37 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
             }
         },
-        "/health": {
+        "/admin/models": {
             "get": {
-                "description": "检查服务是否正常运行",
+                "description": "返回所有可用语言对模型的本地状态（downloaded/missing/outdated）和占用空间",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "系统"
+                    "管理"
                 ],
-                "summary": "健康检查",
+                "summary": "列出模型",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -381,46 +398,53 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
             }
         },
-        "/imme": {
-            "post": {
-                "description": "为沉浸式翻译插件提供的翻译接口",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/models/{from}/{to}": {
+            "delete": {
+                "description": "停止使用该模型的引擎并删除本地模型目录",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "插件"
+                    "管理"
                 ],
-                "summary": "沉浸式翻译插件接口",
+                "summary": "删除模型",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "API Token",
-                        "name": "token",
-                        "in": "query"
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "沉浸式翻译请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.ImmeTranslateRequest"
-                        }
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.ImmeTranslateResponse"
-                        }
-                    },
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -430,8 +454,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -439,8 +463,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -448,57 +472,105 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
             }
         },
-        "/kiss": {
-            "post": {
-                "description": "为简约翻译插件提供的单文本翻译接口",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/models/{from}/{to}/download": {
+            "get": {
+                "description": "返回指定语言对最近一次后台下载任务的状态和已下载字节数",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "插件"
+                    "管理"
                 ],
-                "summary": "简约翻译插件接口（非聚合）",
+                "summary": "查询模型下载进度",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "API Token",
-                        "name": "KEY",
-                        "in": "header"
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "description": "简约翻译请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.KissTranslateRequest"
-                        }
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.KissTranslateResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "在后台下载指定语言对的模型，同一语言对正在下载时返回现有任务",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理"
+                ],
+                "summary": "后台下载模型",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -506,8 +578,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -515,11 +587,7 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
-            }
-        },
-        "/languages": {
-            "get": {
+                },
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -527,25 +595,48 @@ const docTemplate = `{
                     {
                         "ApiKeyQuery": []
                     }
-                ],
-                "description": "返回所有支持的翻译语言代码",
+                ]
+            }
+        },
+        "/admin/models/{from}/{to}/verify": {
+            "post": {
+                "description": "重新计算模型文件的哈希并与 DecompressedHash 比对",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "翻译"
+                    "管理"
+                ],
+                "summary": "校验模型",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "源语言",
+                        "name": "from",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "目标语言",
+                        "name": "to",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取支持的语言列表",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VerifyResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "type": "string"
-                                }
+                                "type": "string"
                             }
                         }
                     },
@@ -558,11 +649,36 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
             }
         },
-        "/translate": {
-            "post": {
+        "/cache/stats": {
+            "get": {
+                "description": "返回翻译结果缓存的容量、命中和未命中次数",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "翻译缓存统计",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -570,8 +686,12 @@ const docTemplate = `{
                     {
                         "ApiKeyQuery": []
                     }
-                ],
-                "description": "翻译单个文本",
+                ]
+            }
+        },
+        "/deepl": {
+            "post": {
+                "description": "兼容 DeepL API v2 的翻译接口，支持通过 glossary_id 使用术语表",
                 "consumes": [
                     "application/json"
                 ],
@@ -579,25 +699,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "翻译"
+                    "插件"
                 ],
-                "summary": "单文本翻译",
+                "summary": "DeepL 翻译兼容接口",
                 "parameters": [
                     {
-                        "description": "翻译请求",
+                        "type": "string",
+                        "description": "API Token",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "DeepL 翻译请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.TranslateRequest"
+                            "$ref": "#/definitions/handlers.DeeplTranslateRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "部分结果模式，单个文本失败时返回原文并附带错误",
+                        "name": "partial_results",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.TranslateResponse"
+                            "$ref": "#/definitions/handlers.DeeplTranslateResponse"
                         }
                     },
                     "400": {
@@ -609,6 +741,51 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "456": {
+                        "description": "",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -621,8 +798,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/translate/batch": {
-            "post": {
+        "/glossaries": {
+            "get": {
+                "description": "按创建时间返回当前密钥可以使用的术语表的信息，不包含词条",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "术语表"
+                ],
+                "summary": "列出术语表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GlossaryListResponse"
+                        }
+                    }
+                },
                 "security": [
                     {
                         "ApiKeyAuth": []
@@ -630,8 +823,10 @@ const docTemplate = `{
                     {
                         "ApiKeyQuery": []
                     }
-                ],
-                "description": "批量翻译多个文本",
+                ]
+            },
+            "post": {
+                "description": "从 TSV 或 CSV 词条创建一个语言对的术语表，翻译时源词条强制使用指定的译文。接口与 DeepL /v2/glossaries 兼容",
                 "consumes": [
                     "application/json"
                 ],
@@ -639,25 +834,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "翻译"
+                    "术语表"
                 ],
-                "summary": "批量翻译",
+                "summary": "创建术语表",
                 "parameters": [
                     {
-                        "description": "批量翻译请求",
+                        "description": "创建术语表请求",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.TranslateBatchRequest"
+                            "$ref": "#/definitions/handlers.CreateGlossaryRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.TranslateBatchResponse"
+                            "$ref": "#/definitions/handlers.GlossaryInfo"
                         }
                     },
                     "400": {
@@ -669,6 +864,15 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -678,155 +882,2469 @@ const docTemplate = `{
                             }
                         }
                     }
-                }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
             }
         },
-        "/version": {
+        "/glossaries/{id}": {
             "get": {
-                "description": "返回当前服务的版本号",
+                "description": "返回术语表的信息，不包含词条",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "系统"
+                    "术语表"
+                ],
+                "summary": "获取术语表信息",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "术语表 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "获取服务版本",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GlossaryInfo"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "删除术语表，之后引用它的翻译请求返回 404",
+                "tags": [
+                    "术语表"
+                ],
+                "summary": "删除术语表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "术语表 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            },
+            "patch": {
+                "description": "修改术语表名称或替换全部词条，语言对不能修改。正在进行的翻译继续使用修改前的词条",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "术语表"
+                ],
+                "summary": "修改术语表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "术语表 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "修改术语表请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateGlossaryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GlossaryInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/glossaries/{id}/entries": {
+            "get": {
+                "description": "以 TSV 格式返回术语表的全部词条",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "术语表"
+                ],
+                "summary": "获取术语表词条",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "术语表 ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/glossary-language-pairs": {
+            "get": {
+                "description": "返回所有可翻译的语言对，包括经英语中转的语言对",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "术语表"
+                ],
+                "summary": "可以创建术语表的语言对",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GlossaryLanguagePairsResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/google/language/translate/v2": {
+            "post": {
+                "description": "兼容 Google Translate API v2 的翻译接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "Google 翻译兼容接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Key",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Google 翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GoogleTranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GoogleTranslateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/google/translate_a/single": {
+            "get": {
+                "description": "兼容 Google translate_a/single 的翻译接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "Google translate_a/single 兼容接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "gtx",
+                        "description": "客户端类型",
+                        "name": "client",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "en",
+                        "description": "源语言代码",
+                        "name": "sl",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "example": "zh-CN",
+                        "description": "目标语言代码",
+                        "name": "tl",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "t",
+                        "description": "数据类型",
+                        "name": "dt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "Hello, world!",
+                        "description": "待翻译文本",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API Key",
+                        "name": "key",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {}
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/hcfy": {
+            "post": {
+                "description": "兼容划词翻译自定义翻译源接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "划词翻译兼容接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Token",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "划词翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HcfyTranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HcfyTranslateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "检查服务是否正常运行",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "健康检查",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/imme": {
+            "post": {
+                "description": "为沉浸式翻译插件提供的翻译接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "沉浸式翻译插件接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Token",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "description": "沉浸式翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImmeTranslateRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "部分结果模式，单个文本失败时返回原文并附带错误",
+                        "name": "partial_results",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImmeTranslateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/kiss": {
+            "post": {
+                "description": "为简约翻译插件提供的单文本翻译接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "简约翻译插件接口（非聚合）",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Token",
+                        "name": "KEY",
+                        "in": "header"
+                    },
+                    {
+                        "description": "简约翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.KissTranslateRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "部分结果模式，单个文本失败时返回原文并附带错误",
+                        "name": "partial_results",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.KissTranslateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/languages": {
+            "get": {
+                "description": "返回所有支持的翻译语言代码",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "获取支持的语言列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "type": "string"
+                                }
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/libre/detect": {
+            "post": {
+                "description": "兼容 LibreTranslate 的 /detect 接口，返回检测到的语言及置信度（0-100）",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "LibreTranslate 语言检测兼容接口",
+                "parameters": [
+                    {
+                        "description": "LibreTranslate 检测请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LibreDetectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LibreDetectedLanguage"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/libre/languages": {
+            "get": {
+                "description": "兼容 LibreTranslate 的 /languages 接口，返回每个语言可翻译到的目标语言（包括经英语中转）",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "LibreTranslate 语言列表兼容接口",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LibreLanguage"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/libre/translate": {
+            "post": {
+                "description": "兼容 LibreTranslate 的 /translate 接口，支持 JSON 和表单请求，q 可以是字符串或数组",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "LibreTranslate 翻译兼容接口",
+                "parameters": [
+                    {
+                        "description": "LibreTranslate 翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LibreTranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LibreTranslateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/microsoft/detect": {
+            "post": {
+                "description": "兼容 Microsoft Translator v3 的 /detect 接口，score 取值 0-1",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "Microsoft Translator 语言检测兼容接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "3.0",
+                        "description": "API 版本",
+                        "name": "api-version",
+                        "in": "query"
+                    },
+                    {
+                        "description": "待检测文本",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MicrosoftTextItem"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MicrosoftDetectResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/microsoft/languages": {
+            "get": {
+                "description": "兼容 Microsoft Translator v3 的 /languages 接口，仅返回 translation 范围",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "Microsoft Translator 语言列表兼容接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "3.0",
+                        "description": "API 版本",
+                        "name": "api-version",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MicrosoftLanguagesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/microsoft/translate": {
+            "post": {
+                "description": "兼容 Microsoft Translator v3 的 /translate 接口，to 可重复传入多个目标语言，未传 from 时返回检测到的语言",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "Microsoft Translator 翻译兼容接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "3.0",
+                        "description": "API 版本",
+                        "name": "api-version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "源语言代码，不传时自动检测",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "目标语言代码，可传多个",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "plain",
+                        "description": "文本类型 plain 或 html",
+                        "name": "textType",
+                        "in": "query"
+                    },
+                    {
+                        "description": "待翻译文本",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MicrosoftTextItem"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.MicrosoftTranslateResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/translate": {
+            "post": {
+                "description": "翻译单个文本，支持纯文本、HTML 和 Markdown，可以指定术语表",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "单文本翻译",
+                "parameters": [
+                    {
+                        "description": "翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/translate/batch": {
+            "post": {
+                "description": "批量翻译多个文本。默认任一文本失败时整个请求返回 500；partial_results 为 true 时返回其余文本的译文和失败文本的错误",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "批量翻译",
+                "parameters": [
+                    {
+                        "description": "批量翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateBatchRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "部分结果模式，单个文本失败时返回原文并附带错误",
+                        "name": "partial_results",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateBatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/translate/catalog": {
+            "post": {
+                "description": "翻译 gettext PO/POT 或 XLIFF 1.2 文件中未翻译的条目，保护 printf、花括号和 ICU 占位符，机器译文标记为 fuzzy 或 needs-review-translation，保留注释和顺序，返回同格式的文件",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "翻译 PO/XLIFF 目录",
+                "parameters": [
+                    {
+                        "description": "翻译目录请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateCatalogRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/translate/i18n": {
+            "post": {
+                "description": "翻译 JSON（i18next、vue-i18n）或 YAML（Rails）资源文件中的字符串值，键和非字符串值保持不变，保护 {{count}}、{name}、%{var} 等插值和 HTML 标签。提供 existing 时只翻译缺少的键",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "资源文件翻译",
+                "parameters": [
+                    {
+                        "description": "资源文件翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateI18nRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/translate/stream": {
+            "post": {
+                "description": "将长文本按行和句子切分后并行翻译，以 SSE 逐段返回：每段完成后发送 chunk 事件（可能乱序，按 index 和偏移定位），\n失败的分段发送 error 事件，最后发送 done 事件。术语表和分句模式与 /translate 相同，HTML 和 Markdown 文本不切分",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "流式翻译",
+                "parameters": [
+                    {
+                        "description": "翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateStreamChunk"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/translate/subtitle": {
+            "post": {
+                "description": "翻译 SRT 或 WebVTT 字幕中的对白文本，保留时间轴、cue settings、样式标签和行结构，返回同格式的字幕文件",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "字幕翻译",
+                "parameters": [
+                    {
+                        "description": "字幕翻译请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TranslateSubtitleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
+        },
+        "/v1/chat/completions": {
+            "post": {
+                "description": "从翻译插件常用的提示词中识别源语言、目标语言和待翻译文本，返回 chat.completion 对象；stream=true 时按行以 SSE 返回 chat.completion.chunk",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "OpenAI Chat Completions 兼容接口",
+                "parameters": [
+                    {
+                        "description": "Chat Completions 请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OpenAIChatRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OpenAIChatResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/models": {
+            "get": {
+                "description": "返回可用的模型：mtranserver 从提示词中识别目标语言，mtranserver-\u003c语言\u003e 固定目标语言",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "插件"
+                ],
+                "summary": "OpenAI 模型列表兼容接口",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OpenAIModelList"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "返回当前服务的版本号",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "系统"
+                ],
+                "summary": "获取服务版本",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "建立持久连接后发送 JSON 消息 {\"id\",\"type\",\"from\",\"to\",\"text\",\"html\"}，type 默认为 translate。\n发送 {\"id\",\"type\":\"cancel\"} 可取消进行中的请求。结果按完成顺序返回 {\"id\",\"type\":\"result\",\"result\"}，\n失败时返回 {\"id\",\"type\":\"error\",\"error\"}，取消后返回 {\"id\",\"type\":\"cancelled\"}。浏览器可通过 token 参数鉴权",
+                "tags": [
+                    "翻译"
+                ],
+                "summary": "WebSocket 翻译接口",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API Token",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WSTranslateResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    },
+                    {
+                        "ApiKeyQuery": []
+                    }
+                ]
+            }
         }
     },
     "definitions": {
+        "handlers.CreateGlossaryRequest": {
+            "type": "object",
+            "required": [
+                "entries",
+                "name",
+                "source_lang",
+                "target_lang"
+            ],
+            "properties": {
+                "entries": {
+                    "description": "Entries 词条，TSV 为每行 源词条\u003cTab\u003e译文，CSV 取每行前两列",
+                    "type": "string",
+                    "example": "MTranServer\tMTranServer\nterms of service\t服务条款"
+                },
+                "entries_format": {
+                    "type": "string",
+                    "enum": [
+                        "tsv",
+                        "csv"
+                    ],
+                    "example": "tsv"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "产品名称"
+                },
+                "source_lang": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "target_lang": {
+                    "type": "string",
+                    "example": "zh-Hans"
+                }
+            }
+        },
         "handlers.DeeplTranslateRequest": {
             "type": "object",
             "required": [
-                "target_lang",
-                "text"
+                "target_lang",
+                "text"
+            ],
+            "properties": {
+                "context": {
+                    "type": "string"
+                },
+                "enable_beta_languages": {
+                    "type": "boolean"
+                },
+                "formality": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "glossary_id": {
+                    "type": "string"
+                },
+                "ignore_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "model_type": {
+                    "type": "string",
+                    "example": "quality_optimized"
+                },
+                "non_splitting_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "outline_detection": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "partial_results": {
+                    "description": "PartialResults 部分结果模式（扩展字段）：单个文本失败时返回原文并附带 error",
+                    "type": "boolean",
+                    "example": false
+                },
+                "preserve_formatting": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "source_lang": {
+                    "type": "string",
+                    "example": "EN"
+                },
+                "split_sentences": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "splitting_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tag_handling": {
+                    "type": "string",
+                    "example": "xml"
+                },
+                "target_lang": {
+                    "type": "string",
+                    "example": "DE"
+                },
+                "text": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Hello",
+                        " world!"
+                    ]
+                }
+            }
+        },
+        "handlers.DeeplTranslateResponse": {
+            "type": "object",
+            "properties": {
+                "translations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DeeplTranslation"
+                    }
+                }
+            }
+        },
+        "handlers.DeeplTranslation": {
+            "type": "object",
+            "properties": {
+                "detected_source_language": {
+                    "type": "string",
+                    "example": "EN"
+                },
+                "error": {
+                    "$ref": "#/definitions/handlers.ItemError"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Hallo, Welt!"
+                }
+            }
+        },
+        "handlers.GlossaryInfo": {
+            "type": "object",
+            "properties": {
+                "creation_time": {
+                    "type": "string"
+                },
+                "entry_count": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "glossary_id": {
+                    "type": "string",
+                    "example": "def3a26b-3e84-45b3-84ae-0c0aaf3525f7"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "产品名称"
+                },
+                "ready": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "source_lang": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "target_lang": {
+                    "type": "string",
+                    "example": "zh-Hans"
+                }
+            }
+        },
+        "handlers.GlossaryLanguagePair": {
+            "type": "object",
+            "properties": {
+                "source_lang": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "target_lang": {
+                    "type": "string",
+                    "example": "zh-Hans"
+                }
+            }
+        },
+        "handlers.GlossaryLanguagePairsResponse": {
+            "type": "object",
+            "properties": {
+                "supported_languages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.GlossaryLanguagePair"
+                    }
+                }
+            }
+        },
+        "handlers.GlossaryListResponse": {
+            "type": "object",
+            "properties": {
+                "glossaries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.GlossaryInfo"
+                    }
+                }
+            }
+        },
+        "handlers.GoogleTranslateRequest": {
+            "type": "object",
+            "required": [
+                "q",
+                "source",
+                "target"
             ],
             "properties": {
-                "context": {
-                    "type": "string"
+                "format": {
+                    "type": "string",
+                    "example": "text"
                 },
-                "enable_beta_languages": {
-                    "type": "boolean"
+                "q": {
+                    "type": "string",
+                    "example": "The Great Pyramid of Giza"
                 },
-                "formality": {
+                "source": {
                     "type": "string",
-                    "example": "default"
+                    "example": "en"
                 },
-                "glossary_id": {
-                    "type": "string"
+                "target": {
+                    "type": "string",
+                    "example": "zh-Hans"
+                }
+            }
+        },
+        "handlers.GoogleTranslateResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "translations": {
+                            "type": "array",
+                            "items": {
+                                "type": "object",
+                                "properties": {
+                                    "translatedText": {
+                                        "type": "string",
+                                        "example": "吉萨大金字塔"
+                                    }
+                                }
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "handlers.HcfyDict": {
+            "type": "object",
+            "properties": {
+                "pos": {
+                    "type": "string",
+                    "example": "n."
                 },
-                "ignore_tags": {
+                "terms": {
                     "type": "array",
                     "items": {
                         "type": "string"
-                    }
+                    },
+                    "example": [
+                        "你好",
+                        "问候"
+                    ]
+                }
+            }
+        },
+        "handlers.HcfyPhonetic": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "美"
                 },
-                "model_type": {
+                "ttsURI": {
                     "type": "string",
-                    "example": "quality_optimized"
+                    "example": "https://..."
                 },
-                "non_splitting_tags": {
+                "value": {
+                    "type": "string",
+                    "example": "həˈloʊ"
+                }
+            }
+        },
+        "handlers.HcfyTranslateRequest": {
+            "type": "object",
+            "required": [
+                "destination",
+                "name",
+                "text"
+            ],
+            "properties": {
+                "destination": {
                     "type": "array",
                     "items": {
                         "type": "string"
+                    },
+                    "example": [
+                        "中文(简体)",
+                        "英语"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "example": "翻译一"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "英语"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Hello, word translation."
+                }
+            }
+        },
+        "handlers.HcfyTranslateResponse": {
+            "type": "object",
+            "properties": {
+                "dict": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.HcfyDict"
                     }
                 },
-                "outline_detection": {
+                "from": {
                     "type": "string",
-                    "example": "1"
+                    "example": "英语"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://..."
+                },
+                "phonetic": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.HcfyPhonetic"
+                    }
+                },
+                "result": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "你好，划词翻译。"
+                    ]
                 },
-                "preserve_formatting": {
+                "text": {
                     "type": "string",
-                    "example": "0"
+                    "example": "Hello, word translation."
                 },
-                "source_lang": {
+                "to": {
                     "type": "string",
-                    "example": "EN"
+                    "example": "中文(简体)"
                 },
-                "split_sentences": {
+                "ttsURI": {
                     "type": "string",
-                    "example": "1"
-                },
-                "splitting_tags": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                    "example": "https://..."
+                }
+            }
+        },
+        "handlers.ImmeTranslateRequest": {
+            "type": "object",
+            "required": [
+                "source_lang",
+                "target_lang",
+                "text_list"
+            ],
+            "properties": {
+                "partial_results": {
+                    "description": "PartialResults 部分结果模式：失败的文本附带 error，未开启时失败的文本静默返回原文",
+                    "type": "boolean",
+                    "example": false
                 },
-                "tag_handling": {
+                "source_lang": {
                     "type": "string",
-                    "example": "xml"
+                    "example": "en"
                 },
                 "target_lang": {
                     "type": "string",
-                    "example": "DE"
+                    "example": "zh-CN"
                 },
-                "text": {
+                "text_list": {
                     "type": "array",
                     "items": {
                         "type": "string"
                     },
                     "example": [
                         "Hello",
-                        " world!"
+                        " world!",
+                        "Good morning!"
                     ]
                 }
             }
         },
-        "handlers.DeeplTranslateResponse": {
+        "handlers.ImmeTranslateResponse": {
             "type": "object",
             "properties": {
                 "translations": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.DeeplTranslation"
+                        "$ref": "#/definitions/handlers.ImmeTranslation"
                     }
                 }
             }
         },
-        "handlers.DeeplTranslation": {
+        "handlers.ImmeTranslation": {
             "type": "object",
             "properties": {
-                "detected_source_language": {
+                "detected_source_lang": {
                     "type": "string",
-                    "example": "EN"
+                    "example": "en"
+                },
+                "error": {
+                    "$ref": "#/definitions/handlers.ItemError"
                 },
                 "text": {
                     "type": "string",
-                    "example": "Hallo, Welt!"
+                    "example": "你好，世界！"
                 }
             }
         },
-        "handlers.GoogleTranslateRequest": {
+        "handlers.ItemError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "enum": [
+                        "timeout",
+                        "canceled",
+                        "insufficient_memory",
+                        "translation_failed"
+                    ],
+                    "example": "timeout"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "context deadline exceeded"
+                }
+            }
+        },
+        "handlers.KissTranslateRequest": {
             "type": "object",
             "required": [
-                "q",
-                "source",
-                "target"
+                "from",
+                "text",
+                "to"
             ],
             "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Hello, world!"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "zh-CN"
+                }
+            }
+        },
+        "handlers.KissTranslateResponse": {
+            "type": "object",
+            "properties": {
+                "src": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "你好，世界！"
+                }
+            }
+        },
+        "handlers.LibreDetectRequest": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "q": {
+                    "type": "string",
+                    "example": "Hello, world!"
+                }
+            }
+        },
+        "handlers.LibreDetectedLanguage": {
+            "type": "object",
+            "properties": {
+                "confidence": {
+                    "type": "number",
+                    "example": 90
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                }
+            }
+        },
+        "handlers.LibreLanguage": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "English"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "de",
+                        "zh-Hans"
+                    ]
+                }
+            }
+        },
+        "handlers.LibreTranslateRequest": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
                 "format": {
                     "type": "string",
                     "example": "text"
                 },
                 "q": {
                     "type": "string",
-                    "example": "The Great Pyramid of Giza"
+                    "example": "Hello, world!"
                 },
                 "source": {
                     "type": "string",
-                    "example": "en"
+                    "example": "auto"
                 },
                 "target": {
                     "type": "string",
@@ -834,159 +3352,279 @@ const docTemplate = `{
                 }
             }
         },
-        "handlers.GoogleTranslateResponse": {
+        "handlers.LibreTranslateResponse": {
             "type": "object",
             "properties": {
-                "data": {
+                "detectedLanguage": {
+                    "type": "object"
+                },
+                "translatedText": {
+                    "type": "string",
+                    "example": "你好，世界！"
+                }
+            }
+        },
+        "handlers.MicrosoftDetectResult": {
+            "type": "object",
+            "properties": {
+                "isTranslationSupported": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "isTransliterationSupported": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.95
+                }
+            }
+        },
+        "handlers.MicrosoftDetectedLanguage": {
+            "type": "object",
+            "properties": {
+                "language": {
+                    "type": "string",
+                    "example": "en"
+                },
+                "score": {
+                    "type": "number",
+                    "example": 0.95
+                }
+            }
+        },
+        "handlers.MicrosoftLanguage": {
+            "type": "object",
+            "properties": {
+                "dir": {
+                    "type": "string",
+                    "example": "ltr"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "English"
+                },
+                "nativeName": {
+                    "type": "string",
+                    "example": "English"
+                }
+            }
+        },
+        "handlers.MicrosoftLanguagesResponse": {
+            "type": "object",
+            "properties": {
+                "translation": {
                     "type": "object",
-                    "properties": {
-                        "translations": {
-                            "type": "array",
-                            "items": {
-                                "type": "object",
-                                "properties": {
-                                    "translatedText": {
-                                        "type": "string",
-                                        "example": "吉萨大金字塔"
-                                    }
-                                }
-                            }
-                        }
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.MicrosoftLanguage"
                     }
                 }
             }
         },
-        "handlers.HcfyDict": {
+        "handlers.MicrosoftTextItem": {
             "type": "object",
             "properties": {
-                "pos": {
+                "Text": {
                     "type": "string",
-                    "example": "n."
+                    "example": "Hello, world!"
+                }
+            }
+        },
+        "handlers.MicrosoftTranslateResult": {
+            "type": "object",
+            "properties": {
+                "detectedLanguage": {
+                    "$ref": "#/definitions/handlers.MicrosoftDetectedLanguage"
                 },
-                "terms": {
+                "translations": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "你好",
-                        "问候"
-                    ]
+                        "$ref": "#/definitions/handlers.MicrosoftTranslation"
+                    }
                 }
             }
         },
-        "handlers.HcfyPhonetic": {
+        "handlers.MicrosoftTranslation": {
             "type": "object",
             "properties": {
-                "name": {
+                "text": {
                     "type": "string",
-                    "example": "美"
+                    "example": "你好，世界！"
                 },
-                "ttsURI": {
+                "to": {
                     "type": "string",
-                    "example": "https://..."
+                    "example": "zh-Hans"
+                }
+            }
+        },
+        "handlers.OpenAIChatChoice": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "$ref": "#/definitions/handlers.OpenAIChatMessage"
                 },
-                "value": {
+                "finish_reason": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "message": {
+                    "$ref": "#/definitions/handlers.OpenAIChatMessage"
+                }
+            }
+        },
+        "handlers.OpenAIChatMessage": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "description": "Content 字符串，或 [{\"type\": \"text\", \"text\": \"...\"}] 形式的内容数组",
                     "type": "string",
-                    "example": "həˈloʊ"
+                    "example": "Translate the following text to German:\n\nHello, world!"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "user"
                 }
             }
         },
-        "handlers.HcfyTranslateRequest": {
+        "handlers.OpenAIChatRequest": {
             "type": "object",
             "required": [
-                "destination",
-                "name",
-                "text"
+                "messages"
             ],
             "properties": {
-                "destination": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.OpenAIChatMessage"
+                    }
+                },
+                "model": {
+                    "type": "string",
+                    "example": "mtranserver"
+                },
+                "stream": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handlers.OpenAIChatResponse": {
+            "type": "object",
+            "properties": {
+                "choices": {
                     "type": "array",
                     "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "中文(简体)",
-                        "英语"
-                    ]
+                        "$ref": "#/definitions/handlers.OpenAIChatChoice"
+                    }
                 },
-                "name": {
+                "created": {
+                    "type": "integer"
+                },
+                "id": {
                     "type": "string",
-                    "example": "翻译一"
+                    "example": "chatcmpl-0123456789abcdef"
                 },
-                "source": {
+                "model": {
                     "type": "string",
-                    "example": "英语"
+                    "example": "mtranserver"
                 },
-                "text": {
+                "object": {
                     "type": "string",
-                    "example": "Hello, word translation."
+                    "example": "chat.completion"
+                },
+                "usage": {
+                    "$ref": "#/definitions/handlers.OpenAIUsage"
                 }
             }
         },
-        "handlers.HcfyTranslateResponse": {
+        "handlers.OpenAIModel": {
             "type": "object",
             "properties": {
-                "dict": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handlers.HcfyDict"
-                    }
+                "created": {
+                    "type": "integer"
                 },
-                "from": {
+                "id": {
                     "type": "string",
-                    "example": "英语"
+                    "example": "mtranserver"
                 },
-                "link": {
+                "object": {
                     "type": "string",
-                    "example": "https://..."
+                    "example": "model"
                 },
-                "phonetic": {
+                "owned_by": {
+                    "type": "string",
+                    "example": "mtranserver"
+                }
+            }
+        },
+        "handlers.OpenAIModelList": {
+            "type": "object",
+            "properties": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.HcfyPhonetic"
+                        "$ref": "#/definitions/handlers.OpenAIModel"
                     }
                 },
-                "result": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "你好，划词翻译。"
-                    ]
-                },
-                "text": {
+                "object": {
                     "type": "string",
-                    "example": "Hello, word translation."
+                    "example": "list"
+                }
+            }
+        },
+        "handlers.OpenAIUsage": {
+            "type": "object",
+            "properties": {
+                "completion_tokens": {
+                    "type": "integer"
                 },
-                "to": {
-                    "type": "string",
-                    "example": "中文(简体)"
+                "prompt_tokens": {
+                    "type": "integer"
                 },
-                "ttsURI": {
-                    "type": "string",
-                    "example": "https://..."
+                "total_tokens": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.ImmeTranslateRequest": {
+        "handlers.TranslateBatchRequest": {
             "type": "object",
             "required": [
-                "source_lang",
-                "target_lang",
-                "text_list"
+                "from",
+                "texts",
+                "to"
             ],
             "properties": {
-                "source_lang": {
+                "from": {
                     "type": "string",
                     "example": "en"
                 },
-                "target_lang": {
+                "html": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "partial_results": {
+                    "description": "PartialResults 部分结果模式：单个文本失败时返回原文，并在 errors 的对应位置给出错误",
+                    "type": "boolean",
+                    "example": false
+                },
+                "split_sentences": {
+                    "description": "SplitSentences 分句模式：0 不分句，1 按标点和换行分句，nonewlines 只按标点分句，为空时使用服务配置",
                     "type": "string",
-                    "example": "zh-CN"
+                    "enum": [
+                        "0",
+                        "1",
+                        "nonewlines"
+                    ],
+                    "example": "1"
                 },
-                "text_list": {
+                "texts": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -996,94 +3634,140 @@ const docTemplate = `{
                         " world!",
                         "Good morning!"
                     ]
+                },
+                "to": {
+                    "type": "string",
+                    "example": "zh-Hans"
                 }
             }
         },
-        "handlers.ImmeTranslateResponse": {
+        "handlers.TranslateBatchResponse": {
             "type": "object",
             "properties": {
-                "translations": {
+                "errors": {
+                    "description": "Errors 仅在部分结果模式下有文本失败时返回，与 results 一一对应，成功的文本为 null",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.ImmeTranslation"
+                        "$ref": "#/definitions/handlers.ItemError"
                     }
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "你好，世界！",
+                        "早上好！"
+                    ]
                 }
             }
         },
-        "handlers.ImmeTranslation": {
+        "handlers.TranslateCatalogRequest": {
             "type": "object",
+            "required": [
+                "content",
+                "to"
+            ],
             "properties": {
-                "detected_source_lang": {
+                "all": {
+                    "description": "All 重新翻译所有条目，默认只翻译未翻译的条目",
+                    "type": "boolean",
+                    "example": false
+                },
+                "content": {
+                    "type": "string",
+                    "example": "msgid \"Hello, %s!\"\nmsgstr \"\"\n"
+                },
+                "format": {
+                    "description": "Format po、pot、xliff 或 xlf，为空时自动识别",
+                    "type": "string",
+                    "example": "po"
+                },
+                "from": {
                     "type": "string",
                     "example": "en"
                 },
-                "text": {
+                "to": {
                     "type": "string",
-                    "example": "你好，世界！"
+                    "example": "zh-Hans"
                 }
             }
         },
-        "handlers.KissTranslateRequest": {
+        "handlers.TranslateI18nRequest": {
             "type": "object",
             "required": [
-                "from",
-                "text",
+                "content",
                 "to"
             ],
             "properties": {
-                "from": {
+                "content": {
                     "type": "string",
-                    "example": "en"
+                    "example": "{\"greeting\": \"Hello, {{name}}!\"}"
                 },
-                "text": {
+                "existing": {
+                    "description": "Existing 已有的目标语言文件，只翻译其中缺少的键",
                     "type": "string",
-                    "example": "Hello, world!"
+                    "example": ""
                 },
-                "to": {
+                "format": {
+                    "description": "Format json 或 yaml，为空时自动识别",
                     "type": "string",
-                    "example": "zh-CN"
-                }
-            }
-        },
-        "handlers.KissTranslateResponse": {
-            "type": "object",
-            "properties": {
-                "src": {
+                    "example": "json"
+                },
+                "from": {
                     "type": "string",
                     "example": "en"
                 },
-                "text": {
+                "to": {
                     "type": "string",
-                    "example": "你好，世界！"
+                    "example": "de"
                 }
             }
         },
-        "handlers.TranslateBatchRequest": {
+        "handlers.TranslateRequest": {
             "type": "object",
             "required": [
                 "from",
-                "texts",
+                "text",
                 "to"
             ],
             "properties": {
+                "format": {
+                    "description": "Format 文本格式：text、html 或 markdown，为空时由 html 决定。markdown 只翻译正文，代码、链接地址和 front matter 保持不变",
+                    "type": "string",
+                    "enum": [
+                        "text",
+                        "html",
+                        "markdown"
+                    ],
+                    "example": "text"
+                },
                 "from": {
                     "type": "string",
                     "example": "en"
                 },
+                "glossary": {
+                    "description": "Glossary 术语表 ID，术语表的语言对必须与 from、to 一致",
+                    "type": "string"
+                },
                 "html": {
                     "type": "boolean",
                     "example": false
                 },
-                "texts": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "Hello",
-                        " world!",
-                        "Good morning!"
-                    ]
+                "split_sentences": {
+                    "description": "SplitSentences 分句模式：0 不分句，1 按标点和换行分句，nonewlines 只按标点分句，为空时使用服务配置",
+                    "type": "string",
+                    "enum": [
+                        "0",
+                        "1",
+                        "nonewlines"
+                    ],
+                    "example": "1"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Hello, world!"
                 },
                 "to": {
                     "type": "string",
@@ -1091,53 +3775,147 @@ const docTemplate = `{
                 }
             }
         },
-        "handlers.TranslateBatchResponse": {
+        "handlers.TranslateResponse": {
             "type": "object",
             "properties": {
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "你好，世界！",
-                        "早上好！"
-                    ]
+                "result": {
+                    "type": "string",
+                    "example": "你好，世界！"
                 }
             }
         },
-        "handlers.TranslateRequest": {
+        "handlers.TranslateStreamChunk": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer",
+                    "example": 13
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "result": {
+                    "type": "string",
+                    "example": "你好，世界！"
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "text": {
+                    "type": "string",
+                    "example": "Hello, world!"
+                }
+            }
+        },
+        "handlers.TranslateSubtitleRequest": {
             "type": "object",
             "required": [
-                "from",
-                "text",
+                "content",
                 "to"
             ],
             "properties": {
+                "content": {
+                    "type": "string",
+                    "example": "1\n00:00:01,000 --\u003e 00:00:02,000\nHello, world!\n"
+                },
+                "format": {
+                    "description": "Format srt 或 vtt，为空时自动识别",
+                    "type": "string",
+                    "example": "srt"
+                },
                 "from": {
                     "type": "string",
                     "example": "en"
                 },
-                "html": {
+                "merge": {
+                    "description": "Merge 合并跨字幕的句子翻译，译文按原文长度拆回各条字幕",
                     "type": "boolean",
                     "example": false
                 },
-                "text": {
-                    "type": "string",
-                    "example": "Hello, world!"
-                },
                 "to": {
                     "type": "string",
                     "example": "zh-Hans"
                 }
             }
         },
-        "handlers.TranslateResponse": {
+        "handlers.UpdateGlossaryRequest": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "string",
+                    "example": "MTranServer\tMTranServer"
+                },
+                "entries_format": {
+                    "type": "string",
+                    "enum": [
+                        "tsv",
+                        "csv"
+                    ],
+                    "example": "tsv"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "产品名称"
+                }
+            }
+        },
+        "handlers.WSTranslateResponse": {
             "type": "object",
             "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
                 "result": {
                     "type": "string",
                     "example": "你好，世界！"
+                },
+                "retry_after": {
+                    "description": "RetryAfter 触发限流或配额时建议等待的秒数",
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "result"
+                }
+            }
+        },
+        "models.FileVerification": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "type": "string"
+                },
+                "file_type": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VerifyResult": {
+            "type": "object",
+            "properties": {
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FileVerification"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
                 }
             }
         }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hashicorp/go-getter"
 	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/metrics"
 	"github.com/xxnuo/MTranServer/internal/utils"
 )
 
@@ -137,9 +138,15 @@ func (d *Downloader) Download(urlStr, filename string, opts *DownloadOptions) er
 		return fmt.Errorf("Failed to configure downloader: %w", err)
 	}
 
+	start := time.Now()
 	if err := client.Get(); err != nil {
+		metrics.DownloadDuration.WithLabelValues("failure").Observe(time.Since(start).Seconds())
 		return fmt.Errorf("Failed to download: %w", err)
 	}
+	metrics.DownloadDuration.WithLabelValues("success").Observe(time.Since(start).Seconds())
+	if info, err := os.Stat(tmpFile); err == nil {
+		metrics.DownloadBytesTotal.Add(float64(info.Size()))
+	}
 
 	logger.Debug("Download completed: %s", filename)
 
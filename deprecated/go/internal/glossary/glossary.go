@@ -0,0 +1,174 @@
+package glossary
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 词条导入格式
+const (
+	FormatTSV = "tsv"
+	FormatCSV = "csv"
+)
+
+// MaxEntries 单个术语表的最大词条数
+const MaxEntries = 10000
+
+// Entry 一个术语：源语言词条和必须使用的译文
+type Entry struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// Glossary 一个语言对的术语表。创建后只能通过 Store 整体替换，可以在多个请求间共享
+type Glossary struct {
+	ID           string    `json:"glossary_id"`
+	Name         string    `json:"name"`
+	SourceLang   string    `json:"source_lang"`
+	TargetLang   string    `json:"target_lang"`
+	CreationTime time.Time `json:"creation_time"`
+	Entries      []Entry   `json:"entries"`
+
+	pattern *regexp.Regexp
+	targets map[string]string
+}
+
+// ParseEntries 解析 TSV（每行 源词条<Tab>译文）或 CSV（取前两列）格式的词条，format 为空时按 TSV 处理
+func ParseEntries(data string, format string) ([]Entry, error) {
+	data = strings.TrimPrefix(data, "\ufeff")
+
+	var rows [][]string
+	switch strings.ToLower(format) {
+	case "", FormatTSV:
+		for i, line := range strings.Split(data, "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			fields := strings.Split(line, "\t")
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("line %d: expected source and target separated by a single tab", i+1)
+			}
+			rows = append(rows, fields)
+		}
+	case FormatCSV:
+		r := csv.NewReader(strings.NewReader(data))
+		r.FieldsPerRecord = -1
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid CSV: %w", err)
+			}
+			if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+				continue
+			}
+			if len(record) < 2 {
+				line, _ := r.FieldPos(0)
+				return nil, fmt.Errorf("line %d: expected at least two columns", line)
+			}
+			rows = append(rows, record[:2])
+		}
+	default:
+		return nil, fmt.Errorf("unsupported entries format: %s", format)
+	}
+
+	entries := make([]Entry, len(rows))
+	for i, row := range rows {
+		entries[i] = Entry{Source: strings.TrimSpace(row[0]), Target: strings.TrimSpace(row[1])}
+	}
+	if err := validateEntries(entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func validateEntries(entries []Entry) error {
+	if len(entries) == 0 {
+		return errors.New("glossary must contain at least one entry")
+	}
+	if len(entries) > MaxEntries {
+		return fmt.Errorf("glossary must not contain more than %d entries", MaxEntries)
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if entry.Source == "" || entry.Target == "" {
+			return fmt.Errorf("entry #%d: source and target must not be empty", i+1)
+		}
+		if strings.ContainsAny(entry.Source+entry.Target, "\t\r\n") {
+			return fmt.Errorf("entry %q: terms must not contain tabs or line breaks", entry.Source)
+		}
+		if seen[entry.Source] {
+			return fmt.Errorf("entry %q: duplicate source term", entry.Source)
+		}
+		seen[entry.Source] = true
+	}
+	return nil
+}
+
+// TSV 按 DeepL 的格式导出词条，每行 源词条<Tab>译文
+func (g *Glossary) TSV() string {
+	var buf bytes.Buffer
+	for _, entry := range g.Entries {
+		buf.WriteString(entry.Source)
+		buf.WriteByte('\t')
+		buf.WriteString(entry.Target)
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
+
+// index 编译词条匹配表达式。较长的词条优先，词条两端是字母或数字时要求位于单词边界
+func (g *Glossary) index() {
+	sources := make([]string, len(g.Entries))
+	g.targets = make(map[string]string, len(g.Entries))
+	for i, entry := range g.Entries {
+		sources[i] = entry.Source
+		g.targets[entry.Source] = entry.Target
+	}
+	sort.SliceStable(sources, func(i, j int) bool { return len(sources[i]) > len(sources[j]) })
+
+	alternatives := make([]string, len(sources))
+	for i, source := range sources {
+		expr := regexp.QuoteMeta(source)
+		if isWordByte(source[0]) {
+			expr = `\b` + expr
+		}
+		if isWordByte(source[len(source)-1]) {
+			expr += `\b`
+		}
+		alternatives[i] = expr
+	}
+	g.pattern = regexp.MustCompile(strings.Join(alternatives, "|"))
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || '0' <= b && b <= '9' || 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// Match 返回文本中所有词条出现的位置，可作为 mask.Matcher 使用
+func (g *Glossary) Match(text string) [][2]int {
+	var spans [][2]int
+	for _, loc := range g.pattern.FindAllStringIndex(text, -1) {
+		spans = append(spans, [2]int{loc[0], loc[1]})
+	}
+	return spans
+}
+
+// Target 返回源词条对应的译文，不是词条时原样返回
+func (g *Glossary) Target(source string) string {
+	if target, ok := g.targets[source]; ok {
+		return target
+	}
+	return source
+}
@@ -0,0 +1,114 @@
+package glossary
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntries(t *testing.T) {
+	entries, err := ParseEntries("MTranServer\tMTranServer\r\n\nterms of service\tNutzungsbedingungen\n", "")
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Source: "MTranServer", Target: "MTranServer"},
+		{Source: "terms of service", Target: "Nutzungsbedingungen"},
+	}, entries)
+
+	entries, err = ParseEntries("\"Acme, Inc.\",Acme AG,ignored\nlicense,Lizenz\n", FormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Source: "Acme, Inc.", Target: "Acme AG"},
+		{Source: "license", Target: "Lizenz"},
+	}, entries)
+
+	for _, tc := range []struct{ data, format string }{
+		{"", FormatTSV},
+		{"a\tb\tc", FormatTSV},
+		{"a", FormatTSV},
+		{"a\t", FormatTSV},
+		{"a\tb\na\tc", FormatTSV},
+		{"a", FormatCSV},
+		{"a\tb", "xlsx"},
+	} {
+		_, err := ParseEntries(tc.data, tc.format)
+		assert.Error(t, err, "%q as %s", tc.data, tc.format)
+	}
+}
+
+func TestGlossaryMatch(t *testing.T) {
+	g := &Glossary{Entries: []Entry{
+		{Source: "Go", Target: "Go"},
+		{Source: "Go SDK", Target: "Go-SDK"},
+		{Source: "C++", Target: "C++"},
+		{Source: "云服务", Target: "cloud service"},
+	}}
+	g.index()
+
+	text := "Go SDK, Go, Gopher, C++, 云服务器"
+	var matches []string
+	for _, span := range g.Match(text) {
+		matches = append(matches, text[span[0]:span[1]])
+	}
+	assert.Equal(t, []string{"Go SDK", "Go", "C++", "云服务"}, matches)
+	assert.Equal(t, "Go-SDK", g.Target("Go SDK"))
+	assert.Equal(t, "other", g.Target("other"))
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glossaries.json")
+	s := NewStore(path)
+
+	_, err := s.Create("", "en", "de", []Entry{{Source: "a", Target: "b"}})
+	assert.Error(t, err)
+	_, err = s.Create("same", "en", "en", []Entry{{Source: "a", Target: "b"}})
+	assert.Error(t, err)
+
+	g, err := s.Create("product", "en", "de", []Entry{{Source: "Acme Cloud", Target: "Acme Cloud"}})
+	require.NoError(t, err)
+	assert.Len(t, g.ID, 36)
+
+	updated, err := s.Update(g.ID, "", []Entry{{Source: "license", Target: "Lizenz"}})
+	require.NoError(t, err)
+	assert.Equal(t, "product", updated.Name)
+	assert.Equal(t, "Lizenz", updated.Target("license"))
+	// 已经取得的术语表不受修改影响
+	assert.Equal(t, "Acme Cloud", g.TSV()[:len("Acme Cloud")])
+
+	loaded := NewStore(path)
+	require.NoError(t, loaded.Load())
+	got, err := loaded.Get(g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "license\tLizenz\n", got.TSV())
+	assert.Equal(t, [][2]int{{4, 11}}, got.Match("the license"))
+
+	require.NoError(t, s.Delete(g.ID))
+	assert.ErrorIs(t, s.Delete(g.ID), ErrNotFound)
+	_, err = s.Get(g.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, s.List())
+
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStoreErrors(t *testing.T) {
+	s := NewStore("")
+	_, err := s.Create("", "en", "de", []Entry{{Source: "a", Target: "b"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = s.Create("x", "en", "en", []Entry{{Source: "a", Target: "b"}})
+	assert.ErrorIs(t, err, ErrInvalid)
+	_, err = s.Create("x", "en", "de", nil)
+	assert.ErrorIs(t, err, ErrInvalid)
+
+	// 父目录是文件，写入失败不属于校验错误
+	blocker := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(blocker, nil, 0644))
+	s = NewStore(filepath.Join(blocker, "glossaries.json"))
+	_, err = s.Create("x", "en", "de", []Entry{{Source: "a", Target: "b"}})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrInvalid)
+	assert.Empty(t, s.List())
+}
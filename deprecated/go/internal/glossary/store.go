@@ -0,0 +1,221 @@
+package glossary
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("glossary not found")
+	// ErrInvalid 名称、语言或词条不合法，Create 和 Update 返回的校验错误都包装了它
+	ErrInvalid = errors.New("invalid glossary")
+)
+
+// Store 术语表存储。设置了文件路径时每次修改后写入文件
+type Store struct {
+	mu         sync.RWMutex
+	path       string
+	glossaries map[string]*Glossary
+}
+
+// NewStore 创建术语表存储，path 为空时只保存在内存中
+func NewStore(path string) *Store {
+	return &Store{
+		path:       path,
+		glossaries: make(map[string]*Glossary),
+	}
+}
+
+// Load 从文件恢复术语表，替换当前内容
+func (s *Store) Load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+
+	var list []*Glossary
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("failed to parse glossaries file: %w", err)
+	}
+
+	glossaries := make(map[string]*Glossary, len(list))
+	for _, g := range list {
+		if err := validateEntries(g.Entries); err != nil {
+			return fmt.Errorf("glossary %s: %w", g.ID, err)
+		}
+		g.index()
+		glossaries[g.ID] = g
+	}
+
+	s.mu.Lock()
+	s.glossaries = glossaries
+	s.mu.Unlock()
+	return nil
+}
+
+// save 在持有锁时调用
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.sorted())
+	if err != nil {
+		return fmt.Errorf("failed to marshal glossaries: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create glossaries directory: %w", err)
+	}
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write glossaries file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move glossaries file: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) sorted() []*Glossary {
+	list := make([]*Glossary, 0, len(s.glossaries))
+	for _, g := range s.glossaries {
+		list = append(list, g)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreationTime.Equal(list[j].CreationTime) {
+			return list[i].CreationTime.Before(list[j].CreationTime)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// List 按创建时间返回所有术语表
+func (s *Store) List() []*Glossary {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.sorted()
+}
+
+// Get 按 ID 查找术语表
+func (s *Store) Get(id string) (*Glossary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	g, ok := s.glossaries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return g, nil
+}
+
+// Create 创建术语表，语言代码应已规范化
+func (s *Store) Create(name, sourceLang, targetLang string, entries []Entry) (*Glossary, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalid)
+	}
+	if sourceLang == "" || targetLang == "" {
+		return nil, fmt.Errorf("%w: source_lang and target_lang are required", ErrInvalid)
+	}
+	if sourceLang == targetLang {
+		return nil, fmt.Errorf("%w: source and target language must differ", ErrInvalid)
+	}
+	if err := validateEntries(entries); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+
+	g := &Glossary{
+		ID:           newID(),
+		Name:         name,
+		SourceLang:   sourceLang,
+		TargetLang:   targetLang,
+		CreationTime: time.Now().UTC().Truncate(time.Millisecond),
+		Entries:      entries,
+	}
+	g.index()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.glossaries[g.ID] = g
+	if err := s.save(); err != nil {
+		delete(s.glossaries, g.ID)
+		return nil, err
+	}
+	return g, nil
+}
+
+// Update 修改术语表名称或替换全部词条，参数为空时保持不变。
+// 正在使用旧术语表的请求不受影响
+func (s *Store) Update(id, name string, entries []Entry) (*Glossary, error) {
+	if entries != nil {
+		if err := validateEntries(entries); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, ok := s.glossaries[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	g := &Glossary{
+		ID:           old.ID,
+		Name:         old.Name,
+		SourceLang:   old.SourceLang,
+		TargetLang:   old.TargetLang,
+		CreationTime: old.CreationTime,
+		Entries:      old.Entries,
+	}
+	if strings.TrimSpace(name) != "" {
+		g.Name = name
+	}
+	if entries != nil {
+		g.Entries = entries
+	}
+	g.index()
+
+	s.glossaries[id] = g
+	if err := s.save(); err != nil {
+		s.glossaries[id] = old
+		return nil, err
+	}
+	return g, nil
+}
+
+// Delete 删除术语表
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g, ok := s.glossaries[id]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.glossaries, id)
+	if err := s.save(); err != nil {
+		s.glossaries[id] = g
+		return err
+	}
+	return nil
+}
+
+// newID 生成 UUID v4 格式的 ID
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
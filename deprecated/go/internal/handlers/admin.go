@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/models"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// handleListEngines 列出已加载的引擎
+// @Summary      列出已加载的引擎
+// @Description  返回所有语言对引擎池及其 worker 的端口、PID、状态、最后使用时间和空闲截止时间
+// @Tags         管理
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/engines [get]
+func HandleListEngines(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"engines": services.ListEngines(),
+	})
+}
+
+// handleWarmEngine 预热引擎
+// @Summary      预热引擎
+// @Description  预先下载模型并启动指定语言对的引擎池，需要中转时同时启动两段引擎
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/engines/{from}/{to} [post]
+func HandleWarmEngine(c *gin.Context) {
+	from := utils.NormalizeLanguageCode(c.Param("from"))
+	to := utils.NormalizeLanguageCode(c.Param("to"))
+
+	if from == to {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "source and target language must differ",
+		})
+		return
+	}
+
+	if err := services.WarmEngine(from, to); err != nil {
+		logger.Error("Failed to warm engine %s -> %s: %v", from, to, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to warm engine: %v", err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// handleStopEngine 停止引擎
+// @Summary      停止引擎
+// @Description  停止指定语言对的引擎池并释放所有 worker
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Success      200   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/engines/{from}/{to} [delete]
+func HandleStopEngine(c *gin.Context) {
+	from := utils.NormalizeLanguageCode(c.Param("from"))
+	to := utils.NormalizeLanguageCode(c.Param("to"))
+
+	if err := services.StopEngine(from, to); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// handleRestartWorker 重启 worker
+// @Summary      重启 worker
+// @Description  在原端口上重启引擎池中的单个 worker
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Param        port  path      int     true  "worker 端口"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/engines/{from}/{to}/workers/{port}/restart [post]
+func HandleRestartWorker(c *gin.Context) {
+	from := utils.NormalizeLanguageCode(c.Param("from"))
+	to := utils.NormalizeLanguageCode(c.Param("to"))
+
+	port, err := strconv.Atoi(c.Param("port"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid port",
+		})
+		return
+	}
+
+	if err := services.RestartEngineWorker(from, to, port); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// handleWorkerLogs 获取 worker 日志
+// @Summary      获取 worker 日志
+// @Description  返回单个 worker 最近输出的日志
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Param        port  path      int     true  "worker 端口"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/engines/{from}/{to}/workers/{port}/logs [get]
+func HandleWorkerLogs(c *gin.Context) {
+	from := utils.NormalizeLanguageCode(c.Param("from"))
+	to := utils.NormalizeLanguageCode(c.Param("to"))
+
+	port, err := strconv.Atoi(c.Param("port"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "invalid port",
+		})
+		return
+	}
+
+	logs, err := services.GetWorkerLogs(from, to, port)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs": logs,
+	})
+}
+
+func respondAdminError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrEngineNotFound), errors.Is(err, services.ErrWorkerNotFound),
+		errors.Is(err, models.ErrModelNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrWorkerBusy), errors.Is(err, services.ErrModelDownloading),
+		errors.Is(err, services.ErrOfflineMode):
+		status = http.StatusConflict
+	default:
+		logger.Error("Admin request failed: %v", err)
+	}
+
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func setupAdminRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/admin/engines", HandleListEngines)
+	r.DELETE("/admin/engines/:from/:to", HandleStopEngine)
+	r.POST("/admin/engines/:from/:to/workers/:port/restart", HandleRestartWorker)
+	r.GET("/admin/engines/:from/:to/workers/:port/logs", HandleWorkerLogs)
+	return r
+}
+
+func TestHandleListEngines(t *testing.T) {
+	r := setupAdminRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/engines", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "engines")
+}
+
+func TestHandleStopEngineNotFound(t *testing.T) {
+	r := setupAdminRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/admin/engines/en/ja", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleRestartWorkerInvalidPort(t *testing.T) {
+	r := setupAdminRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/admin/engines/en/ja/workers/abc/restart", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandleWorkerLogsNotFound(t *testing.T) {
+	r := setupAdminRouter()
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin/engines/en/ja/workers/12345/logs", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleDeleteModelRejectsUnknownPair(t *testing.T) {
+	oldRecords := models.GlobalRecords
+	oldModelDir := config.GetConfig().ModelDir
+	t.Cleanup(func() {
+		models.GlobalRecords = oldRecords
+		config.GetConfig().ModelDir = oldModelDir
+	})
+
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{{SourceLanguage: "en", TargetLanguage: "ja", FileType: "model"}},
+	}
+	config.GetConfig().ModelDir = t.TempDir()
+	keep := filepath.Join(config.GetConfig().ModelDir, "cache_dir")
+	require.NoError(t, os.MkdirAll(keep, 0755))
+
+	r := gin.New()
+	r.DELETE("/admin/models/:from/:to", HandleDeleteModel)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("DELETE", "/admin/models/cache/dir", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.DirExists(t, keep)
+}
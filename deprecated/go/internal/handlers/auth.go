@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/middleware"
+)
+
+// checkPairAllowed 校验当前密钥是否允许翻译该语言对，不允许时返回 403 并终止请求
+func checkPairAllowed(c *gin.Context, fromLang, toLang string) bool {
+	key := middleware.KeyFromContext(c)
+	if key == nil || key.AllowsPair(fromLang, toLang) {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": fmt.Sprintf("API key is not allowed to translate %s -> %s", fromLang, toLang),
+	})
+	c.Abort()
+	return false
+}
@@ -44,11 +44,14 @@ type DeeplTranslateRequest struct {
 	ModelType           string   `json:"model_type,omitempty" example:"quality_optimized"`
 	Context             string   `json:"context,omitempty"`
 	EnableBetaLanguages bool     `json:"enable_beta_languages,omitempty"`
+	// PartialResults 部分结果模式（扩展字段）：单个文本失败时返回原文并附带 error
+	PartialResults bool `json:"partial_results,omitempty" example:"false"`
 }
 
 type DeeplTranslation struct {
-	DetectedSourceLanguage string `json:"detected_source_language" example:"EN"`
-	Text                   string `json:"text" example:"Hallo, Welt!"`
+	DetectedSourceLanguage string     `json:"detected_source_language" example:"EN"`
+	Text                   string     `json:"text" example:"Hallo, Welt!"`
+	Error                  *ItemError `json:"error,omitempty"`
 }
 
 type DeeplTranslateResponse struct {
@@ -57,85 +60,90 @@ type DeeplTranslateResponse struct {
 
 // HandleDeeplTranslate DeepL 翻译兼容接口
 // @Summary      DeepL 翻译兼容接口
-// @Description  兼容 DeepL API v2 的翻译接口
+// @Description  兼容 DeepL API v2 的翻译接口，支持通过 glossary_id 使用术语表
 // @Tags         插件
 // @Accept       json
 // @Produce      json
-// @Param        token    query     string                  false  "API Token"
-// @Param        request  body      DeeplTranslateRequest   true   "DeepL 翻译请求"
-// @Success      200      {object}  DeeplTranslateResponse
-// @Failure      400      {object}  map[string]string
-// @Failure      401      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Param        token            query     string                 false  "API Token"
+// @Param        request          body      DeeplTranslateRequest  true   "DeepL 翻译请求"
+// @Param        partial_results  query     bool                   false  "部分结果模式，单个文本失败时返回原文并附带错误"
+// @Success      200              {object}  DeeplTranslateResponse
+// @Failure      400              {object}  map[string]string
+// @Failure      401              {object}  map[string]string
+// @Failure      403              {object}  map[string]string
+// @Failure      404              {object}  map[string]string
+// @Failure      429              {object}  map[string]string
+// @Failure      456              {object}  map[string]string
+// @Failure      500              {object}  map[string]string
 // @Router       /deepl [post]
-func HandleDeeplTranslate(apiToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+func HandleDeeplTranslate(c *gin.Context) {
+	var req DeeplTranslateRequest
 
-		if apiToken != "" {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if !checkSplitSentences(c, req.SplitSentences) {
+		return
+	}
 
-			authHeader := c.GetHeader("Authorization")
-			token := ""
+	sourceLang := "auto"
+	if req.SourceLang != "" {
+		sourceLang = utils.NormalizeLanguageCode(req.SourceLang)
+	}
+	targetLang := utils.NormalizeLanguageCode(req.TargetLang)
+
+	if !checkPairAllowed(c, sourceLang, targetLang) {
+		return
+	}
 
-			if strings.HasPrefix(authHeader, "DeepL-Auth-Key ") {
-				token = strings.TrimPrefix(authHeader, "DeepL-Auth-Key ")
-			} else if authHeader != "" {
+	// 与 DeepL 相同，使用术语表时必须指定源语言
+	if req.GlossaryID != "" && req.SourceLang == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "source_lang is required when glossary_id is set",
+		})
+		return
+	}
+	g, ok := resolveGlossary(c, req.GlossaryID, sourceLang, targetLang)
+	if !ok {
+		return
+	}
 
-				token = strings.TrimPrefix(authHeader, "Bearer ")
-			} else {
+	translations := make([]DeeplTranslation, len(req.Text))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
+	defer cancel()
+	ctx = services.WithSplitSentences(ctx, req.SplitSentences)
 
-				token = c.Query("token")
-			}
+	isHTML := req.TagHandling == "html" || req.TagHandling == "xml"
 
-			if token != apiToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Unauthorized",
-				})
-				return
-			}
-		}
-		var req DeeplTranslateRequest
+	results, errs := services.TranslateManyWithGlossary(ctx, sourceLang, targetLang, req.Text, isHTML, g)
+	if err := services.FirstError(errs); err != nil && !partialResults(c, req.PartialResults) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
+		})
+		return
+	}
 
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
-			return
+	itemErrs := itemErrors(req.Text, results, errs)
+	for i, result := range results {
+		detectedLang := req.SourceLang
+		if detectedLang == "" {
+			detectedLang = convertBCP47ToDeeplLang(sourceLang)
 		}
 
-		sourceLang := "auto"
-		if req.SourceLang != "" {
-			sourceLang = utils.NormalizeLanguageCode(req.SourceLang)
+		translations[i] = DeeplTranslation{
+			DetectedSourceLanguage: detectedLang,
+			Text:                   result,
 		}
-		targetLang := utils.NormalizeLanguageCode(req.TargetLang)
-
-		translations := make([]DeeplTranslation, len(req.Text))
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
-		defer cancel()
-
-		isHTML := req.TagHandling == "html" || req.TagHandling == "xml"
-
-		for i, text := range req.Text {
-			result, err := services.TranslateWithPivot(ctx, sourceLang, targetLang, text, isHTML)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": fmt.Sprintf("Translation failed at index %d: %v", i, err),
-				})
-				return
-			}
-
-			detectedLang := req.SourceLang
-			if detectedLang == "" {
-				detectedLang = convertBCP47ToDeeplLang(sourceLang)
-			}
-
-			translations[i] = DeeplTranslation{
-				DetectedSourceLanguage: detectedLang,
-				Text:                   result,
-			}
+		if itemErrs != nil {
+			translations[i].Error = itemErrs[i]
 		}
-
-		c.JSON(http.StatusOK, DeeplTranslateResponse{
-			Translations: translations,
-		})
 	}
+
+	c.JSON(http.StatusOK, DeeplTranslateResponse{
+		Translations: translations,
+	})
 }
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// TranslateSubtitleRequest 字幕翻译请求
+type TranslateSubtitleRequest struct {
+	From    string `json:"from" example:"en"`
+	To      string `json:"to" binding:"required" example:"zh-Hans"`
+	Content string `json:"content" binding:"required" example:"1\n00:00:01,000 --> 00:00:02,000\nHello, world!\n"`
+	// Format srt 或 vtt，为空时自动识别
+	Format string `json:"format" example:"srt"`
+	// Merge 合并跨字幕的句子翻译，译文按原文长度拆回各条字幕
+	Merge bool `json:"merge" example:"false"`
+}
+
+// TranslateCatalogRequest 翻译目录请求
+type TranslateCatalogRequest struct {
+	From    string `json:"from" example:"en"`
+	To      string `json:"to" binding:"required" example:"zh-Hans"`
+	Content string `json:"content" binding:"required" example:"msgid \"Hello, %s!\"\nmsgstr \"\"\n"`
+	// Format po、pot、xliff 或 xlf，为空时自动识别
+	Format string `json:"format" example:"po"`
+	// All 重新翻译所有条目，默认只翻译未翻译的条目
+	All bool `json:"all" example:"false"`
+}
+
+// TranslateI18nRequest 资源文件翻译请求
+type TranslateI18nRequest struct {
+	From    string `json:"from" example:"en"`
+	To      string `json:"to" binding:"required" example:"de"`
+	Content string `json:"content" binding:"required" example:"{\"greeting\": \"Hello, {{name}}!\"}"`
+	// Format json 或 yaml，为空时自动识别
+	Format string `json:"format" example:"json"`
+	// Existing 已有的目标语言文件，只翻译其中缺少的键
+	Existing string `json:"existing" example:""`
+}
+
+// HandleTranslateSubtitle 字幕翻译
+// @Summary      字幕翻译
+// @Description  翻译 SRT 或 WebVTT 字幕中的对白文本，保留时间轴、cue settings、样式标签和行结构，返回同格式的字幕文件
+// @Tags         翻译
+// @Accept       json
+// @Produce      plain
+// @Param        request  body      TranslateSubtitleRequest  true  "字幕翻译请求"
+// @Success      200      {string}  string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /translate/subtitle [post]
+func HandleTranslateSubtitle(c *gin.Context) {
+	var req TranslateSubtitleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	translateFile(c, services.SubtitleFile, req.From, req.To, req.Content, req.Format, services.FileOptions{Merge: req.Merge})
+}
+
+// HandleTranslateCatalog 翻译目录
+// @Summary      翻译 PO/XLIFF 目录
+// @Description  翻译 gettext PO/POT 或 XLIFF 1.2 文件中未翻译的条目，保护 printf、花括号和 ICU 占位符，机器译文标记为 fuzzy 或 needs-review-translation，保留注释和顺序，返回同格式的文件
+// @Tags         翻译
+// @Accept       json
+// @Produce      plain
+// @Param        request  body      TranslateCatalogRequest  true  "翻译目录请求"
+// @Success      200      {string}  string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /translate/catalog [post]
+func HandleTranslateCatalog(c *gin.Context) {
+	var req TranslateCatalogRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	translateFile(c, services.CatalogFile, req.From, req.To, req.Content, req.Format, services.FileOptions{All: req.All})
+}
+
+// HandleTranslateI18n 资源文件翻译
+// @Summary      资源文件翻译
+// @Description  翻译 JSON（i18next、vue-i18n）或 YAML（Rails）资源文件中的字符串值，键和非字符串值保持不变，保护 {{count}}、{name}、%{var} 等插值和 HTML 标签。提供 existing 时只翻译缺少的键
+// @Tags         翻译
+// @Accept       json
+// @Produce      json
+// @Produce      plain
+// @Param        request  body      TranslateI18nRequest  true  "资源文件翻译请求"
+// @Success      200      {string}  string
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /translate/i18n [post]
+func HandleTranslateI18n(c *gin.Context) {
+	var req TranslateI18nRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	translateFile(c, services.I18nFile, req.From, req.To, req.Content, req.Format, services.FileOptions{Existing: req.Existing})
+}
+
+// translateFile 解析文件、翻译并按原格式返回，format 不为空时要求内容是该格式
+func translateFile(c *gin.Context, t *services.FileType, from, to, content, format string, opts services.FileOptions) {
+	file, err := t.Parse(content, format, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid %s: %v", t.Name, err),
+		})
+		return
+	}
+
+	fromLang := "auto"
+	if from != "" {
+		fromLang = utils.NormalizeLanguageCode(from)
+	}
+	toLang := utils.NormalizeLanguageCode(to)
+
+	if !checkPairAllowed(c, fromLang, toLang) {
+		return
+	}
+
+	logger.Debug("File translation request: %s -> %s, type: %s, format: %s", fromLang, toLang, t.Name, file.Format())
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 300*time.Second)
+	defer cancel()
+
+	if _, err := file.Translate(ctx, fromLang, toLang); err != nil {
+		logger.Error("File translation failed (%s, %s -> %s): %v", t.Name, fromLang, toLang, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
+		})
+		return
+	}
+
+	data, err := file.Marshal()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Failed to encode %s: %v", file.Format(), err),
+		})
+		return
+	}
+	c.Data(http.StatusOK, file.ContentType(), data)
+}
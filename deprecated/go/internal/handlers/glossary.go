@@ -0,0 +1,341 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/glossary"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/middleware"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// CreateGlossaryRequest 创建术语表请求
+type CreateGlossaryRequest struct {
+	Name       string `json:"name" binding:"required" example:"产品名称"`
+	SourceLang string `json:"source_lang" binding:"required" example:"en"`
+	TargetLang string `json:"target_lang" binding:"required" example:"zh-Hans"`
+	// Entries 词条，TSV 为每行 源词条<Tab>译文，CSV 取每行前两列
+	Entries       string `json:"entries" binding:"required" example:"MTranServer\tMTranServer\nterms of service\t服务条款"`
+	EntriesFormat string `json:"entries_format" enums:"tsv,csv" example:"tsv"`
+}
+
+// UpdateGlossaryRequest 修改术语表请求，字段为空时保持不变，entries 会替换全部词条
+type UpdateGlossaryRequest struct {
+	Name          string `json:"name" example:"产品名称"`
+	Entries       string `json:"entries" example:"MTranServer\tMTranServer"`
+	EntriesFormat string `json:"entries_format" enums:"tsv,csv" example:"tsv"`
+}
+
+// GlossaryInfo 术语表信息，与 DeepL 的格式相同
+type GlossaryInfo struct {
+	GlossaryID   string    `json:"glossary_id" example:"def3a26b-3e84-45b3-84ae-0c0aaf3525f7"`
+	Name         string    `json:"name" example:"产品名称"`
+	Ready        bool      `json:"ready" example:"true"`
+	SourceLang   string    `json:"source_lang" example:"en"`
+	TargetLang   string    `json:"target_lang" example:"zh-Hans"`
+	CreationTime time.Time `json:"creation_time"`
+	EntryCount   int       `json:"entry_count" example:"2"`
+}
+
+// GlossaryListResponse 术语表列表
+type GlossaryListResponse struct {
+	Glossaries []GlossaryInfo `json:"glossaries"`
+}
+
+// GlossaryLanguagePair 可以创建术语表的语言对
+type GlossaryLanguagePair struct {
+	SourceLang string `json:"source_lang" example:"en"`
+	TargetLang string `json:"target_lang" example:"zh-Hans"`
+}
+
+// GlossaryLanguagePairsResponse 可以创建术语表的语言对列表
+type GlossaryLanguagePairsResponse struct {
+	SupportedLanguages []GlossaryLanguagePair `json:"supported_languages"`
+}
+
+func newGlossaryInfo(g *glossary.Glossary) GlossaryInfo {
+	return GlossaryInfo{
+		GlossaryID:   g.ID,
+		Name:         g.Name,
+		Ready:        true,
+		SourceLang:   g.SourceLang,
+		TargetLang:   g.TargetLang,
+		CreationTime: g.CreationTime,
+		EntryCount:   len(g.Entries),
+	}
+}
+
+// glossaryError 按错误类型返回 404、400 或 500
+func glossaryError(c *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, glossary.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, glossary.ErrInvalid):
+		status = http.StatusBadRequest
+	default:
+		logger.Error("Glossary operation failed: %v", err)
+	}
+	c.JSON(status, gin.H{
+		"error": err.Error(),
+	})
+}
+
+// getGlossary 按路径参数 id 查找术语表，并检查当前密钥是否允许其语言对，失败时返回错误响应
+func getGlossary(c *gin.Context) (*glossary.Glossary, bool) {
+	g, err := services.Glossaries().Get(c.Param("id"))
+	if err != nil {
+		glossaryError(c, err)
+		return nil, false
+	}
+	if !checkPairAllowed(c, g.SourceLang, g.TargetLang) {
+		return nil, false
+	}
+	return g, true
+}
+
+// resolveGlossary 查找请求指定的术语表并检查语言对是否一致，失败时返回错误响应并终止请求。
+// id 为空时返回 nil
+func resolveGlossary(c *gin.Context, id, fromLang, toLang string) (*glossary.Glossary, bool) {
+	if id == "" {
+		return nil, true
+	}
+
+	g, err := services.Glossaries().Get(id)
+	if err != nil {
+		glossaryError(c, err)
+		c.Abort()
+		return nil, false
+	}
+	if g.SourceLang != fromLang || g.TargetLang != toLang {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Glossary %s is for %s -> %s, not %s -> %s", g.ID, g.SourceLang, g.TargetLang, fromLang, toLang),
+		})
+		c.Abort()
+		return nil, false
+	}
+	return g, true
+}
+
+// HandleCreateGlossary 创建术语表
+// @Summary      创建术语表
+// @Description  从 TSV 或 CSV 词条创建一个语言对的术语表，翻译时源词条强制使用指定的译文。接口与 DeepL /v2/glossaries 兼容
+// @Tags         术语表
+// @Accept       json
+// @Produce      json
+// @Param        request  body      CreateGlossaryRequest  true  "创建术语表请求"
+// @Success      201      {object}  GlossaryInfo
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossaries [post]
+func HandleCreateGlossary(c *gin.Context) {
+	var req CreateGlossaryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	sourceLang := utils.NormalizeLanguageCode(req.SourceLang)
+	targetLang := utils.NormalizeLanguageCode(req.TargetLang)
+
+	if !checkPairAllowed(c, sourceLang, targetLang) {
+		return
+	}
+
+	entries, err := glossary.ParseEntries(req.Entries, req.EntriesFormat)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid entries: %v", err),
+		})
+		return
+	}
+
+	g, err := services.Glossaries().Create(req.Name, sourceLang, targetLang, entries)
+	if err != nil {
+		glossaryError(c, err)
+		return
+	}
+
+	logger.Info("Created glossary %s (%s -> %s, %d entries)", g.ID, g.SourceLang, g.TargetLang, len(g.Entries))
+	c.JSON(http.StatusCreated, newGlossaryInfo(g))
+}
+
+// HandleListGlossaries 列出术语表
+// @Summary      列出术语表
+// @Description  按创建时间返回当前密钥可以使用的术语表的信息，不包含词条
+// @Tags         术语表
+// @Produce      json
+// @Success      200  {object}  GlossaryListResponse
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossaries [get]
+func HandleListGlossaries(c *gin.Context) {
+	key := middleware.KeyFromContext(c)
+	infos := make([]GlossaryInfo, 0)
+	for _, g := range services.Glossaries().List() {
+		if key == nil || key.AllowsPair(g.SourceLang, g.TargetLang) {
+			infos = append(infos, newGlossaryInfo(g))
+		}
+	}
+	c.JSON(http.StatusOK, GlossaryListResponse{
+		Glossaries: infos,
+	})
+}
+
+// HandleGetGlossary 获取术语表信息
+// @Summary      获取术语表信息
+// @Description  返回术语表的信息，不包含词条
+// @Tags         术语表
+// @Produce      json
+// @Param        id   path      string  true  "术语表 ID"
+// @Success      200  {object}  GlossaryInfo
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossaries/{id} [get]
+func HandleGetGlossary(c *gin.Context) {
+	g, ok := getGlossary(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newGlossaryInfo(g))
+}
+
+// HandleGlossaryEntries 获取术语表词条
+// @Summary      获取术语表词条
+// @Description  以 TSV 格式返回术语表的全部词条
+// @Tags         术语表
+// @Produce      plain
+// @Param        id   path      string  true  "术语表 ID"
+// @Success      200  {string}  string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossaries/{id}/entries [get]
+func HandleGlossaryEntries(c *gin.Context) {
+	g, ok := getGlossary(c)
+	if !ok {
+		return
+	}
+	c.Data(http.StatusOK, "text/tab-separated-values; charset=utf-8", []byte(g.TSV()))
+}
+
+// HandleUpdateGlossary 修改术语表
+// @Summary      修改术语表
+// @Description  修改术语表名称或替换全部词条，语言对不能修改。正在进行的翻译继续使用修改前的词条
+// @Tags         术语表
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                 true  "术语表 ID"
+// @Param        request  body      UpdateGlossaryRequest  true  "修改术语表请求"
+// @Success      200      {object}  GlossaryInfo
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossaries/{id} [patch]
+func HandleUpdateGlossary(c *gin.Context) {
+	var req UpdateGlossaryRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var entries []glossary.Entry
+	if req.Entries != "" {
+		var err error
+		entries, err = glossary.ParseEntries(req.Entries, req.EntriesFormat)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Invalid entries: %v", err),
+			})
+			return
+		}
+	}
+
+	if _, ok := getGlossary(c); !ok {
+		return
+	}
+
+	g, err := services.Glossaries().Update(c.Param("id"), req.Name, entries)
+	if err != nil {
+		glossaryError(c, err)
+		return
+	}
+
+	logger.Info("Updated glossary %s (%d entries)", g.ID, len(g.Entries))
+	c.JSON(http.StatusOK, newGlossaryInfo(g))
+}
+
+// HandleDeleteGlossary 删除术语表
+// @Summary      删除术语表
+// @Description  删除术语表，之后引用它的翻译请求返回 404
+// @Tags         术语表
+// @Param        id   path  string  true  "术语表 ID"
+// @Success      204
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossaries/{id} [delete]
+func HandleDeleteGlossary(c *gin.Context) {
+	if _, ok := getGlossary(c); !ok {
+		return
+	}
+
+	id := c.Param("id")
+	if err := services.Glossaries().Delete(id); err != nil {
+		glossaryError(c, err)
+		return
+	}
+
+	logger.Info("Deleted glossary %s", id)
+	c.Status(http.StatusNoContent)
+}
+
+// HandleGlossaryLanguagePairs 可以创建术语表的语言对
+// @Summary      可以创建术语表的语言对
+// @Description  返回所有可翻译的语言对，包括经英语中转的语言对
+// @Tags         术语表
+// @Produce      json
+// @Success      200  {object}  GlossaryLanguagePairsResponse
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /glossary-language-pairs [get]
+func HandleGlossaryLanguagePairs(c *gin.Context) {
+	targets := services.SupportedTargets()
+	sources := make([]string, 0, len(targets))
+	for source := range targets {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	pairs := make([]GlossaryLanguagePair, 0)
+	for _, source := range sources {
+		for _, target := range targets[source] {
+			pairs = append(pairs, GlossaryLanguagePair{SourceLang: source, TargetLang: target})
+		}
+	}
+	c.JSON(http.StatusOK, GlossaryLanguagePairsResponse{
+		SupportedLanguages: pairs,
+	})
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/auth"
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/middleware"
+)
+
+func setupGlossaryRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	config.GetConfig().ConfigDir = t.TempDir()
+
+	r := gin.New()
+	r.POST("/glossaries", HandleCreateGlossary)
+	r.GET("/glossaries", HandleListGlossaries)
+	r.GET("/glossaries/:id", HandleGetGlossary)
+	r.PATCH("/glossaries/:id", HandleUpdateGlossary)
+	r.DELETE("/glossaries/:id", HandleDeleteGlossary)
+	r.GET("/glossaries/:id/entries", HandleGlossaryEntries)
+	r.POST("/translate", HandleTranslate)
+	r.POST("/deepl", HandleDeeplTranslate)
+	return r
+}
+
+func serveJSON(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGlossaryCRUD(t *testing.T) {
+	r := setupGlossaryRouter(t)
+
+	w := serveJSON(r, "POST", "/glossaries", `{"name":"product","source_lang":"EN","target_lang":"de","entries":"Acme Cloud,Acme Cloud\nlicense,Lizenz","entries_format":"csv"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var info GlossaryInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+	assert.Equal(t, "en", info.SourceLang)
+	assert.Equal(t, 2, info.EntryCount)
+	assert.True(t, info.Ready)
+
+	w = serveJSON(r, "GET", "/glossaries", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), info.GlossaryID)
+
+	w = serveJSON(r, "PATCH", "/glossaries/"+info.GlossaryID, `{"name":"legal","entries":"license\tLizenz"}`)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = serveJSON(r, "GET", "/glossaries/"+info.GlossaryID, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"legal"`)
+
+	w = serveJSON(r, "GET", "/glossaries/"+info.GlossaryID+"/entries", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "license\tLizenz\n", w.Body.String())
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/tab-separated-values")
+
+	w = serveJSON(r, "DELETE", "/glossaries/"+info.GlossaryID, "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	w = serveJSON(r, "GET", "/glossaries/"+info.GlossaryID, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGlossaryInvalidRequests(t *testing.T) {
+	r := setupGlossaryRouter(t)
+
+	w := serveJSON(r, "POST", "/glossaries", `{"name":"x","source_lang":"en","target_lang":"de","entries":"no tab"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveJSON(r, "POST", "/glossaries", `{"name":"x","source_lang":"en","target_lang":"en","entries":"a\tb"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveJSON(r, "PATCH", "/glossaries/missing", `{"name":"x"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestTranslateGlossaryMismatch(t *testing.T) {
+	r := setupGlossaryRouter(t)
+
+	w := serveJSON(r, "POST", "/glossaries", `{"name":"x","source_lang":"en","target_lang":"de","entries":"a\tb"}`)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var info GlossaryInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+
+	w = serveJSON(r, "POST", "/translate", `{"from":"en","to":"fr","text":"a","glossary":"`+info.GlossaryID+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serveJSON(r, "POST", "/translate", `{"from":"en","to":"de","text":"a","glossary":"missing"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serveJSON(r, "POST", "/deepl", `{"text":["a"],"target_lang":"DE","glossary_id":"`+info.GlossaryID+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "source_lang")
+}
+
+func TestGlossaryPairRestrictedKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	config.GetConfig().ConfigDir = t.TempDir()
+
+	store, err := auth.NewStore([]auth.Key{
+		{Name: "all", Token: "all"},
+		{Name: "de", Token: "de", Pairs: []string{"en-de"}},
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(middleware.RequireScope(&auth.Authenticator{Keys: store}, auth.ScopeTranslate))
+	r.POST("/glossaries", HandleCreateGlossary)
+	r.GET("/glossaries", HandleListGlossaries)
+	r.GET("/glossaries/:id", HandleGetGlossary)
+	r.PATCH("/glossaries/:id", HandleUpdateGlossary)
+	r.DELETE("/glossaries/:id", HandleDeleteGlossary)
+	r.GET("/glossaries/:id/entries", HandleGlossaryEntries)
+
+	serve := func(token, method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("KEY", token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("all", "POST", "/glossaries", `{"name":"ja","source_lang":"ja","target_lang":"en","entries":"a\tb"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var info GlossaryInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+
+	w = serve("de", "GET", "/glossaries", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), info.GlossaryID)
+
+	for _, req := range []struct{ method, path, body string }{
+		{"GET", "/glossaries/" + info.GlossaryID, ""},
+		{"GET", "/glossaries/" + info.GlossaryID + "/entries", ""},
+		{"PATCH", "/glossaries/" + info.GlossaryID, `{"name":"mine"}`},
+		{"DELETE", "/glossaries/" + info.GlossaryID, ""},
+	} {
+		w = serve("de", req.method, req.path, req.body)
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s", req.method, req.path)
+	}
+
+	w = serve("all", "GET", "/glossaries/"+info.GlossaryID, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"name":"ja"`)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -51,70 +50,48 @@ type GoogleTranslateResponse struct {
 // @Success      200      {object}  GoogleTranslateResponse
 // @Failure      400      {object}  map[string]string
 // @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Router       /google/language/translate/v2 [post]
-func HandleGoogleCompatTranslate(apiToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-
-		if apiToken != "" {
-
-			token := c.Query("key")
-
-			if token == "" {
-				authHeader := c.GetHeader("Authorization")
-				if strings.HasPrefix(authHeader, "Bearer ") {
-					token = strings.TrimPrefix(authHeader, "Bearer ")
-				} else if authHeader != "" {
-					token = authHeader
-				}
-			}
-
-			if token == "" {
-				token = c.Query("token")
-			}
-
-			if token != apiToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Unauthorized",
-				})
-				return
-			}
-		}
-
-		var req GoogleTranslateRequest
-
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
-
-		sourceBCP47 := utils.NormalizeLanguageCode(req.Source)
-		targetBCP47 := utils.NormalizeLanguageCode(req.Target)
-
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
-		defer cancel()
-
-		isHTML := req.Format == "html"
-		result, err := services.TranslateWithPivot(ctx, sourceBCP47, targetBCP47, req.Q, isHTML)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Translation failed: %v", err),
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"data": gin.H{
-				"translations": []gin.H{
-					{
-						"translatedText": result,
-					},
-				},
-			},
+func HandleGoogleCompatTranslate(c *gin.Context) {
+	var req GoogleTranslateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	sourceBCP47 := utils.NormalizeLanguageCode(req.Source)
+	targetBCP47 := utils.NormalizeLanguageCode(req.Target)
+
+	if !checkPairAllowed(c, sourceBCP47, targetBCP47) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	isHTML := req.Format == "html"
+	result, err := services.TranslateWithPivot(ctx, sourceBCP47, targetBCP47, req.Q, isHTML)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
 		})
+		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data": gin.H{
+			"translations": []gin.H{
+				{
+					"translatedText": result,
+				},
+			},
+		},
+	})
 }
 
 // HandleGoogleTranslateSingle Google translate_a/single 兼容接口
@@ -132,80 +109,59 @@ func HandleGoogleCompatTranslate(apiToken string) gin.HandlerFunc {
 // @Success      200     {array}   interface{}
 // @Failure      400     {object}  map[string]string
 // @Failure      401     {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
 // @Failure      500     {object}  map[string]string
 // @Router       /google/translate_a/single [get]
-func HandleGoogleTranslateSingle(apiToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-
-		if apiToken != "" {
-			token := c.Query("key")
-
-			if token == "" {
-				authHeader := c.GetHeader("Authorization")
-				if strings.HasPrefix(authHeader, "Bearer ") {
-					token = strings.TrimPrefix(authHeader, "Bearer ")
-				} else if authHeader != "" {
-					token = authHeader
-				}
-			}
-
-			if token == "" {
-				token = c.Query("token")
-			}
-
-			if token != apiToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Unauthorized",
-				})
-				return
-			}
-		}
-
-		sl := c.Query("sl")
-		tl := c.Query("tl")
-		q := c.Query("q")
-
-		if tl == "" || q == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Missing required parameters: tl, q",
-			})
-			return
-		}
-
-		if sl == "" {
-			sl = "auto"
-		}
-
-		text := q
-
-		sourceBCP47 := utils.NormalizeLanguageCode(sl)
-		targetBCP47 := utils.NormalizeLanguageCode(tl)
-
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
-		defer cancel()
-
-		result, err := services.TranslateWithPivot(ctx, sourceBCP47, targetBCP47, text, false)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Translation failed: %v", err),
-			})
-			return
-		}
-
-		detectedLang := convertBCP47ToGoogleLang(sourceBCP47)
-		response := []interface{}{
-			[]interface{}{
-				[]interface{}{result, text, nil, nil, 1},
-			},
-			nil,
-			detectedLang,
-			nil,
-			nil,
-			nil,
-			nil,
-			[]interface{}{},
-		}
-
-		c.JSON(http.StatusOK, response)
+func HandleGoogleTranslateSingle(c *gin.Context) {
+	sl := c.Query("sl")
+	tl := c.Query("tl")
+	q := c.Query("q")
+
+	if tl == "" || q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing required parameters: tl, q",
+		})
+		return
+	}
+
+	if sl == "" {
+		sl = "auto"
+	}
+
+	text := q
+
+	sourceBCP47 := utils.NormalizeLanguageCode(sl)
+	targetBCP47 := utils.NormalizeLanguageCode(tl)
+
+	if !checkPairAllowed(c, sourceBCP47, targetBCP47) {
+		return
 	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	result, err := services.TranslateWithPivot(ctx, sourceBCP47, targetBCP47, text, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
+		})
+		return
+	}
+
+	detectedLang := convertBCP47ToGoogleLang(sourceBCP47)
+	response := []interface{}{
+		[]interface{}{
+			[]interface{}{result, text, nil, nil, 1},
+		},
+		nil,
+		detectedLang,
+		nil,
+		nil,
+		nil,
+		nil,
+		[]interface{}{},
+	}
+
+	c.JSON(http.StatusOK, response)
 }
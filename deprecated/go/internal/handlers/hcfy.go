@@ -283,104 +283,80 @@ type HcfyTranslateResponse struct {
 // @Success      200      {object}  HcfyTranslateResponse
 // @Failure      400      {object}  map[string]string
 // @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Router       /hcfy [post]
-func HandleHcfyTranslate(apiToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-
-		if apiToken != "" {
-			token := c.Query("token")
-			if token == "" {
-				authHeader := c.GetHeader("Authorization")
-				if strings.HasPrefix(authHeader, "Bearer ") {
-					token = strings.TrimPrefix(authHeader, "Bearer ")
-				} else if authHeader != "" {
-					token = authHeader
-				}
-			}
-
-			if token != apiToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Unauthorized",
-				})
-				return
-			}
-		}
-
-		var req HcfyTranslateRequest
-
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
-
-		sourceLang := "auto"
-		if req.Source != "" {
-			sourceLang = convertHcfyLangToBCP47(req.Source)
-		}
+func HandleHcfyTranslate(c *gin.Context) {
+	var req HcfyTranslateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 
-		if len(req.Destination) == 0 {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "destination is required",
-			})
-			return
-		}
+	sourceLang := "auto"
+	if req.Source != "" {
+		sourceLang = convertHcfyLangToBCP47(req.Source)
+	}
 
-		targetLangName := req.Destination[0]
-		targetLang := convertHcfyLangToBCP47(targetLangName)
+	if len(req.Destination) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "destination is required",
+		})
+		return
+	}
 
-		detectedSourceLang := sourceLang
-		if sourceLang == "auto" {
+	targetLangName := req.Destination[0]
+	targetLang := convertHcfyLangToBCP47(targetLangName)
 
-			if containsChinese(req.Text) {
-				detectedSourceLang = "zh-Hans"
-			} else if containsJapanese(req.Text) {
-				detectedSourceLang = "ja"
-			} else if containsKorean(req.Text) {
-				detectedSourceLang = "ko"
-			} else {
-				detectedSourceLang = "en"
-			}
-		}
+	detectedSourceLang := sourceLang
+	if sourceLang == "auto" {
 
-		if detectedSourceLang == targetLang && len(req.Destination) > 1 {
-			targetLangName = req.Destination[1]
-			targetLang = convertHcfyLangToBCP47(targetLangName)
+		if containsChinese(req.Text) {
+			detectedSourceLang = "zh-Hans"
+		} else if containsJapanese(req.Text) {
+			detectedSourceLang = "ja"
+		} else if containsKorean(req.Text) {
+			detectedSourceLang = "ko"
+		} else {
+			detectedSourceLang = "en"
 		}
+	}
 
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
-		defer cancel()
-
-		paragraphs := strings.Split(req.Text, "\n")
-		results := make([]string, len(paragraphs))
+	if detectedSourceLang == targetLang && len(req.Destination) > 1 {
+		targetLangName = req.Destination[1]
+		targetLang = convertHcfyLangToBCP47(targetLangName)
+	}
 
-		for i, paragraph := range paragraphs {
-			if paragraph == "" {
-				results[i] = ""
-				continue
-			}
+	if !checkPairAllowed(c, detectedSourceLang, targetLang) {
+		return
+	}
 
-			result, err := services.TranslateWithPivot(ctx, detectedSourceLang, targetLang, paragraph, false)
-			if err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{
-					"error": fmt.Sprintf("Translation failed at paragraph %d: %v", i, err),
-				})
-				return
-			}
-			results[i] = result
-		}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
 
-		response := HcfyTranslateResponse{
-			Text:   req.Text,
-			From:   convertBCP47ToHcfyLang(detectedSourceLang),
-			To:     targetLangName,
-			Result: results,
+	paragraphs := strings.Split(req.Text, "\n")
+	results, errs := services.TranslateMany(ctx, detectedSourceLang, targetLang, paragraphs, false)
+	for i, err := range errs {
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": fmt.Sprintf("Translation failed at paragraph %d: %v", i, err),
+			})
+			return
 		}
+	}
 
-		c.JSON(http.StatusOK, response)
+	response := HcfyTranslateResponse{
+		Text:   req.Text,
+		From:   convertBCP47ToHcfyLang(detectedSourceLang),
+		To:     targetLangName,
+		Result: results,
 	}
+
+	c.JSON(http.StatusOK, response)
 }
 
 func containsChinese(text string) bool {
@@ -15,11 +15,14 @@ type ImmeTranslateRequest struct {
 	SourceLang string   `json:"source_lang" binding:"required" example:"en"`
 	TargetLang string   `json:"target_lang" binding:"required" example:"zh-CN"`
 	TextList   []string `json:"text_list" binding:"required" example:"Hello, world!,Good morning!"`
+	// PartialResults 部分结果模式：失败的文本附带 error，未开启时失败的文本静默返回原文
+	PartialResults bool `json:"partial_results" example:"false"`
 }
 
 type ImmeTranslation struct {
-	DetectedSourceLang string `json:"detected_source_lang" example:"en"`
-	Text               string `json:"text" example:"你好，世界！"`
+	DetectedSourceLang string     `json:"detected_source_lang" example:"en"`
+	Text               string     `json:"text" example:"你好，世界！"`
+	Error              *ItemError `json:"error,omitempty"`
 }
 
 type ImmeTranslateResponse struct {
@@ -32,61 +35,59 @@ type ImmeTranslateResponse struct {
 // @Tags         插件
 // @Accept       json
 // @Produce      json
-// @Param        token    query     string                  false  "API Token"
-// @Param        request  body      ImmeTranslateRequest    true   "沉浸式翻译请求"
-// @Success      200      {object}  ImmeTranslateResponse
-// @Failure      400      {object}  map[string]string
-// @Failure      401      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Param        token            query     string                false  "API Token"
+// @Param        request          body      ImmeTranslateRequest  true   "沉浸式翻译请求"
+// @Param        partial_results  query     bool                  false  "部分结果模式，单个文本失败时返回原文并附带错误"
+// @Success      200              {object}  ImmeTranslateResponse
+// @Failure      400              {object}  map[string]string
+// @Failure      401              {object}  map[string]string
+// @Failure      403              {object}  map[string]string
+// @Failure      429              {object}  map[string]string
+// @Failure      500              {object}  map[string]string
 // @Router       /imme [post]
-func HandleImmeTranslate(apiToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
+func HandleImmeTranslate(c *gin.Context) {
+	var req ImmeTranslateRequest
 
-		if apiToken != "" {
-			token := c.Query("token")
-			if token != apiToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Unauthorized",
-				})
-				return
-			}
-		}
-
-		var req ImmeTranslateRequest
-
-		if err := c.ShouldBindJSON(&req); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 
-		sourceLang := utils.NormalizeLanguageCode(req.SourceLang)
-		targetLang := utils.NormalizeLanguageCode(req.TargetLang)
+	sourceLang := utils.NormalizeLanguageCode(req.SourceLang)
+	targetLang := utils.NormalizeLanguageCode(req.TargetLang)
 
-		translations := make([]ImmeTranslation, len(req.TextList))
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
-		defer cancel()
+	if !checkPairAllowed(c, sourceLang, targetLang) {
+		return
+	}
 
-		logger.Debug("Imme request: %s -> %s, count: %d", sourceLang, targetLang, len(req.TextList))
-		for i, text := range req.TextList {
-			logger.Debug("Imme translating [%d/%d]: %s -> %s, text length: %d, text: %q", i+1, len(req.TextList), sourceLang, targetLang, len(text), text)
-			result, err := services.TranslateWithPivot(ctx, sourceLang, targetLang, text, true)
-			if err != nil {
-				logger.Error("Imme translation failed at index %d (%s -> %s): %v", i, sourceLang, targetLang, err)
-				result = text // Fallback to original text
-			} else {
-				logger.Debug("Imme translated [%d/%d] success", i+1, len(req.TextList))
-			}
+	translations := make([]ImmeTranslation, len(req.TextList))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
+	defer cancel()
 
-			translations[i] = ImmeTranslation{
-				DetectedSourceLang: req.SourceLang,
-				Text:               result,
-			}
+	logger.Debug("Imme request: %s -> %s, count: %d", sourceLang, targetLang, len(req.TextList))
+	results, errs := services.TranslateMany(ctx, sourceLang, targetLang, req.TextList, true)
+	for i, err := range errs {
+		if err != nil {
+			logger.Error("Imme translation failed at index %d (%s -> %s): %v", i, sourceLang, targetLang, err)
 		}
+	}
+	// 失败的文本返回原文
+	itemErrs := itemErrors(req.TextList, results, errs)
+	partial := partialResults(c, req.PartialResults)
 
-		c.JSON(http.StatusOK, ImmeTranslateResponse{
-			Translations: translations,
-		})
+	for i, result := range results {
+		translations[i] = ImmeTranslation{
+			DetectedSourceLang: req.SourceLang,
+			Text:               result,
+		}
+		if partial && itemErrs != nil {
+			translations[i].Error = itemErrs[i]
+		}
 	}
+
+	c.JSON(http.StatusOK, ImmeTranslateResponse{
+		Translations: translations,
+	})
 }
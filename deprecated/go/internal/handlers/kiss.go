@@ -26,11 +26,14 @@ type KissBatchTranslateRequest struct {
 	From  string   `json:"from" binding:"required" example:"auto"`
 	To    string   `json:"to" binding:"required" example:"zh-CN"`
 	Texts []string `json:"texts" binding:"required" example:"Hello,World"`
+	// PartialResults 部分结果模式：单个文本失败时返回原文并附带 error
+	PartialResults bool `json:"partial_results" example:"false"`
 }
 
 type KissBatchTranslateItem struct {
-	Text string `json:"text" example:"你好"`
-	Src  string `json:"src" example:"en"`
+	Text  string     `json:"text" example:"你好"`
+	Src   string     `json:"src" example:"en"`
+	Error *ItemError `json:"error,omitempty"`
 }
 
 type KissBatchTranslateResponse struct {
@@ -43,85 +46,80 @@ type KissBatchTranslateResponse struct {
 // @Tags         插件
 // @Accept       json
 // @Produce      json
-// @Param        KEY      header    string                false  "API Token"
-// @Param        request  body      KissTranslateRequest  true   "简约翻译请求"
-// @Success      200      {object}  KissTranslateResponse
-// @Failure      400      {object}  map[string]string
-// @Failure      401      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Param        KEY              header    string                false  "API Token"
+// @Param        request          body      KissTranslateRequest  true   "简约翻译请求"
+// @Param        partial_results  query     bool                  false  "部分结果模式，单个文本失败时返回原文并附带错误"
+// @Success      200              {object}  KissTranslateResponse
+// @Failure      400              {object}  map[string]string
+// @Failure      401              {object}  map[string]string
+// @Failure      403              {object}  map[string]string
+// @Failure      429              {object}  map[string]string
+// @Failure      500              {object}  map[string]string
 // @Router       /kiss [post]
-func HandleKissTranslate(apiToken string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-
-		if apiToken != "" {
-			token := c.GetHeader("KEY")
-			if token != apiToken {
-				c.JSON(http.StatusUnauthorized, gin.H{
-					"error": "Unauthorized",
-				})
-				return
-			}
-		}
-
-		var rawReq map[string]interface{}
-		if err := c.ShouldBindJSON(&rawReq); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": err.Error(),
-			})
-			return
-		}
+func HandleKissTranslate(c *gin.Context) {
+	var rawReq map[string]interface{}
+	if err := c.ShouldBindJSON(&rawReq); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
 
-		if texts, ok := rawReq["texts"].([]interface{}); ok && len(texts) > 0 {
+	if texts, ok := rawReq["texts"].([]interface{}); ok && len(texts) > 0 {
 
-			var batchReq KissBatchTranslateRequest
-			batchReq.From, _ = rawReq["from"].(string)
-			batchReq.To, _ = rawReq["to"].(string)
-			for _, t := range texts {
-				if str, ok := t.(string); ok {
-					batchReq.Texts = append(batchReq.Texts, str)
-				}
+		var batchReq KissBatchTranslateRequest
+		batchReq.From, _ = rawReq["from"].(string)
+		batchReq.To, _ = rawReq["to"].(string)
+		batchReq.PartialResults, _ = rawReq["partial_results"].(bool)
+		for _, t := range texts {
+			if str, ok := t.(string); ok {
+				batchReq.Texts = append(batchReq.Texts, str)
 			}
-			if batchReq.From == "" || batchReq.To == "" || len(batchReq.Texts) == 0 {
-				c.JSON(http.StatusBadRequest, gin.H{
-					"error": "Invalid batch request",
-				})
-				return
-			}
-			handleBatchTranslate(c, batchReq)
-			return
 		}
-
-		var req KissTranslateRequest
-		req.From, _ = rawReq["from"].(string)
-		req.To, _ = rawReq["to"].(string)
-		req.Text, _ = rawReq["text"].(string)
-
-		if req.From == "" || req.To == "" || req.Text == "" {
+		if batchReq.From == "" || batchReq.To == "" || len(batchReq.Texts) == 0 {
 			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Missing required fields: from, to, text",
+				"error": "Invalid batch request",
 			})
 			return
 		}
+		handleBatchTranslate(c, batchReq)
+		return
+	}
 
-		fromLang := utils.NormalizeLanguageCode(req.From)
-		toLang := utils.NormalizeLanguageCode(req.To)
+	var req KissTranslateRequest
+	req.From, _ = rawReq["from"].(string)
+	req.To, _ = rawReq["to"].(string)
+	req.Text, _ = rawReq["text"].(string)
 
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
-		defer cancel()
+	if req.From == "" || req.To == "" || req.Text == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Missing required fields: from, to, text",
+		})
+		return
+	}
 
-		result, err := services.TranslateWithPivot(ctx, fromLang, toLang, req.Text, false)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Translation failed: %v", err),
-			})
-			return
-		}
+	fromLang := utils.NormalizeLanguageCode(req.From)
+	toLang := utils.NormalizeLanguageCode(req.To)
+
+	if !checkPairAllowed(c, fromLang, toLang) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
 
-		c.JSON(http.StatusOK, gin.H{
-			"text": result,
-			"src":  req.From,
+	result, err := services.TranslateWithPivot(ctx, fromLang, toLang, req.Text, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
 		})
+		return
 	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"text": result,
+		"src":  req.From,
+	})
 }
 
 func handleBatchTranslate(c *gin.Context, req KissBatchTranslateRequest) {
@@ -129,22 +127,32 @@ func handleBatchTranslate(c *gin.Context, req KissBatchTranslateRequest) {
 	fromLang := utils.NormalizeLanguageCode(req.From)
 	toLang := utils.NormalizeLanguageCode(req.To)
 
+	if !checkPairAllowed(c, fromLang, toLang) {
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
 	defer cancel()
 
-	translations := make([]KissBatchTranslateItem, 0, len(req.Texts))
-	for _, text := range req.Texts {
-		result, err := services.TranslateWithPivot(ctx, fromLang, toLang, text, false)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Translation failed: %v", err),
-			})
-			return
-		}
-		translations = append(translations, KissBatchTranslateItem{
+	results, errs := services.TranslateMany(ctx, fromLang, toLang, req.Texts, false)
+	if err := services.FirstError(errs); err != nil && !partialResults(c, req.PartialResults) {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
+		})
+		return
+	}
+
+	itemErrs := itemErrors(req.Texts, results, errs)
+	translations := make([]KissBatchTranslateItem, 0, len(results))
+	for i, result := range results {
+		item := KissBatchTranslateItem{
 			Text: result,
 			Src:  req.From,
-		})
+		}
+		if itemErrs != nil {
+			item.Error = itemErrs[i]
+		}
+		translations = append(translations, item)
 	}
 
 	c.JSON(http.StatusOK, KissBatchTranslateResponse{
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// LibreTranslateRequest LibreTranslate 翻译请求，q 可以是字符串或字符串数组
+type LibreTranslateRequest struct {
+	Q      interface{} `json:"q" form:"-" swaggertype:"string" example:"Hello, world!"`
+	Source string      `json:"source" form:"source" example:"auto"`
+	Target string      `json:"target" form:"target" example:"zh-Hans"`
+	Format string      `json:"format" form:"format" example:"text"`
+	APIKey string      `json:"api_key" form:"api_key"`
+}
+
+// LibreDetectedLanguage 检测到的语言，confidence 取值 0-100
+type LibreDetectedLanguage struct {
+	Confidence float64 `json:"confidence" example:"90"`
+	Language   string  `json:"language" example:"en"`
+}
+
+// LibreTranslateResponse LibreTranslate 翻译响应。q 为数组时 translatedText 和 detectedLanguage 也为数组
+type LibreTranslateResponse struct {
+	TranslatedText   interface{} `json:"translatedText" swaggertype:"string" example:"你好，世界！"`
+	DetectedLanguage interface{} `json:"detectedLanguage,omitempty" swaggertype:"object"`
+}
+
+type LibreDetectRequest struct {
+	Q      string `json:"q" form:"q" example:"Hello, world!"`
+	APIKey string `json:"api_key" form:"api_key"`
+}
+
+type LibreLanguage struct {
+	Code    string   `json:"code" example:"en"`
+	Name    string   `json:"name" example:"English"`
+	Targets []string `json:"targets" example:"de,zh-Hans"`
+}
+
+// libreTexts 解析 q 参数，返回文本列表以及 q 是否为数组
+func libreTexts(c *gin.Context, q interface{}) ([]string, bool, error) {
+	if q == nil && c.ContentType() != binding.MIMEJSON {
+		values := c.PostFormArray("q")
+		if len(values) == 1 {
+			return values, false, nil
+		}
+		if len(values) > 1 {
+			return values, true, nil
+		}
+	}
+
+	switch v := q.(type) {
+	case string:
+		if v != "" {
+			return []string{v}, false, nil
+		}
+	case []interface{}:
+		texts := make([]string, len(v))
+		for i, item := range v {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false, fmt.Errorf("Invalid request: q[%d] must be a string", i)
+			}
+			texts[i] = text
+		}
+		return texts, true, nil
+	}
+	return nil, false, fmt.Errorf("Invalid request: missing q parameter")
+}
+
+func libreDetect(text string) LibreDetectedLanguage {
+	lang, confidence := services.DetectLanguageWithConfidence(text, 0)
+	return LibreDetectedLanguage{
+		Confidence: math.Round(confidence*10000) / 100,
+		Language:   lang,
+	}
+}
+
+// HandleLibreTranslate LibreTranslate 翻译兼容接口
+// @Summary      LibreTranslate 翻译兼容接口
+// @Description  兼容 LibreTranslate 的 /translate 接口，支持 JSON 和表单请求，q 可以是字符串或数组
+// @Tags         插件
+// @Accept       json,x-www-form-urlencoded
+// @Produce      json
+// @Param        request  body      LibreTranslateRequest   true  "LibreTranslate 翻译请求"
+// @Success      200      {object}  LibreTranslateResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Router       /libre/translate [post]
+func HandleLibreTranslate(c *gin.Context) {
+	var req LibreTranslateRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	texts, isArray, err := libreTexts(c, req.Q)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if req.Target == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request: missing target parameter",
+		})
+		return
+	}
+	if req.Format != "" && req.Format != "text" && req.Format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Invalid request: unsupported format %s", req.Format),
+		})
+		return
+	}
+
+	sourceLang := "auto"
+	if req.Source != "" && req.Source != "auto" {
+		sourceLang = utils.NormalizeLanguageCode(req.Source)
+	}
+	targetLang := utils.NormalizeLanguageCode(req.Target)
+
+	if !checkPairAllowed(c, sourceLang, targetLang) {
+		return
+	}
+
+	isHTML := req.Format == "html"
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
+	defer cancel()
+
+	results, errs := services.TranslateMany(ctx, sourceLang, targetLang, texts, isHTML)
+	if err := services.FirstError(errs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Translation failed: %v", err),
+		})
+		return
+	}
+
+	var detected []LibreDetectedLanguage
+	if sourceLang == "auto" {
+		detected = make([]LibreDetectedLanguage, len(texts))
+		for i, text := range texts {
+			detected[i] = libreDetect(text)
+		}
+	}
+
+	if isArray {
+		resp := LibreTranslateResponse{TranslatedText: results}
+		if detected != nil {
+			resp.DetectedLanguage = detected
+		}
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+
+	resp := LibreTranslateResponse{TranslatedText: results[0]}
+	if detected != nil {
+		resp.DetectedLanguage = detected[0]
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleLibreDetect LibreTranslate 语言检测兼容接口
+// @Summary      LibreTranslate 语言检测兼容接口
+// @Description  兼容 LibreTranslate 的 /detect 接口，返回检测到的语言及置信度（0-100）
+// @Tags         插件
+// @Accept       json,x-www-form-urlencoded
+// @Produce      json
+// @Param        request  body      LibreDetectRequest  true  "LibreTranslate 检测请求"
+// @Success      200      {array}   LibreDetectedLanguage
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Router       /libre/detect [post]
+func HandleLibreDetect(c *gin.Context) {
+	var req LibreDetectRequest
+
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if req.Q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid request: missing q parameter",
+		})
+		return
+	}
+
+	result := []LibreDetectedLanguage{}
+	if detected := libreDetect(req.Q); detected.Language != "" {
+		result = append(result, detected)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// HandleLibreLanguages LibreTranslate 语言列表兼容接口
+// @Summary      LibreTranslate 语言列表兼容接口
+// @Description  兼容 LibreTranslate 的 /languages 接口，返回每个语言可翻译到的目标语言（包括经英语中转）
+// @Tags         插件
+// @Produce      json
+// @Success      200  {array}   LibreLanguage
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /libre/languages [get]
+func HandleLibreLanguages(c *gin.Context) {
+	targets := services.SupportedTargets()
+	if targets == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Records not initialized",
+		})
+		return
+	}
+
+	languages := make([]LibreLanguage, 0, len(targets))
+	for code, list := range targets {
+		languages = append(languages, LibreLanguage{
+			Code:    code,
+			Name:    languageName(code),
+			Targets: list,
+		})
+	}
+	sort.Slice(languages, func(i, j int) bool {
+		return languages[i].Code < languages[j].Code
+	})
+
+	c.JSON(http.StatusOK, languages)
+}
+
+// languageName 返回语言代码的英文名称，无法识别时返回代码本身
+func languageName(code string) string {
+	tag, err := language.Parse(code)
+	if err != nil {
+		return code
+	}
+	if name := display.English.Tags().Name(tag); name != "" {
+		return name
+	}
+	return code
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func TestHandleLibreLanguages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalRecords := models.GlobalRecords
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "zh-Hans"},
+			{SourceLanguage: "en", TargetLanguage: "ja"},
+			{SourceLanguage: "ja", TargetLanguage: "en"},
+		},
+	}
+	defer func() {
+		models.GlobalRecords = originalRecords
+	}()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	HandleLibreLanguages(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var languages []LibreLanguage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &languages))
+	require.Len(t, languages, 3)
+
+	assert.Equal(t, LibreLanguage{Code: "en", Name: "English", Targets: []string{"ja", "zh-Hans"}}, languages[0])
+	assert.Equal(t, LibreLanguage{Code: "ja", Name: "Japanese", Targets: []string{"en", "zh-Hans"}}, languages[1])
+	assert.Equal(t, "zh-Hans", languages[2].Code)
+	assert.Empty(t, languages[2].Targets)
+}
+
+func TestHandleLibreTranslateInvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		errMsg      string
+	}{
+		{"MissingQ", "application/json", `{"source": "en", "target": "de"}`, "missing q"},
+		{"InvalidQItem", "application/json", `{"q": ["a", 1], "target": "de"}`, "q[1] must be a string"},
+		{"MissingTarget", "application/json", `{"q": "hello"}`, "missing target"},
+		{"UnsupportedFormat", "application/json", `{"q": "hello", "target": "de", "format": "markdown"}`, "unsupported format"},
+		{"FormMissingTarget", "application/x-www-form-urlencoded", "q=hello&source=en", "missing target"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", "/libre/translate", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			HandleLibreTranslate(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), tt.errMsg)
+		})
+	}
+}
+
+func TestHandleLibreDetect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/libre/detect", strings.NewReader("q=The+quick+brown+fox+jumps+over+the+lazy+dog"))
+	c.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	HandleLibreDetect(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result []LibreDetectedLanguage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	require.Len(t, result, 1)
+	assert.Equal(t, "en", result[0].Language)
+	assert.Greater(t, result[0].Confidence, 50.0)
+}
@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+const microsoftAPIVersion = "3.0"
+
+// rtlLanguages 从右到左书写的语言
+var rtlLanguages = map[string]bool{
+	"ar":  true,
+	"ckb": true,
+	"dv":  true,
+	"fa":  true,
+	"he":  true,
+	"ps":  true,
+	"ur":  true,
+	"yi":  true,
+}
+
+// MicrosoftTextItem Microsoft Translator 请求体中的单个文本
+type MicrosoftTextItem struct {
+	Text string `json:"Text" example:"Hello, world!"`
+}
+
+type MicrosoftDetectedLanguage struct {
+	Language string  `json:"language" example:"en"`
+	Score    float64 `json:"score" example:"0.95"`
+}
+
+type MicrosoftTranslation struct {
+	Text string `json:"text" example:"你好，世界！"`
+	To   string `json:"to" example:"zh-Hans"`
+}
+
+// MicrosoftTranslateResult 单个文本的翻译结果，未指定 from 时包含 detectedLanguage
+type MicrosoftTranslateResult struct {
+	DetectedLanguage *MicrosoftDetectedLanguage `json:"detectedLanguage,omitempty"`
+	Translations     []MicrosoftTranslation     `json:"translations"`
+}
+
+type MicrosoftDetectResult struct {
+	Language                   string  `json:"language" example:"en"`
+	Score                      float64 `json:"score" example:"0.95"`
+	IsTranslationSupported     bool    `json:"isTranslationSupported" example:"true"`
+	IsTransliterationSupported bool    `json:"isTransliterationSupported" example:"false"`
+}
+
+type MicrosoftLanguage struct {
+	Name       string `json:"name" example:"English"`
+	NativeName string `json:"nativeName" example:"English"`
+	Dir        string `json:"dir" example:"ltr"`
+}
+
+type MicrosoftLanguagesResponse struct {
+	Translation map[string]MicrosoftLanguage `json:"translation"`
+}
+
+// microsoftError 按 Microsoft Translator 的格式返回错误，code 为六位错误码
+func microsoftError(c *gin.Context, status int, code int, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// checkAPIVersion 校验 api-version 参数，未传时视为 3.0
+func checkAPIVersion(c *gin.Context) bool {
+	if version := c.Query("api-version"); version != "" && version != microsoftAPIVersion {
+		microsoftError(c, http.StatusBadRequest, 400021, "The API version parameter is missing or invalid.")
+		return false
+	}
+	return true
+}
+
+// bindMicrosoftTexts 解析 [{"Text": "..."}] 格式的请求体
+func bindMicrosoftTexts(c *gin.Context) ([]string, bool) {
+	var items []MicrosoftTextItem
+	if err := c.ShouldBindJSON(&items); err != nil {
+		microsoftError(c, http.StatusBadRequest, 400074, "The body of the request is not valid JSON.")
+		return nil, false
+	}
+	if len(items) == 0 {
+		microsoftError(c, http.StatusBadRequest, 400002, "The request body is empty.")
+		return nil, false
+	}
+
+	texts := make([]string, len(items))
+	for i, item := range items {
+		texts[i] = item.Text
+	}
+	return texts, true
+}
+
+func microsoftDetect(text string) MicrosoftDetectedLanguage {
+	lang, confidence := services.DetectLanguageWithConfidence(text, 0)
+	return MicrosoftDetectedLanguage{
+		Language: lang,
+		Score:    math.Round(confidence*100) / 100,
+	}
+}
+
+// HandleMicrosoftTranslate Microsoft Translator 翻译兼容接口
+// @Summary      Microsoft Translator 翻译兼容接口
+// @Description  兼容 Microsoft Translator v3 的 /translate 接口，to 可重复传入多个目标语言，未传 from 时返回检测到的语言
+// @Tags         插件
+// @Accept       json
+// @Produce      json
+// @Param        api-version  query     string               false  "API 版本"  default(3.0)
+// @Param        from         query     string               false  "源语言代码，不传时自动检测"
+// @Param        to           query     []string             true   "目标语言代码，可传多个"  collectionFormat(multi)
+// @Param        textType     query     string               false  "文本类型 plain 或 html"  default(plain)
+// @Param        request      body      []MicrosoftTextItem  true   "待翻译文本"
+// @Success      200          {array}   MicrosoftTranslateResult
+// @Failure      400          {object}  map[string]interface{}
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      429          {object}  map[string]string
+// @Failure      500          {object}  map[string]interface{}
+// @Router       /microsoft/translate [post]
+func HandleMicrosoftTranslate(c *gin.Context) {
+	if !checkAPIVersion(c) {
+		return
+	}
+
+	targets := c.QueryArray("to")
+	if len(targets) == 0 {
+		microsoftError(c, http.StatusBadRequest, 400036, "The target language is not valid.")
+		return
+	}
+
+	textType := c.DefaultQuery("textType", "plain")
+	if textType != "plain" && textType != "html" {
+		microsoftError(c, http.StatusBadRequest, 400064, "The textType parameter is not valid.")
+		return
+	}
+
+	texts, ok := bindMicrosoftTexts(c)
+	if !ok {
+		return
+	}
+
+	sourceLang := "auto"
+	if from := c.Query("from"); from != "" {
+		sourceLang = utils.NormalizeLanguageCode(from)
+	}
+
+	targetLangs := make([]string, len(targets))
+	for i, target := range targets {
+		targetLangs[i] = utils.NormalizeLanguageCode(target)
+		if !checkPairAllowed(c, sourceLang, targetLangs[i]) {
+			return
+		}
+	}
+
+	isHTML := textType == "html"
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
+	defer cancel()
+
+	results := make([]MicrosoftTranslateResult, len(texts))
+	for i := range results {
+		results[i].Translations = make([]MicrosoftTranslation, 0, len(targets))
+		if sourceLang == "auto" {
+			detected := microsoftDetect(texts[i])
+			results[i].DetectedLanguage = &detected
+		}
+	}
+
+	for i, targetLang := range targetLangs {
+		translated, errs := services.TranslateMany(ctx, sourceLang, targetLang, texts, isHTML)
+		if err := services.FirstError(errs); err != nil {
+			microsoftError(c, http.StatusInternalServerError, 500000, fmt.Sprintf("Translation failed: %v", err))
+			return
+		}
+		for j, text := range translated {
+			results[j].Translations = append(results[j].Translations, MicrosoftTranslation{
+				Text: text,
+				To:   targets[i],
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// HandleMicrosoftDetect Microsoft Translator 语言检测兼容接口
+// @Summary      Microsoft Translator 语言检测兼容接口
+// @Description  兼容 Microsoft Translator v3 的 /detect 接口，score 取值 0-1
+// @Tags         插件
+// @Accept       json
+// @Produce      json
+// @Param        api-version  query     string               false  "API 版本"  default(3.0)
+// @Param        request      body      []MicrosoftTextItem  true   "待检测文本"
+// @Success      200          {array}   MicrosoftDetectResult
+// @Failure      400          {object}  map[string]interface{}
+// @Failure      401          {object}  map[string]string
+// @Failure      429          {object}  map[string]string
+// @Router       /microsoft/detect [post]
+func HandleMicrosoftDetect(c *gin.Context) {
+	if !checkAPIVersion(c) {
+		return
+	}
+
+	texts, ok := bindMicrosoftTexts(c)
+	if !ok {
+		return
+	}
+
+	supported := services.SupportedTargets()
+	results := make([]MicrosoftDetectResult, len(texts))
+	for i, text := range texts {
+		detected := microsoftDetect(text)
+		results[i] = MicrosoftDetectResult{
+			Language:               detected.Language,
+			Score:                  detected.Score,
+			IsTranslationSupported: len(supported[detected.Language]) > 0,
+		}
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// HandleMicrosoftLanguages Microsoft Translator 语言列表兼容接口
+// @Summary      Microsoft Translator 语言列表兼容接口
+// @Description  兼容 Microsoft Translator v3 的 /languages 接口，仅返回 translation 范围
+// @Tags         插件
+// @Produce      json
+// @Param        api-version  query     string  false  "API 版本"  default(3.0)
+// @Success      200          {object}  MicrosoftLanguagesResponse
+// @Failure      400          {object}  map[string]interface{}
+// @Failure      401          {object}  map[string]string
+// @Failure      500          {object}  map[string]interface{}
+// @Router       /microsoft/languages [get]
+func HandleMicrosoftLanguages(c *gin.Context) {
+	if !checkAPIVersion(c) {
+		return
+	}
+
+	targets := services.SupportedTargets()
+	if targets == nil {
+		microsoftError(c, http.StatusInternalServerError, 500000, "Records not initialized")
+		return
+	}
+
+	translation := make(map[string]MicrosoftLanguage, len(targets))
+	for code := range targets {
+		translation[code] = MicrosoftLanguage{
+			Name:       languageName(code),
+			NativeName: nativeLanguageName(code),
+			Dir:        languageDir(code),
+		}
+	}
+
+	c.JSON(http.StatusOK, MicrosoftLanguagesResponse{Translation: translation})
+}
+
+// nativeLanguageName 返回语言的自称，无法识别时返回英文名称
+func nativeLanguageName(code string) string {
+	tag, err := language.Parse(code)
+	if err != nil {
+		return code
+	}
+	if name := display.Self.Name(tag); name != "" {
+		return name
+	}
+	return languageName(code)
+}
+
+func languageDir(code string) string {
+	base, _ := language.Make(code).Base()
+	if rtlLanguages[base.String()] {
+		return "rtl"
+	}
+	return "ltr"
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func TestHandleMicrosoftLanguages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalRecords := models.GlobalRecords
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "ar"},
+			{SourceLanguage: "en", TargetLanguage: "de"},
+		},
+	}
+	defer func() {
+		models.GlobalRecords = originalRecords
+	}()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/microsoft/languages?api-version=3.0", nil)
+
+	HandleMicrosoftLanguages(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var resp MicrosoftLanguagesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Translation, 3)
+	assert.Equal(t, MicrosoftLanguage{Name: "English", NativeName: "English", Dir: "ltr"}, resp.Translation["en"])
+	assert.Equal(t, MicrosoftLanguage{Name: "German", NativeName: "Deutsch", Dir: "ltr"}, resp.Translation["de"])
+	assert.Equal(t, "rtl", resp.Translation["ar"].Dir)
+}
+
+func TestHandleMicrosoftTranslateInvalidRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name string
+		url  string
+		body string
+		code int
+	}{
+		{"InvalidVersion", "/microsoft/translate?api-version=2.0&to=de", `[{"Text": "hello"}]`, 400021},
+		{"MissingTo", "/microsoft/translate?api-version=3.0", `[{"Text": "hello"}]`, 400036},
+		{"InvalidTextType", "/microsoft/translate?to=de&textType=xml", `[{"Text": "hello"}]`, 400064},
+		{"InvalidBody", "/microsoft/translate?to=de", `{"Text": "hello"}`, 400074},
+		{"EmptyBody", "/microsoft/translate?to=de", `[]`, 400002},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request, _ = http.NewRequest("POST", tt.url, bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			HandleMicrosoftTranslate(c)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var resp struct {
+				Error struct {
+					Code    int    `json:"code"`
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tt.code, resp.Error.Code)
+			assert.NotEmpty(t, resp.Error.Message)
+		})
+	}
+}
+
+func TestHandleMicrosoftDetect(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	originalRecords := models.GlobalRecords
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "de"},
+		},
+	}
+	defer func() {
+		models.GlobalRecords = originalRecords
+	}()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `[{"Text": "This is a simple English sentence for language detection."}]`
+	c.Request, _ = http.NewRequest("POST", "/microsoft/detect?api-version=3.0", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	HandleMicrosoftDetect(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var results []MicrosoftDetectResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "en", results[0].Language)
+	assert.True(t, results[0].IsTranslationSupported)
+	assert.Greater(t, results[0].Score, 0.0)
+	assert.LessOrEqual(t, results[0].Score, 1.0)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/models"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// handleListModels 列出模型
+// @Summary      列出模型
+// @Description  返回所有可用语言对模型的本地状态（downloaded/missing/outdated）和占用空间
+// @Tags         管理
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Failure      500  {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/models [get]
+func HandleListModels(c *gin.Context) {
+	list, err := models.ListModels()
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"models": list,
+	})
+}
+
+// handleDownloadModel 后台下载模型
+// @Summary      后台下载模型
+// @Description  在后台下载指定语言对的模型，同一语言对正在下载时返回现有任务
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Success      202   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/models/{from}/{to}/download [post]
+func HandleDownloadModel(c *gin.Context) {
+	from, to, ok := bindModelPair(c)
+	if !ok {
+		return
+	}
+
+	job, err := services.StartModelDownload(from, to)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// handleModelDownloadStatus 查询模型下载进度
+// @Summary      查询模型下载进度
+// @Description  返回指定语言对最近一次后台下载任务的状态和已下载字节数
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/models/{from}/{to}/download [get]
+func HandleModelDownloadStatus(c *gin.Context) {
+	from := utils.NormalizeLanguageCode(c.Param("from"))
+	to := utils.NormalizeLanguageCode(c.Param("to"))
+
+	job, ok := services.GetModelDownload(from, to)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "no download task for this model",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// handleDeleteModel 删除模型
+// @Summary      删除模型
+// @Description  停止使用该模型的引擎并删除本地模型目录
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/models/{from}/{to} [delete]
+func HandleDeleteModel(c *gin.Context) {
+	from, to, ok := bindModelPair(c)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteModel(from, to); err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+	})
+}
+
+// handleVerifyModel 校验模型
+// @Summary      校验模型
+// @Description  重新计算模型文件的哈希并与 DecompressedHash 比对
+// @Tags         管理
+// @Produce      json
+// @Param        from  path      string  true  "源语言"
+// @Param        to    path      string  true  "目标语言"
+// @Success      200   {object}  models.VerifyResult
+// @Failure      400   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /admin/models/{from}/{to}/verify [post]
+func HandleVerifyModel(c *gin.Context) {
+	from, to, ok := bindModelPair(c)
+	if !ok {
+		return
+	}
+
+	result, err := models.VerifyModel(from, to)
+	if err != nil {
+		respondAdminError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func bindModelPair(c *gin.Context) (string, string, bool) {
+	from := utils.NormalizeLanguageCode(c.Param("from"))
+	to := utils.NormalizeLanguageCode(c.Param("to"))
+
+	if err := models.ValidateLanguagePair(from, to); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return "", "", false
+	}
+	return from, to, true
+}
@@ -0,0 +1,559 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// openaiModel 模型名称，"mtranserver-<目标语言>" 形式的模型固定目标语言
+const openaiModel = "mtranserver"
+
+type OpenAIChatMessage struct {
+	Role string `json:"role" example:"user"`
+	// Content 字符串，或 [{"type": "text", "text": "..."}] 形式的内容数组
+	Content interface{} `json:"content" swaggertype:"string" example:"Translate the following text to German:\n\nHello, world!"`
+}
+
+type OpenAIChatRequest struct {
+	Model    string              `json:"model" example:"mtranserver"`
+	Messages []OpenAIChatMessage `json:"messages" binding:"required"`
+	Stream   bool                `json:"stream" example:"false"`
+}
+
+type OpenAIChatChoice struct {
+	Index        int                `json:"index"`
+	Message      *OpenAIChatMessage `json:"message,omitempty"`
+	Delta        *OpenAIChatMessage `json:"delta,omitempty"`
+	FinishReason *string            `json:"finish_reason"`
+}
+
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIChatResponse chat.completion 响应，流式时为 chat.completion.chunk
+type OpenAIChatResponse struct {
+	ID      string             `json:"id" example:"chatcmpl-0123456789abcdef"`
+	Object  string             `json:"object" example:"chat.completion"`
+	Created int64              `json:"created"`
+	Model   string             `json:"model" example:"mtranserver"`
+	Choices []OpenAIChatChoice `json:"choices"`
+	Usage   *OpenAIUsage       `json:"usage,omitempty"`
+}
+
+type OpenAIModel struct {
+	ID      string `json:"id" example:"mtranserver"`
+	Object  string `json:"object" example:"model"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by" example:"mtranserver"`
+}
+
+type OpenAIModelList struct {
+	Object string        `json:"object" example:"list"`
+	Data   []OpenAIModel `json:"data"`
+}
+
+// openaiError 以 OpenAI 的错误格式返回，兼容客户端读取 error.message
+func openaiError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"message": message,
+			"type":    "invalid_request_error",
+		},
+	})
+}
+
+// HandleOpenAIModels OpenAI 模型列表兼容接口
+// @Summary      OpenAI 模型列表兼容接口
+// @Description  返回可用的模型：mtranserver 从提示词中识别目标语言，mtranserver-<语言> 固定目标语言
+// @Tags         插件
+// @Produce      json
+// @Success      200  {object}  OpenAIModelList
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Router       /v1/models [get]
+func HandleOpenAIModels(c *gin.Context) {
+	targets := services.SupportedTargets()
+	if targets == nil {
+		openaiError(c, http.StatusInternalServerError, "Records not initialized")
+		return
+	}
+
+	langs := make(map[string]bool)
+	for _, list := range targets {
+		for _, lang := range list {
+			langs[lang] = true
+		}
+	}
+	codes := make([]string, 0, len(langs))
+	for lang := range langs {
+		codes = append(codes, lang)
+	}
+	sort.Strings(codes)
+
+	created := time.Now().Unix()
+	data := make([]OpenAIModel, 0, len(codes)+1)
+	data = append(data, OpenAIModel{ID: openaiModel, Object: "model", Created: created, OwnedBy: openaiModel})
+	for _, code := range codes {
+		data = append(data, OpenAIModel{ID: openaiModel + "-" + code, Object: "model", Created: created, OwnedBy: openaiModel})
+	}
+
+	c.JSON(http.StatusOK, OpenAIModelList{
+		Object: "list",
+		Data:   data,
+	})
+}
+
+// HandleOpenAIChatCompletions OpenAI Chat Completions 兼容接口
+// @Summary      OpenAI Chat Completions 兼容接口
+// @Description  从翻译插件常用的提示词中识别源语言、目标语言和待翻译文本，返回 chat.completion 对象；stream=true 时按行以 SSE 返回 chat.completion.chunk
+// @Tags         插件
+// @Accept       json
+// @Produce      json,text/event-stream
+// @Param        request  body      OpenAIChatRequest  true  "Chat Completions 请求"
+// @Success      200      {object}  OpenAIChatResponse
+// @Failure      400      {object}  map[string]interface{}
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Failure      500      {object}  map[string]interface{}
+// @Router       /v1/chat/completions [post]
+func HandleOpenAIChatCompletions(c *gin.Context) {
+	var req OpenAIChatRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		openaiError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	prompt, err := parseTranslationPrompt(req.Model, req.Messages)
+	if err != nil {
+		openaiError(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !checkPairAllowed(c, prompt.from, prompt.to) {
+		return
+	}
+
+	if req.Model == "" {
+		req.Model = openaiModel
+	}
+
+	logger.Debug("OpenAI chat request: %s -> %s, text length: %d, stream: %v", prompt.from, prompt.to, len(prompt.text), req.Stream)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
+	defer cancel()
+
+	if req.Stream {
+		streamChatCompletion(ctx, c, req.Model, prompt)
+		return
+	}
+
+	result, err := services.TranslateWithPivot(ctx, prompt.from, prompt.to, prompt.text, false)
+	if err != nil {
+		openaiError(c, http.StatusInternalServerError, fmt.Sprintf("Translation failed: %v", err))
+		return
+	}
+
+	stop := "stop"
+	promptTokens := messagesLength(req.Messages)
+	completionTokens := utf8.RuneCountInString(result)
+	c.JSON(http.StatusOK, OpenAIChatResponse{
+		ID:      newCompletionID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []OpenAIChatChoice{{
+			Message:      &OpenAIChatMessage{Role: "assistant", Content: result},
+			FinishReason: &stop,
+		}},
+		Usage: &OpenAIUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	})
+}
+
+// streamChatCompletion 逐行翻译并以 SSE 发送，每行一个 chunk，最后发送 [DONE]
+func streamChatCompletion(ctx context.Context, c *gin.Context, model string, prompt translationPrompt) {
+	id := newCompletionID()
+	created := time.Now().Unix()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	send := func(delta *OpenAIChatMessage, finishReason *string) bool {
+		data, err := json.Marshal(OpenAIChatResponse{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   model,
+			Choices: []OpenAIChatChoice{{Delta: delta, FinishReason: finishReason}},
+		})
+		if err != nil {
+			return false
+		}
+		if _, err := fmt.Fprintf(c.Writer, "data: %s\n\n", data); err != nil {
+			return false
+		}
+		c.Writer.Flush()
+		return true
+	}
+
+	if !send(&OpenAIChatMessage{Role: "assistant", Content: ""}, nil) {
+		return
+	}
+
+	lines := strings.Split(prompt.text, "\n")
+	for i, line := range lines {
+		result := line
+		if strings.TrimSpace(line) != "" {
+			var err error
+			result, err = services.TranslateWithPivot(ctx, prompt.from, prompt.to, line, false)
+			if err != nil {
+				logger.Error("OpenAI stream translation failed at line %d (%s -> %s): %v", i, prompt.from, prompt.to, err)
+				fmt.Fprintf(c.Writer, "data: {\"error\":{\"message\":%q,\"type\":\"server_error\"}}\n\n", fmt.Sprintf("Translation failed: %v", err))
+				c.Writer.Flush()
+				return
+			}
+		}
+		if i < len(lines)-1 {
+			result += "\n"
+		}
+		if !send(&OpenAIChatMessage{Content: result}, nil) {
+			return
+		}
+	}
+
+	stop := "stop"
+	if !send(&OpenAIChatMessage{}, &stop) {
+		return
+	}
+	fmt.Fprint(c.Writer, "data: [DONE]\n\n")
+	c.Writer.Flush()
+}
+
+func newCompletionID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return "chatcmpl-" + hex.EncodeToString(b)
+}
+
+func messagesLength(messages []OpenAIChatMessage) int {
+	n := 0
+	for _, m := range messages {
+		n += utf8.RuneCountInString(messageText(m))
+	}
+	return n
+}
+
+// messageText 返回消息的文本内容，内容数组中只取 text 部分
+func messageText(m OpenAIChatMessage) string {
+	switch content := m.Content.(type) {
+	case string:
+		return content
+	case []interface{}:
+		var parts []string
+		for _, part := range content {
+			if p, ok := part.(map[string]interface{}); ok {
+				if text, ok := p["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	return ""
+}
+
+type translationPrompt struct {
+	from string
+	to   string
+	text string
+}
+
+var (
+	targetPattern    = regexp.MustCompile(`(?i)\b(?:to|into)\s+((?:[^\s:：,，.。;"'()]+|\([^)]*\))(?:\s+(?:[^\s:：,，.。;"'()]+|\([^)]*\))){0,2})`)
+	sourcePattern    = regexp.MustCompile(`(?i)\bfrom\s+((?:[^\s:：,，.。;"'()]+|\([^)]*\))(?:\s+(?:[^\s:：,，.。;"'()]+|\([^)]*\))){0,2})\s+(?:to|into)\b`)
+	zhTargetPattern  = regexp.MustCompile(`(?:翻译成|翻译为|译成|译为|翻译到)\s*([^\s:：,，.。;“”"]+)`)
+	zhSourcePattern  = regexp.MustCompile(`(?:从|将)\s*([^\s:：,，.。;“”"从将]+?)\s*(?:翻译|译)`)
+	sourceTextMarker = regexp.MustCompile(`(?is)source\s*text\s*[:：]\s*(.*?)\s*(?:translated\s*text\s*[:：].*)?$`)
+	quotedText       = regexp.MustCompile("(?s)(?:\"\"\"|```)\\s*\\n?(.*?)\\n?\\s*(?:\"\"\"|```)")
+)
+
+// parseTranslationPrompt 从对话中识别翻译参数。
+// 目标语言优先取模型名中的语言，其次在最后一条用户消息中查找，再到其它消息（如 system）中查找；
+// 指令出现在最后一条用户消息中时，待翻译文本为 Source Text 标记、三引号代码块或指令行之后的内容，
+// 否则整条用户消息即为待翻译文本
+func parseTranslationPrompt(model string, messages []OpenAIChatMessage) (translationPrompt, error) {
+	last := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			last = i
+			break
+		}
+	}
+	if last < 0 {
+		return translationPrompt{}, errors.New("no user message found")
+	}
+
+	content := messageText(messages[last])
+	prompt := translationPrompt{from: "auto"}
+	langs := supportedLanguages()
+
+	if lang, ok := strings.CutPrefix(model, openaiModel+"-"); ok {
+		code, ok := resolveLanguageName(langs, lang)
+		if !ok {
+			return translationPrompt{}, fmt.Errorf("unsupported model: %s", model)
+		}
+		prompt.to = code
+	}
+
+	instructionEnd := -1
+	if isInstruction(content) {
+		if to, end, ok := findTargetLanguage(langs, content); ok {
+			if prompt.to == "" {
+				prompt.to = to
+			}
+			instructionEnd = end
+			if from, ok := findSourceLanguage(langs, content); ok {
+				prompt.from = from
+			}
+		}
+	}
+
+	if instructionEnd < 0 {
+		for i, m := range messages {
+			if i == last || (m.Role != "system" && m.Role != "user" && m.Role != "developer") {
+				continue
+			}
+			text := messageText(m)
+			if !isInstruction(text) {
+				continue
+			}
+			if to, _, ok := findTargetLanguage(langs, text); ok {
+				if prompt.to == "" {
+					prompt.to = to
+				}
+				if from, ok := findSourceLanguage(langs, text); ok {
+					prompt.from = from
+				}
+				break
+			}
+		}
+	}
+
+	if prompt.to == "" {
+		return translationPrompt{}, errors.New("could not determine the target language from the prompt, use a model such as mtranserver-de or include \"to <language>\"")
+	}
+
+	prompt.text = extractPromptText(content, instructionEnd)
+	if strings.TrimSpace(prompt.text) == "" {
+		return translationPrompt{}, errors.New("no text to translate found in the prompt")
+	}
+
+	return prompt, nil
+}
+
+var instructionPattern = regexp.MustCompile(`(?i)translat|翻译|译成|译为`)
+
+func isInstruction(text string) bool {
+	return instructionPattern.MatchString(text)
+}
+
+func extractPromptText(content string, instructionEnd int) string {
+	if m := sourceTextMarker.FindStringSubmatch(content); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	if m := quotedText.FindStringSubmatch(content); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+	if instructionEnd < 0 {
+		return strings.TrimSpace(content)
+	}
+
+	rest := content[instructionEnd:]
+	if i := strings.IndexAny(rest, "\n"); i >= 0 {
+		return strings.TrimSpace(rest[i+1:])
+	}
+	if i := strings.IndexAny(rest, ":："); i >= 0 {
+		_, size := utf8.DecodeRuneInString(rest[i:])
+		return strings.TrimSpace(rest[i+size:])
+	}
+	return ""
+}
+
+// findTargetLanguage 返回识别到的目标语言及指令结束位置
+func findTargetLanguage(langs languageSet, text string) (string, int, bool) {
+	for _, m := range targetPattern.FindAllStringSubmatchIndex(text, -1) {
+		if code, n, ok := resolveLanguageWords(langs, text[m[2]:m[3]]); ok {
+			return code, m[2] + n, true
+		}
+	}
+	if m := zhTargetPattern.FindStringSubmatchIndex(text); m != nil {
+		if code, ok := resolveLanguageName(langs, text[m[2]:m[3]]); ok {
+			return code, m[3], true
+		}
+	}
+	return "", 0, false
+}
+
+func findSourceLanguage(langs languageSet, text string) (string, bool) {
+	if m := sourcePattern.FindStringSubmatch(text); m != nil {
+		if code, _, ok := resolveLanguageWords(langs, m[1]); ok {
+			return code, true
+		}
+	}
+	if m := zhSourcePattern.FindStringSubmatch(text); m != nil {
+		if code, ok := resolveLanguageName(langs, m[1]); ok {
+			return code, true
+		}
+	}
+	return "", false
+}
+
+// englishWords 与语言代码相同的常见英文单词，在英文指令中不视为语言代码
+var englishWords = map[string]bool{
+	"am": true, "an": true, "as": true, "at": true, "be": true, "by": true, "do": true, "go": true,
+	"he": true, "if": true, "in": true, "is": true, "it": true, "me": true, "my": true, "no": true,
+	"of": true, "on": true, "or": true, "so": true, "to": true, "up": true, "us": true, "we": true,
+}
+
+// resolveLanguageWords 依次尝试最长的前几个词作为语言名，返回语言代码和所用文本的长度
+func resolveLanguageWords(langs languageSet, s string) (string, int, bool) {
+	words := strings.Fields(s)
+	for n := len(words); n > 0; n-- {
+		candidate := strings.Join(words[:n], " ")
+		if englishWords[candidate] {
+			continue
+		}
+		if code, ok := resolveLanguageName(langs, candidate); ok {
+			return code, strings.Index(s, words[n-1]) + len(words[n-1]), true
+		}
+	}
+	return "", 0, false
+}
+
+// languageSet 支持的语言代码，每个请求构建一次后传给语言名解析函数
+type languageSet map[string]bool
+
+func supportedLanguages() languageSet {
+	targets := services.SupportedTargets()
+	langs := make(languageSet, len(targets))
+	for source, list := range targets {
+		langs[source] = true
+		for _, target := range list {
+			langs[target] = true
+		}
+	}
+	return langs
+}
+
+// resolveLanguageName 将语言代码或英文、中文、本地语言名称解析为 langs 中的语言代码
+func resolveLanguageName(langs languageSet, name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", false
+	}
+
+	lower := strings.ToLower(name)
+	switch lower {
+	case "auto", "auto-detect", "auto detect", "detect":
+		return "", false
+	case "chinese (simplified)", "中文", "汉语", "简中":
+		lower = "zh-hans"
+	case "chinese (traditional)", "繁中":
+		lower = "zh-hant"
+	}
+
+	if code := utils.NormalizeLanguageCode(lower); langs[code] && isLanguageCode(lower) {
+		return code, true
+	}
+
+	candidates := []string{lower}
+	if strings.HasSuffix(name, "文") {
+		candidates = append(candidates, strings.TrimSuffix(name, "文")+"语")
+	}
+
+	codes := make([]string, 0, len(langs))
+	for code := range langs {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	// 先匹配完整名称（如 Traditional Chinese），再匹配基础语言名（如 Chinese）。
+	// 多个变体共用基础语言名时优先默认变体，其次取代码排序最前的，保证结果稳定
+	for _, full := range []bool{true, false} {
+		var matched []string
+		for _, code := range codes {
+			tag, err := language.Parse(code)
+			if err != nil {
+				continue
+			}
+			var names []string
+			if full {
+				names = []string{display.English.Tags().Name(tag), display.Self.Name(tag), display.Chinese.Tags().Name(tag)}
+			} else {
+				base, _ := tag.Base()
+				names = []string{display.English.Languages().Name(base), display.Chinese.Languages().Name(base)}
+			}
+			if matchesLanguageName(names, candidates) {
+				matched = append(matched, code)
+			}
+		}
+		for _, code := range matched {
+			if base, _ := language.Make(code).Base(); utils.NormalizeLanguageCode(base.String()) == code {
+				return code, true
+			}
+		}
+		if len(matched) > 0 {
+			return matched[0], true
+		}
+	}
+
+	// "Chinese" 等基础语言名映射到默认变体
+	if code := utils.NormalizeLanguageCode(lower); langs[code] {
+		return code, true
+	}
+	return "", false
+}
+
+func matchesLanguageName(names, candidates []string) bool {
+	for _, n := range names {
+		for _, candidate := range candidates {
+			if n != "" && strings.EqualFold(n, candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}([-_][a-z0-9]{2,8})*$`)
+
+func isLanguageCode(s string) bool {
+	return languageCodePattern.MatchString(s)
+}
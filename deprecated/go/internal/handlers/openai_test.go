@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func setupOpenAIRecords(t *testing.T) {
+	t.Helper()
+
+	originalRecords := models.GlobalRecords
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "zh-Hans"},
+			{SourceLanguage: "zh-Hans", TargetLanguage: "en"},
+			{SourceLanguage: "en", TargetLanguage: "de"},
+			{SourceLanguage: "de", TargetLanguage: "en"},
+			{SourceLanguage: "en", TargetLanguage: "ja"},
+			{SourceLanguage: "en", TargetLanguage: "it"},
+		},
+	}
+	t.Cleanup(func() {
+		models.GlobalRecords = originalRecords
+	})
+}
+
+func TestParseTranslationPrompt(t *testing.T) {
+	setupOpenAIRecords(t)
+
+	system := OpenAIChatMessage{Role: "system", Content: "You are a professional, authentic machine translation engine."}
+
+	tests := []struct {
+		name     string
+		model    string
+		messages []OpenAIChatMessage
+		want     translationPrompt
+	}{
+		{
+			name:     "ImmersiveTranslate",
+			messages: []OpenAIChatMessage{system, {Role: "user", Content: "Translate the following source text to Simplified Chinese, Output translation directly without any additional text.\nSource Text: Hello, world!\n\nTranslated Text:"}},
+			want:     translationPrompt{from: "auto", to: "zh-Hans", text: "Hello, world!"},
+		},
+		{
+			name:     "FromTo",
+			messages: []OpenAIChatMessage{system, {Role: "user", Content: "Translate the following source text from English to 简体中文. Output translation directly without any additional text.\n\nSource Text: Good morning\n\nTranslated Text:"}},
+			want:     translationPrompt{from: "en", to: "zh-Hans", text: "Good morning"},
+		},
+		{
+			name:     "TripleQuotes",
+			messages: []OpenAIChatMessage{{Role: "user", Content: "Translate from German to English:\n\n\"\"\"\nGuten Tag\n\"\"\""}},
+			want:     translationPrompt{from: "de", to: "en", text: "Guten Tag"},
+		},
+		{
+			name:     "InstructionLine",
+			messages: []OpenAIChatMessage{{Role: "user", Content: "Translate into Japanese:\nIt is a nice day.\nSee you."}},
+			want:     translationPrompt{from: "auto", to: "ja", text: "It is a nice day.\nSee you."},
+		},
+		{
+			name:     "InlineColon",
+			messages: []OpenAIChatMessage{{Role: "user", Content: "Please translate to de: Hello there"}},
+			want:     translationPrompt{from: "auto", to: "de", text: "Hello there"},
+		},
+		{
+			name: "SystemInstruction",
+			messages: []OpenAIChatMessage{
+				{Role: "system", Content: "Translate everything the user says into Italian."},
+				{Role: "user", Content: "I want to go home, it is late."},
+			},
+			want: translationPrompt{from: "auto", to: "it", text: "I want to go home, it is late."},
+		},
+		{
+			name:     "Chinese",
+			messages: []OpenAIChatMessage{{Role: "user", Content: "请将以下内容从英文翻译成德语：\nGood night"}},
+			want:     translationPrompt{from: "en", to: "de", text: "Good night"},
+		},
+		{
+			name:     "ModelTarget",
+			model:    "mtranserver-de",
+			messages: []OpenAIChatMessage{{Role: "user", Content: []interface{}{map[string]interface{}{"type": "text", "text": "Hello"}}}},
+			want:     translationPrompt{from: "auto", to: "de", text: "Hello"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTranslationPrompt(tt.model, tt.messages)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTranslationPromptErrors(t *testing.T) {
+	setupOpenAIRecords(t)
+
+	_, err := parseTranslationPrompt("", []OpenAIChatMessage{{Role: "user", Content: "Hello there"}})
+	assert.ErrorContains(t, err, "target language")
+
+	_, err = parseTranslationPrompt("", []OpenAIChatMessage{{Role: "system", Content: "Translate to German."}})
+	assert.ErrorContains(t, err, "no user message")
+
+	_, err = parseTranslationPrompt("", []OpenAIChatMessage{{Role: "user", Content: "Translate to German:"}})
+	assert.ErrorContains(t, err, "no text")
+
+	_, err = parseTranslationPrompt("mtranserver-xx", []OpenAIChatMessage{{Role: "user", Content: "Hello"}})
+	assert.ErrorContains(t, err, "unsupported model")
+}
+
+func TestResolveLanguageNameSharedBase(t *testing.T) {
+	langs := languageSet{"en": true, "zh-Hans": true, "zh-Hant": true, "pt": true, "pt-BR": true}
+
+	for i := 0; i < 20; i++ {
+		for name, want := range map[string]string{
+			"Chinese":              "zh-Hans",
+			"中文":                   "zh-Hans",
+			"Traditional Chinese":  "zh-Hant",
+			"Portuguese":           "pt",
+			"Brazilian Portuguese": "pt-BR",
+		} {
+			got, ok := resolveLanguageName(langs, name)
+			assert.True(t, ok, name)
+			assert.Equal(t, want, got, name)
+		}
+	}
+}
+
+func TestHandleOpenAIModels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupOpenAIRecords(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	HandleOpenAIModels(c)
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list OpenAIModelList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.Equal(t, "list", list.Object)
+
+	ids := make([]string, 0, len(list.Data))
+	for _, m := range list.Data {
+		ids = append(ids, m.ID)
+	}
+	assert.Equal(t, []string{"mtranserver", "mtranserver-de", "mtranserver-en", "mtranserver-it", "mtranserver-ja", "mtranserver-zh-Hans"}, ids)
+}
+
+func TestHandleOpenAIChatCompletionsInvalidPrompt(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	setupOpenAIRecords(t)
+
+	body, _ := json.Marshal(OpenAIChatRequest{
+		Model:    "mtranserver",
+		Messages: []OpenAIChatMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/v1/chat/completions", bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	HandleOpenAIChatCompletions(c)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var resp map[string]map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "invalid_request_error", resp["error"]["type"])
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/services"
+)
+
+// 单个文本翻译失败时的错误码
+const (
+	ItemErrorTimeout            = "timeout"
+	ItemErrorCanceled           = "canceled"
+	ItemErrorInsufficientMemory = "insufficient_memory"
+	ItemErrorTranslationFailed  = "translation_failed"
+)
+
+// ItemError 部分结果模式下单个文本的翻译错误，该文本的译文为原文
+type ItemError struct {
+	Code    string `json:"code" enums:"timeout,canceled,insufficient_memory,translation_failed" example:"timeout"`
+	Message string `json:"message" example:"context deadline exceeded"`
+}
+
+func newItemError(err error) *ItemError {
+	if err == nil {
+		return nil
+	}
+
+	code := ItemErrorTranslationFailed
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		code = ItemErrorTimeout
+	case errors.Is(err, context.Canceled):
+		code = ItemErrorCanceled
+	case errors.Is(err, services.ErrInsufficientMemory):
+		code = ItemErrorInsufficientMemory
+	}
+	return &ItemError{Code: code, Message: err.Error()}
+}
+
+// partialResults 判断请求是否开启部分结果模式：请求体中的 partial_results 或同名查询参数为 true。
+// 开启后单个文本失败不会使整个请求失败，而是返回原文并在该项附带错误
+func partialResults(c *gin.Context, enabled bool) bool {
+	if enabled {
+		return true
+	}
+	v, _ := strconv.ParseBool(c.Query("partial_results"))
+	return v
+}
+
+// itemErrors 把失败的文本替换为原文，返回每个文本的错误，全部成功时返回 nil
+func itemErrors(texts, results []string, errs []error) []*ItemError {
+	var items []*ItemError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if items == nil {
+			items = make([]*ItemError, len(errs))
+		}
+		items[i] = newItemError(err)
+		results[i] = texts[i]
+	}
+	return items
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/services"
+)
+
+func TestNewItemError(t *testing.T) {
+	assert.Nil(t, newItemError(nil))
+	assert.Equal(t, ItemErrorTimeout, newItemError(fmt.Errorf("item 0: %w", context.DeadlineExceeded)).Code)
+	assert.Equal(t, ItemErrorCanceled, newItemError(context.Canceled).Code)
+	assert.Equal(t, ItemErrorInsufficientMemory, newItemError(services.ErrInsufficientMemory).Code)
+
+	item := newItemError(errors.New("worker crashed"))
+	assert.Equal(t, ItemErrorTranslationFailed, item.Code)
+	assert.Equal(t, "worker crashed", item.Message)
+}
+
+func TestItemErrors(t *testing.T) {
+	texts := []string{"a", "b", "c"}
+
+	results := []string{"A", "B", "C"}
+	assert.Nil(t, itemErrors(texts, results, make([]error, 3)))
+
+	results = []string{"A", "", "C"}
+	items := itemErrors(texts, results, []error{nil, context.DeadlineExceeded, nil})
+	require.Len(t, items, 3)
+	assert.Nil(t, items[0])
+	assert.Equal(t, ItemErrorTimeout, items[1].Code)
+	assert.Equal(t, []string{"A", "b", "C"}, results)
+}
+
+func TestPartialResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(target string) *gin.Context {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("POST", target, nil)
+		return c
+	}
+
+	assert.True(t, partialResults(newContext("/imme?partial_results=true"), false))
+	assert.False(t, partialResults(newContext("/imme"), false))
+	assert.True(t, partialResults(newContext("/imme"), true))
+}
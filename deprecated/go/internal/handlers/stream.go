@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/services"
+)
+
+const (
+	// streamConcurrency 流式翻译同时进行的分段数
+	streamConcurrency = 4
+	// streamChunkTimeout 单个分段的翻译超时，整个请求不设超时
+	streamChunkTimeout = 60 * time.Second
+)
+
+// TranslateStreamChunk chunk 事件，start/end 为分段在原文中的字节偏移
+type TranslateStreamChunk struct {
+	Index  int    `json:"index" example:"0"`
+	Start  int    `json:"start" example:"0"`
+	End    int    `json:"end" example:"13"`
+	Text   string `json:"text" example:"Hello, world!"`
+	Result string `json:"result" example:"你好，世界！"`
+}
+
+// TranslateStreamError error 事件，该分段在最终结果中保留原文
+type TranslateStreamError struct {
+	Index int    `json:"index" example:"0"`
+	Start int    `json:"start" example:"0"`
+	End   int    `json:"end" example:"13"`
+	Error string `json:"error"`
+}
+
+// TranslateStreamSummary done 事件，result 为按原文空白拼接的完整译文
+type TranslateStreamSummary struct {
+	Chunks    int    `json:"chunks" example:"1"`
+	Failed    int    `json:"failed" example:"0"`
+	Result    string `json:"result" example:"你好，世界！"`
+	ElapsedMs int64  `json:"elapsed_ms" example:"120"`
+}
+
+type streamResult struct {
+	chunk  services.Chunk
+	result string
+	err    error
+}
+
+// HandleTranslateStream 流式翻译
+// @Summary      流式翻译
+// @Description  将长文本按行和句子切分后并行翻译，以 SSE 逐段返回：每段完成后发送 chunk 事件（可能乱序，按 index 和偏移定位），
+// @Description  失败的分段发送 error 事件，最后发送 done 事件。术语表和分句模式与 /translate 相同，HTML 和 Markdown 文本不切分
+// @Tags         翻译
+// @Accept       json
+// @Produce      text/event-stream
+// @Param        request  body      TranslateRequest  true  "翻译请求"
+// @Success      200      {object}  TranslateStreamChunk
+// @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /translate/stream [post]
+func HandleTranslateStream(c *gin.Context) {
+	var req TranslateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	format, g, ok := checkTranslateRequest(c, &req)
+	if !ok {
+		return
+	}
+
+	// HTML 和 Markdown 切分后会破坏结构，整体作为一个分段翻译
+	var chunks []services.Chunk
+	if format == "text" {
+		chunks = services.SplitChunks(req.Text, req.From, services.MaxChunkLength)
+	} else {
+		chunks = []services.Chunk{{Text: req.Text, End: len(req.Text)}}
+	}
+
+	translate := func(ctx context.Context, text string) (string, error) {
+		if format == "markdown" {
+			return services.TranslateMarkdown(ctx, req.From, req.To, text, g)
+		}
+		return services.TranslateWithGlossary(ctx, req.From, req.To, text, format == "html", g)
+	}
+
+	logger.Debug("Stream translation request: %s -> %s, format: %s, text length: %d, chunks: %d", req.From, req.To, format, len(req.Text), len(chunks))
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	ctx := services.WithSplitSentences(c.Request.Context(), req.SplitSentences)
+	started := time.Now()
+	results := translateChunks(ctx, chunks, translate)
+
+	translated := make([]string, len(chunks))
+	failed := 0
+	for r := range results {
+		if r.err != nil {
+			logger.Error("Stream translation failed at chunk %d (%s -> %s): %v", r.chunk.Index, req.From, req.To, r.err)
+			failed++
+			translated[r.chunk.Index] = r.chunk.Text
+			c.SSEvent("error", TranslateStreamError{
+				Index: r.chunk.Index,
+				Start: r.chunk.Start,
+				End:   r.chunk.End,
+				Error: fmt.Sprintf("Translation failed: %v", r.err),
+			})
+		} else {
+			translated[r.chunk.Index] = r.result
+			c.SSEvent("chunk", TranslateStreamChunk{
+				Index:  r.chunk.Index,
+				Start:  r.chunk.Start,
+				End:    r.chunk.End,
+				Text:   r.chunk.Text,
+				Result: r.result,
+			})
+		}
+		c.Writer.Flush()
+	}
+
+	if ctx.Err() != nil {
+		logger.Debug("Stream translation cancelled by client: %s -> %s", req.From, req.To)
+		return
+	}
+
+	c.SSEvent("done", TranslateStreamSummary{
+		Chunks:    len(chunks),
+		Failed:    failed,
+		Result:    services.JoinChunks(req.Text, chunks, translated),
+		ElapsedMs: time.Since(started).Milliseconds(),
+	})
+	c.Writer.Flush()
+
+	logger.Debug("Stream translation completed: %s -> %s, chunks: %d, failed: %d", req.From, req.To, len(chunks), failed)
+}
+
+// translateChunks 并行翻译各分段，按完成顺序发送结果，全部完成后关闭通道
+func translateChunks(ctx context.Context, chunks []services.Chunk, translate func(context.Context, string) (string, error)) <-chan streamResult {
+	results := make(chan streamResult, len(chunks))
+	sem := make(chan struct{}, streamConcurrency)
+
+	go func() {
+		defer close(results)
+
+		var wg sync.WaitGroup
+		for _, chunk := range chunks {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				break
+			}
+
+			wg.Add(1)
+			go func(chunk services.Chunk) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				chunkCtx, cancel := context.WithTimeout(ctx, streamChunkTimeout)
+				defer cancel()
+
+				result, err := translate(chunkCtx, chunk.Text)
+				results <- streamResult{chunk: chunk, result: result, err: err}
+			}(chunk)
+		}
+
+		wg.Wait()
+	}()
+
+	return results
+}
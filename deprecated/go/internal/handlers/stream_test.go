@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleTranslateStreamValidation(t *testing.T) {
+	r := setupGlossaryRouter(t)
+	r.POST("/translate/stream", HandleTranslateStream)
+
+	w := serveJSON(r, "POST", "/translate/stream", `{"from":"en","to":"de","text":"Hello","format":"rst"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Unsupported format")
+
+	w = serveJSON(r, "POST", "/translate/stream", `{"from":"en","to":"de","text":"Hello","split_sentences":"always"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "split_sentences")
+
+	w = serveJSON(r, "POST", "/translate/stream", `{"from":"en","to":"de","text":"Hello","glossary":"missing"}`)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/services"
 	"github.com/xxnuo/MTranServer/internal/version"
 )
 
@@ -55,3 +56,16 @@ func HandleHeartbeat(c *gin.Context) {
 func HandleLBHeartbeat(c *gin.Context) {
 	c.String(http.StatusOK, "Ready")
 }
+
+// handleCacheStats 翻译缓存统计
+// @Summary      翻译缓存统计
+// @Description  返回翻译结果缓存的容量、命中和未命中次数
+// @Tags         系统
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /cache/stats [get]
+func HandleCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, services.GetCacheStats())
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/xxnuo/MTranServer/internal/glossary"
 	"github.com/xxnuo/MTranServer/internal/logger"
 	"github.com/xxnuo/MTranServer/internal/services"
 	"github.com/xxnuo/MTranServer/internal/utils"
@@ -18,6 +19,12 @@ type TranslateRequest struct {
 	To   string `json:"to" binding:"required" example:"zh-Hans"`
 	Text string `json:"text" binding:"required" example:"Hello, world!"`
 	HTML bool   `json:"html" example:"false"`
+	// Format 文本格式：text、html 或 markdown，为空时由 html 决定。markdown 只翻译正文，代码、链接地址和 front matter 保持不变
+	Format string `json:"format" enums:"text,html,markdown" example:"text"`
+	// Glossary 术语表 ID，术语表的语言对必须与 from、to 一致
+	Glossary string `json:"glossary"`
+	// SplitSentences 分句模式：0 不分句，1 按标点和换行分句，nonewlines 只按标点分句，为空时使用服务配置
+	SplitSentences string `json:"split_sentences" enums:"0,1,nonewlines" example:"1"`
 }
 
 // TranslateResponse 翻译响应
@@ -27,13 +34,16 @@ type TranslateResponse struct {
 
 // handleTranslate 单文本翻译
 // @Summary      单文本翻译
-// @Description  翻译单个文本
+// @Description  翻译单个文本，支持纯文本、HTML 和 Markdown，可以指定术语表
 // @Tags         翻译
 // @Accept       json
 // @Produce      json
 // @Param        request  body      TranslateRequest  true  "翻译请求"
 // @Success      200      {object}  TranslateResponse
 // @Failure      400      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      429      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Security     ApiKeyAuth
 // @Security     ApiKeyQuery
@@ -48,14 +58,23 @@ func HandleTranslate(c *gin.Context) {
 		return
 	}
 
-	req.From = utils.NormalizeLanguageCode(req.From)
-	req.To = utils.NormalizeLanguageCode(req.To)
+	format, g, ok := checkTranslateRequest(c, &req)
+	if !ok {
+		return
+	}
 
-	logger.Debug("Translation request: %s -> %s, text length: %d", req.From, req.To, len(req.Text))
+	logger.Debug("Translation request: %s -> %s, format: %s, text length: %d", req.From, req.To, format, len(req.Text))
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
-
-	result, err := services.TranslateWithPivot(ctx, req.From, req.To, req.Text, req.HTML)
+	ctx = services.WithSplitSentences(ctx, req.SplitSentences)
+
+	var result string
+	var err error
+	if format == "markdown" {
+		result, err = services.TranslateMarkdown(ctx, req.From, req.To, req.Text, g)
+	} else {
+		result, err = services.TranslateWithGlossary(ctx, req.From, req.To, req.Text, format == "html", g)
+	}
 	if err != nil {
 		logger.Error("Translation failed (%s -> %s): %v", req.From, req.To, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -75,22 +94,31 @@ type TranslateBatchRequest struct {
 	To    string   `json:"to" binding:"required" example:"zh-Hans"`
 	Texts []string `json:"texts" binding:"required" example:"Hello, world!,Good morning!"`
 	HTML  bool     `json:"html" example:"false"`
+	// SplitSentences 分句模式：0 不分句，1 按标点和换行分句，nonewlines 只按标点分句，为空时使用服务配置
+	SplitSentences string `json:"split_sentences" enums:"0,1,nonewlines" example:"1"`
+	// PartialResults 部分结果模式：单个文本失败时返回原文，并在 errors 的对应位置给出错误
+	PartialResults bool `json:"partial_results" example:"false"`
 }
 
 type TranslateBatchResponse struct {
 	Results []string `json:"results" example:"你好，世界！,早上好！"`
+	// Errors 仅在部分结果模式下有文本失败时返回，与 results 一一对应，成功的文本为 null
+	Errors []*ItemError `json:"errors,omitempty"`
 }
 
 // handleTranslateBatch 批量翻译
 // @Summary      批量翻译
-// @Description  批量翻译多个文本
+// @Description  批量翻译多个文本。默认任一文本失败时整个请求返回 500；partial_results 为 true 时返回其余文本的译文和失败文本的错误
 // @Tags         翻译
 // @Accept       json
 // @Produce      json
-// @Param        request  body      TranslateBatchRequest  true  "批量翻译请求"
-// @Success      200      {object}  TranslateBatchResponse
-// @Failure      400      {object}  map[string]string
-// @Failure      500      {object}  map[string]string
+// @Param        request          body      TranslateBatchRequest  true   "批量翻译请求"
+// @Param        partial_results  query     bool                   false  "部分结果模式，单个文本失败时返回原文并附带错误"
+// @Success      200              {object}  TranslateBatchResponse
+// @Failure      400              {object}  map[string]string
+// @Failure      403              {object}  map[string]string
+// @Failure      429              {object}  map[string]string
+// @Failure      500              {object}  map[string]string
 // @Security     ApiKeyAuth
 // @Security     ApiKeyQuery
 // @Router       /translate/batch [post]
@@ -104,28 +132,84 @@ func HandleTranslateBatch(c *gin.Context) {
 		return
 	}
 
+	if !checkSplitSentences(c, req.SplitSentences) {
+		return
+	}
+
 	req.From = utils.NormalizeLanguageCode(req.From)
 	req.To = utils.NormalizeLanguageCode(req.To)
 
+	if !checkPairAllowed(c, req.From, req.To) {
+		return
+	}
+
 	logger.Debug("Batch translation request: %s -> %s, count: %d", req.From, req.To, len(req.Texts))
-	results := make([]string, len(req.Texts))
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 120*time.Second)
 	defer cancel()
+	ctx = services.WithSplitSentences(ctx, req.SplitSentences)
 
-	for i, text := range req.Texts {
-		result, err := services.TranslateWithPivot(ctx, req.From, req.To, text, req.HTML)
-		if err != nil {
-			logger.Error("Batch translation failed at index %d (%s -> %s): %v", i, req.From, req.To, err)
+	results, errs := services.TranslateMany(ctx, req.From, req.To, req.Texts, req.HTML)
+	if err := services.FirstError(errs); err != nil {
+		logger.Error("Batch translation failed (%s -> %s): %v", req.From, req.To, err)
+		if !partialResults(c, req.PartialResults) {
 			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": fmt.Sprintf("Translation failed at index %d: %v", i, err),
+				"error": fmt.Sprintf("Translation failed: %v", err),
 			})
 			return
 		}
-		results[i] = result
 	}
 
 	logger.Debug("Batch translation completed: %s -> %s, count: %d", req.From, req.To, len(req.Texts))
-	c.JSON(http.StatusOK, gin.H{
-		"results": results,
+	c.JSON(http.StatusOK, TranslateBatchResponse{
+		Results: results,
+		Errors:  itemErrors(req.Texts, results, errs),
+	})
+}
+
+// checkTranslateRequest 校验单文本翻译请求的格式、分句模式、语言对和术语表，并规范化语言代码。
+// 返回实际使用的格式（text、html 或 markdown），校验失败时已返回错误
+func checkTranslateRequest(c *gin.Context, req *TranslateRequest) (string, *glossary.Glossary, bool) {
+	format := req.Format
+	if format == "" {
+		format = "text"
+		if req.HTML {
+			format = "html"
+		}
+	}
+	switch format {
+	case "text", "html", "markdown":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Unsupported format: %s", req.Format),
+		})
+		return "", nil, false
+	}
+	if !checkSplitSentences(c, req.SplitSentences) {
+		return "", nil, false
+	}
+
+	req.From = utils.NormalizeLanguageCode(req.From)
+	req.To = utils.NormalizeLanguageCode(req.To)
+
+	if !checkPairAllowed(c, req.From, req.To) {
+		return "", nil, false
+	}
+
+	g, ok := resolveGlossary(c, req.Glossary, req.From, req.To)
+	if !ok {
+		return "", nil, false
+	}
+	return format, g, true
+}
+
+// checkSplitSentences 校验分句模式，不合法时返回 400 并终止请求
+func checkSplitSentences(c *gin.Context, mode string) bool {
+	if services.ValidSplitSentences(mode) {
+		return true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": fmt.Sprintf("Unsupported split_sentences: %s, must be 0, 1 or nonewlines", mode),
 	})
+	c.Abort()
+	return false
 }
@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/xxnuo/MTranServer/internal/auth"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/middleware"
+	"github.com/xxnuo/MTranServer/internal/services"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+const (
+	wsMaxMessageSize = 1 << 20
+	// wsMaxInFlight 单个连接同时进行的翻译数
+	wsMaxInFlight  = 32
+	wsPingInterval = 30 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsWriteWait    = 10 * time.Second
+	wsTransTimeout = 60 * time.Second
+)
+
+// WebSocket 消息类型
+const (
+	WSTypeTranslate = "translate"
+	WSTypeCancel    = "cancel"
+	WSTypeResult    = "result"
+	WSTypeError     = "error"
+	WSTypeCancelled = "cancelled"
+)
+
+// 与 CORS 中间件一致，允许任意来源
+var wsUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// WSTranslateMessage 客户端消息，type 为 translate（默认）或 cancel，id 由客户端指定
+type WSTranslateMessage struct {
+	ID   string `json:"id" example:"1"`
+	Type string `json:"type,omitempty" example:"translate"`
+	From string `json:"from,omitempty" example:"en"`
+	To   string `json:"to,omitempty" example:"zh-Hans"`
+	Text string `json:"text,omitempty" example:"Hello, world!"`
+	HTML bool   `json:"html,omitempty" example:"false"`
+}
+
+// WSTranslateResponse 服务端消息，type 为 result、error 或 cancelled，结果按完成顺序返回
+type WSTranslateResponse struct {
+	ID     string `json:"id" example:"1"`
+	Type   string `json:"type" example:"result"`
+	Result string `json:"result,omitempty" example:"你好，世界！"`
+	Error  string `json:"error,omitempty"`
+	// RetryAfter 触发限流或配额时建议等待的秒数
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// wsSession 一个 WebSocket 连接的状态
+type wsSession struct {
+	conn  *websocket.Conn
+	key   *auth.Key
+	allow middleware.AllowFunc
+	ctx   context.Context
+
+	writeMu  sync.Mutex
+	mu       sync.Mutex
+	inflight map[string]*wsRequest
+	wg       sync.WaitGroup
+}
+
+// wsRequest 进行中的请求，按指针区分取消后复用同一 id 的新请求
+type wsRequest struct {
+	cancel context.CancelFunc
+}
+
+// HandleWebSocket WebSocket 翻译接口
+// @Summary      WebSocket 翻译接口
+// @Description  建立持久连接后发送 JSON 消息 {"id","type","from","to","text","html"}，type 默认为 translate。
+// @Description  发送 {"id","type":"cancel"} 可取消进行中的请求。结果按完成顺序返回 {"id","type":"result","result"}，
+// @Description  失败时返回 {"id","type":"error","error"}，取消后返回 {"id","type":"cancelled"}。浏览器可通过 token 参数鉴权
+// @Tags         翻译
+// @Param        token  query  string  false  "API Token"
+// @Success      101    {object}  WSTranslateResponse
+// @Failure      401    {object}  map[string]string
+// @Failure      429    {object}  map[string]string
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Router       /ws [get]
+func HandleWebSocket(c *gin.Context) {
+	conn, err := wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Debug("WebSocket upgrade failed from %s: %v", c.ClientIP(), err)
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	s := &wsSession{
+		conn:     conn,
+		key:      middleware.KeyFromContext(c),
+		allow:    middleware.AllowFromContext(c),
+		ctx:      ctx,
+		inflight: make(map[string]*wsRequest),
+	}
+
+	logger.Debug("WebSocket connected from %s", c.ClientIP())
+	go s.pingLoop()
+	s.readLoop()
+
+	cancel()
+	s.wg.Wait()
+	logger.Debug("WebSocket disconnected from %s", c.ClientIP())
+}
+
+func (s *wsSession) readLoop() {
+	s.conn.SetReadLimit(wsMaxMessageSize)
+	s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var msg WSTranslateMessage
+		if err := s.conn.ReadJSON(&msg); err != nil {
+			if _, ok := err.(*websocket.CloseError); !ok {
+				logger.Debug("WebSocket read failed: %v", err)
+			}
+			return
+		}
+		s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		switch msg.Type {
+		case "", WSTypeTranslate:
+			s.translate(msg)
+		case WSTypeCancel:
+			s.cancel(msg.ID)
+		default:
+			s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: fmt.Sprintf("Unknown message type %s", msg.Type)})
+		}
+	}
+}
+
+func (s *wsSession) pingLoop() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+			s.writeMu.Unlock()
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) send(resp WSTranslateResponse) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := s.conn.WriteJSON(resp); err != nil {
+		logger.Debug("WebSocket write failed: %v", err)
+	}
+}
+
+func (s *wsSession) translate(msg WSTranslateMessage) {
+	if msg.ID == "" {
+		s.send(WSTranslateResponse{Type: WSTypeError, Error: "Invalid request: missing id"})
+		return
+	}
+	if msg.To == "" || msg.Text == "" {
+		s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: "Invalid request: missing to or text"})
+		return
+	}
+
+	fromLang := "auto"
+	if msg.From != "" {
+		fromLang = utils.NormalizeLanguageCode(msg.From)
+	}
+	toLang := utils.NormalizeLanguageCode(msg.To)
+
+	if s.key != nil && !s.key.AllowsPair(fromLang, toLang) {
+		s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: fmt.Sprintf("API key is not allowed to translate %s -> %s", fromLang, toLang)})
+		return
+	}
+
+	if decision := s.allow(utf8.RuneCountInString(msg.Text)); !decision.Allowed {
+		resp := WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: "Too many requests", RetryAfter: middleware.RetryAfterSeconds(decision)}
+		if decision.QuotaExceeded {
+			resp.Error = "Quota exceeded"
+		}
+		s.send(resp)
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.inflight[msg.ID]; ok {
+		s.mu.Unlock()
+		s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: fmt.Sprintf("Request %s is already in progress", msg.ID)})
+		return
+	}
+	if len(s.inflight) >= wsMaxInFlight {
+		s.mu.Unlock()
+		s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: "Too many requests in progress"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, wsTransTimeout)
+	req := &wsRequest{cancel: cancel}
+	s.inflight[msg.ID] = req
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+
+		result, err := services.TranslateWithPivot(ctx, fromLang, toLang, msg.Text, msg.HTML)
+
+		// 已被取消的请求不再返回结果
+		s.mu.Lock()
+		active := s.inflight[msg.ID] == req
+		if active {
+			delete(s.inflight, msg.ID)
+		}
+		s.mu.Unlock()
+		if !active || s.ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			logger.Error("WebSocket translation failed (%s -> %s): %v", fromLang, toLang, err)
+			s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeError, Error: fmt.Sprintf("Translation failed: %v", err)})
+			return
+		}
+		s.send(WSTranslateResponse{ID: msg.ID, Type: WSTypeResult, Result: result})
+	}()
+}
+
+func (s *wsSession) cancel(id string) {
+	s.mu.Lock()
+	req, ok := s.inflight[id]
+	delete(s.inflight, id)
+	s.mu.Unlock()
+
+	if !ok {
+		s.send(WSTranslateResponse{ID: id, Type: WSTypeError, Error: fmt.Sprintf("Request %s is not in progress", id)})
+		return
+	}
+	req.cancel()
+	s.send(WSTranslateResponse{ID: id, Type: WSTypeCancelled})
+}
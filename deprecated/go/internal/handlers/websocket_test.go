@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/auth"
+	"github.com/xxnuo/MTranServer/internal/middleware"
+)
+
+func dialWebSocket(t *testing.T, r *gin.Engine) *websocket.Conn {
+	t.Helper()
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws?token=secret"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestHandleWebSocketInvalidMessages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/ws", HandleWebSocket)
+	conn := dialWebSocket(t, r)
+
+	tests := []struct {
+		name string
+		msg  WSTranslateMessage
+		want WSTranslateResponse
+	}{
+		{"MissingID", WSTranslateMessage{To: "de", Text: "hello"}, WSTranslateResponse{Type: WSTypeError, Error: "Invalid request: missing id"}},
+		{"MissingText", WSTranslateMessage{ID: "1", To: "de"}, WSTranslateResponse{ID: "1", Type: WSTypeError, Error: "Invalid request: missing to or text"}},
+		{"UnknownType", WSTranslateMessage{ID: "2", Type: "ping"}, WSTranslateResponse{ID: "2", Type: WSTypeError, Error: "Unknown message type ping"}},
+		{"CancelUnknown", WSTranslateMessage{ID: "3", Type: WSTypeCancel}, WSTranslateResponse{ID: "3", Type: WSTypeError, Error: "Request 3 is not in progress"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, conn.WriteJSON(tt.msg))
+
+			var resp WSTranslateResponse
+			require.NoError(t, conn.ReadJSON(&resp))
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestHandleWebSocketPairNotAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store, err := auth.NewStore([]auth.Key{
+		{Name: "de-only", Token: "secret", Pairs: []string{"en-de"}},
+	})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.GET("/ws", middleware.RequireScope(&auth.Authenticator{Keys: store}, auth.ScopeTranslate), HandleWebSocket)
+	conn := dialWebSocket(t, r)
+
+	require.NoError(t, conn.WriteJSON(WSTranslateMessage{ID: "1", From: "en", To: "ja", Text: "hello"}))
+
+	var resp WSTranslateResponse
+	require.NoError(t, conn.ReadJSON(&resp))
+	assert.Equal(t, WSTranslateResponse{ID: "1", Type: WSTypeError, Error: "API key is not allowed to translate en -> ja"}, resp)
+}
@@ -0,0 +1,242 @@
+package i18n
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/textfile"
+	"gopkg.in/yaml.v3"
+)
+
+// Format 资源文件格式
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+var formats = textfile.NewFormats("i18n",
+	textfile.Format{Name: string(FormatJSON), ContentType: "application/json; charset=utf-8"},
+	textfile.Format{Name: string(FormatYAML), Aliases: []string{"yml"}, ContentType: "application/yaml; charset=utf-8"},
+)
+
+// ParseFormat 解析格式名称，支持 json、yaml 和 yml
+func ParseFormat(name string) (Format, error) {
+	f, err := formats.Parse(name)
+	return Format(f), err
+}
+
+// ContentType 返回格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	return formats.ContentType(string(f))
+}
+
+// Unit 一个待翻译的字符串值，译文通过 File.Apply 写回
+type Unit struct {
+	Text string
+
+	node *yaml.Node
+}
+
+// File 解析后的资源文件。JSON 和 YAML 都解析为 yaml.Node 树，保留键的顺序和 YAML 注释
+type File struct {
+	Format Format
+
+	// doc YAML 为 DocumentNode，JSON 为顶层值
+	doc  *yaml.Node
+	root *yaml.Node
+	// localeRoot 根键是语言代码（Rails 风格的 en:），合并时跳过
+	localeRoot bool
+	// done 已经使用现有译文的字符串
+	done map[*yaml.Node]bool
+
+	// indent JSON 为缩进字符串（为空表示紧凑格式），YAML 为缩进的空格数
+	indent   string
+	enc      textfile.Encoding
+	newline  bool
+	docStart bool
+}
+
+// Parse 解析 JSON 或 YAML，以 { 或 [ 开头的视为 JSON
+func Parse(data string) (*File, error) {
+	f := &File{done: make(map[*yaml.Node]bool)}
+	data, f.enc = textfile.Decode(data)
+	f.newline = strings.HasSuffix(data, "\n")
+
+	trimmed := strings.TrimLeft(data, " \t\n")
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		node, err := decodeJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		f.Format = FormatJSON
+		f.doc = node
+		f.root = node
+		f.indent = detectIndent(data)
+		return f, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(data), &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, errors.New("empty YAML document")
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode && root.Kind != yaml.SequenceNode {
+		return nil, errors.New("YAML document must be a mapping or a sequence")
+	}
+	clearMergeTags(root)
+	f.Format = FormatYAML
+	f.doc = &doc
+	f.root = root
+	f.indent = strings.Repeat(" ", max(len(detectIndent(data)), 2))
+	f.docStart = strings.HasPrefix(trimmed, "---")
+	return f, nil
+}
+
+// clearMergeTags 清除合并键（<<）的显式标签，否则 yaml.v3 输出时会写成 !!merge <<。
+// 清除后 ShortTag 仍然解析为 !!merge
+func clearMergeTags(n *yaml.Node) {
+	for i, child := range n.Content {
+		if n.Kind == yaml.MappingNode && i%2 == 0 && child.ShortTag() == "!!merge" {
+			child.Tag = ""
+			continue
+		}
+		clearMergeTags(child)
+	}
+}
+
+// detectIndent 返回第一个缩进行的前导空白
+func detectIndent(data string) string {
+	for _, line := range strings.Split(data, "\n") {
+		if trimmed := strings.TrimLeft(line, " \t"); trimmed != "" && len(trimmed) < len(line) {
+			return line[:len(line)-len(trimmed)]
+		}
+	}
+	return ""
+}
+
+// RootKey 根节点只有一个值为映射的键时（如 Rails 的 en:）返回该键
+func (f *File) RootKey() (string, bool) {
+	if f.root.Kind != yaml.MappingNode || len(f.root.Content) != 2 || f.root.Content[1].Kind != yaml.MappingNode {
+		return "", false
+	}
+	return f.root.Content[0].Value, true
+}
+
+// SetLocaleRoot 把根键视为语言代码并改为 locale，Merge 时比较根键下的内容
+func (f *File) SetLocaleRoot(locale string) {
+	if _, ok := f.RootKey(); !ok {
+		return
+	}
+	f.localeRoot = true
+	f.root.Content[0].Value = locale
+}
+
+func (f *File) content() *yaml.Node {
+	if f.localeRoot {
+		return f.root.Content[1]
+	}
+	return f.root
+}
+
+// Merge 把 existing 中相同路径的非空字符串作为译文，这些字符串不再由 Units 返回。返回复用的数量
+func (f *File) Merge(existing *File) int {
+	known := make(map[string]string)
+	walk(existing.content(), "", func(path string, n *yaml.Node) {
+		if strings.TrimSpace(n.Value) != "" {
+			known[path] = n.Value
+		}
+	})
+
+	merged := 0
+	walk(f.content(), "", func(path string, n *yaml.Node) {
+		if value, ok := known[path]; ok && !f.done[n] {
+			n.Value = value
+			f.done[n] = true
+			merged++
+		}
+	})
+	return merged
+}
+
+// Units 返回需要翻译的字符串值，键、数字、布尔值和 null 保持不变
+func (f *File) Units() []Unit {
+	var units []Unit
+	walk(f.content(), "", func(_ string, n *yaml.Node) {
+		if strings.TrimSpace(n.Value) == "" || f.done[n] {
+			return
+		}
+		units = append(units, Unit{Text: n.Value, node: n})
+	})
+	return units
+}
+
+func (f *File) Apply(units []Unit, results []string) {
+	for i, unit := range units {
+		unit.node.Value = results[i]
+		f.done[unit.node] = true
+	}
+}
+
+// walk 按文档顺序访问所有字符串值，path 为从根开始的键和数组下标
+func walk(n *yaml.Node, path string, fn func(path string, n *yaml.Node)) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			walk(child, path, fn)
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			// YAML 的合并键引用其他节点，被引用的节点在定义处翻译
+			if key.ShortTag() == "!!merge" {
+				continue
+			}
+			walk(n.Content[i+1], path+"\x00"+key.Value, fn)
+		}
+	case yaml.SequenceNode:
+		for i, child := range n.Content {
+			walk(child, path+"\x00["+strconv.Itoa(i)+"]", fn)
+		}
+	case yaml.ScalarNode:
+		if n.ShortTag() == "!!str" {
+			fn(path, n)
+		}
+	}
+}
+
+// Marshal 按原格式输出，JSON 沿用原文的缩进，YAML 保留注释
+func (f *File) Marshal() ([]byte, error) {
+	var out string
+	if f.Format == FormatJSON {
+		var sb strings.Builder
+		encodeJSON(&sb, f.doc, f.indent, "")
+		out = sb.String()
+		if f.newline {
+			out += "\n"
+		}
+	} else {
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(len(f.indent))
+		if err := enc.Encode(f.doc); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		out = buf.String()
+		if f.docStart {
+			out = "---\n" + out
+		}
+	}
+
+	return []byte(f.enc.Encode(out)), nil
+}
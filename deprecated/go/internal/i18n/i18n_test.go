@@ -0,0 +1,189 @@
+package i18n
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func texts(units []Unit) []string {
+	var out []string
+	for _, u := range units {
+		out = append(out, u.Text)
+	}
+	return out
+}
+
+func upper(f *File) {
+	units := f.Units()
+	results := make([]string, len(units))
+	for i, u := range units {
+		results[i] = strings.ToUpper(u.Text)
+	}
+	f.Apply(units, results)
+}
+
+func marshal(t *testing.T, f *File) string {
+	t.Helper()
+	out, err := f.Marshal()
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"json": FormatJSON, ".yml": FormatYAML, "YAML": FormatYAML} {
+		got, err := ParseFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := ParseFormat("po")
+	assert.Error(t, err)
+}
+
+const sampleJSON = `{
+    "title": "Welcome <b>{{name}}</b> & \"friends\"",
+    "count": 3,
+    "enabled": true,
+    "empty": "",
+    "nested": {
+        "items": [
+            "One",
+            "Two",
+            null
+        ],
+        "price": 1.50
+    },
+    "list": []
+}
+`
+
+func TestJSON(t *testing.T) {
+	f, err := Parse(sampleJSON)
+	require.NoError(t, err)
+	assert.Equal(t, FormatJSON, f.Format)
+	assert.Equal(t, sampleJSON, marshal(t, f))
+
+	assert.Equal(t, []string{`Welcome <b>{{name}}</b> & "friends"`, "One", "Two"}, texts(f.Units()))
+	upper(f)
+	assert.Equal(t, strings.NewReplacer(
+		`Welcome <b>{{name}}</b> & \"friends\"`, `WELCOME <B>{{NAME}}</B> & \"FRIENDS\"`,
+		`"One"`, `"ONE"`,
+		`"Two"`, `"TWO"`,
+	).Replace(sampleJSON), marshal(t, f))
+}
+
+func TestJSONCompact(t *testing.T) {
+	f, err := Parse(`{"a":"x","b":[1,{"c":"y"}]}`)
+	require.NoError(t, err)
+	upper(f)
+	assert.Equal(t, `{"a":"X","b":[1,{"c":"Y"}]}`, marshal(t, f))
+}
+
+func TestJSONInvalid(t *testing.T) {
+	_, err := Parse(`{"a": }`)
+	assert.ErrorContains(t, err, "invalid JSON")
+
+	_, err = Parse(`{"a": "b"} {}`)
+	assert.ErrorContains(t, err, "unexpected data after top-level value")
+}
+
+const sampleYAML = `# Rails locale
+en:
+  greeting: Hello %{name}
+  # Pluralized
+  inbox:
+    one: One message
+    other: '%{count} messages'
+  max: 10
+  flag: yes
+  defaults: &defaults
+    ok: OK
+  dialog:
+    <<: *defaults
+    cancel: Cancel
+`
+
+func TestYAML(t *testing.T) {
+	f, err := Parse(sampleYAML)
+	require.NoError(t, err)
+	assert.Equal(t, FormatYAML, f.Format)
+	assert.Equal(t, sampleYAML, marshal(t, f))
+
+	key, ok := f.RootKey()
+	assert.True(t, ok)
+	assert.Equal(t, "en", key)
+
+	assert.Equal(t, []string{"Hello %{name}", "One message", "%{count} messages", "yes", "OK", "Cancel"}, texts(f.Units()))
+
+	f.SetLocaleRoot("de")
+	upper(f)
+	assert.Equal(t, strings.NewReplacer(
+		"en:", "de:",
+		"Hello %{name}", "HELLO %{NAME}",
+		"One message", "ONE MESSAGE",
+		"'%{count} messages'", "'%{COUNT} MESSAGES'",
+		"flag: yes", "flag: YES",
+		"Cancel", "CANCEL",
+	).Replace(sampleYAML), marshal(t, f))
+}
+
+func TestYAMLQuoting(t *testing.T) {
+	f, err := Parse("a: x\nb: y\n")
+	require.NoError(t, err)
+	units := f.Units()
+	f.Apply(units, []string{"key: value", "true"})
+
+	out := marshal(t, f)
+	assert.Equal(t, "a: 'key: value'\nb: \"true\"\n", out)
+
+	again, err := Parse(out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key: value", "true"}, texts(again.Units()))
+}
+
+func TestYAMLInvalid(t *testing.T) {
+	_, err := Parse("a: [b\n")
+	assert.ErrorContains(t, err, "invalid YAML")
+
+	_, err = Parse("just a string\n")
+	assert.EqualError(t, err, "YAML document must be a mapping or a sequence")
+
+	_, err = Parse("")
+	assert.EqualError(t, err, "empty YAML document")
+}
+
+func TestMerge(t *testing.T) {
+	f, err := Parse(`{"a": "Apple", "b": {"c": "Cat", "d": "Dog"}, "e": ["Egg"]}`)
+	require.NoError(t, err)
+	existing, err := Parse(`{"b": {"c": "Katze", "d": ""}, "e": ["Ei"], "stale": "Alt"}`)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.Merge(existing))
+	assert.Equal(t, []string{"Apple", "Dog"}, texts(f.Units()))
+
+	upper(f)
+	assert.Equal(t, `{"a":"APPLE","b":{"c":"Katze","d":"DOG"},"e":["Ei"]}`, marshal(t, f))
+}
+
+func TestMergeLocaleRoot(t *testing.T) {
+	f, err := Parse("en:\n  a: Apple\n  b: Banana\n")
+	require.NoError(t, err)
+	existing, err := Parse("de:\n  a: Apfel\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, f.Merge(existing))
+
+	f.SetLocaleRoot("de")
+	existing.SetLocaleRoot("de")
+	assert.Equal(t, 1, f.Merge(existing))
+	assert.Equal(t, []string{"Banana"}, texts(f.Units()))
+}
+
+func TestDocumentStart(t *testing.T) {
+	data := "---\na: x\n"
+	f, err := Parse(data)
+	require.NoError(t, err)
+	assert.Equal(t, data, marshal(t, f))
+}
@@ -0,0 +1,133 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeJSON 把 JSON 解析为 yaml.Node 树以保留键的顺序，数字保留原文
+func decodeJSON(data string) (*yaml.Node, error) {
+	d := json.NewDecoder(strings.NewReader(data))
+	d.UseNumber()
+
+	node, err := decodeJSONValue(d)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after top-level value")
+	}
+	return node, nil
+}
+
+func decodeJSONValue(d *json.Decoder) (*yaml.Node, error) {
+	tok, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if t == '[' {
+			node = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		}
+		for d.More() {
+			if node.Kind == yaml.MappingNode {
+				key, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key.(string)})
+			}
+			value, err := decodeJSONValue(d)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		// 结束的 } 或 ]
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: t.String()}, nil
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(t)}, nil
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+// encodeJSON 输出 yaml.Node 树，indent 为空时输出紧凑格式
+func encodeJSON(sb *strings.Builder, n *yaml.Node, indent, prefix string) {
+	newline := func(prefix string) {
+		if indent != "" {
+			sb.WriteString("\n")
+			sb.WriteString(prefix)
+		}
+	}
+
+	switch n.Kind {
+	case yaml.MappingNode:
+		if len(n.Content) == 0 {
+			sb.WriteString("{}")
+			return
+		}
+		sb.WriteString("{")
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			newline(prefix + indent)
+			writeJSONString(sb, n.Content[i].Value)
+			sb.WriteString(":")
+			if indent != "" {
+				sb.WriteString(" ")
+			}
+			encodeJSON(sb, n.Content[i+1], indent, prefix+indent)
+		}
+		newline(prefix)
+		sb.WriteString("}")
+	case yaml.SequenceNode:
+		if len(n.Content) == 0 {
+			sb.WriteString("[]")
+			return
+		}
+		sb.WriteString("[")
+		for i, child := range n.Content {
+			if i > 0 {
+				sb.WriteString(",")
+			}
+			newline(prefix + indent)
+			encodeJSON(sb, child, indent, prefix+indent)
+		}
+		newline(prefix)
+		sb.WriteString("]")
+	default:
+		if n.Tag == "!!str" {
+			writeJSONString(sb, n.Value)
+		} else {
+			sb.WriteString(n.Value)
+		}
+	}
+}
+
+// writeJSONString 输出 JSON 字符串，不转义 HTML 字符
+func writeJSONString(sb *strings.Builder, s string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	sb.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
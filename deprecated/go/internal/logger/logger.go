@@ -2,6 +2,7 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strings"
@@ -39,6 +40,13 @@ func init() {
 	errorLogger = log.New(os.Stderr, colorRed+"[ERROR]"+colorReset+" ", log.Ldate|log.Ltime|log.Lshortfile)
 }
 
+// SetOutput 将 debug/info/warn 日志重定向到 w，error 日志始终输出到标准错误
+func SetOutput(w io.Writer) {
+	debugLogger.SetOutput(w)
+	infoLogger.SetOutput(w)
+	warnLogger.SetOutput(w)
+}
+
 func SetLevel(level string) {
 	switch strings.ToLower(level) {
 	case "debug":
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -12,11 +14,13 @@ import (
 )
 
 type WSMessage struct {
+	ID   string          `json:"id,omitempty"`
 	Type string          `json:"type"`
 	Data json.RawMessage `json:"data"`
 }
 
 type WSResponse struct {
+	ID   string          `json:"id,omitempty"`
 	Type string          `json:"type"`
 	Code int             `json:"code"`
 	Msg  string          `json:"msg"`
@@ -33,27 +37,67 @@ type ExitRequest struct {
 	Force bool `json:"force"`
 }
 
+type TransBatchRequest struct {
+	Requests []TransRequest `json:"requests"`
+}
+
 type HealthResponse struct {
-	Ready bool `json:"ready"`
+	Ready        bool     `json:"ready"`
+	Capabilities []string `json:"capabilities,omitempty"`
 }
 
 type TransResponse struct {
 	TranslatedText string `json:"translated_text"`
 }
 
+type TransBatchResponse struct {
+	Results []TransResponse `json:"results"`
+}
+
+// CapabilityBatch is advertised in the health response by workers that
+// accept "trans_batch" messages.
+const CapabilityBatch = "trans_batch"
+
 type ExitResponse struct {
 	Message string `json:"message"`
 }
 
+type requestResult struct {
+	resp *WSResponse
+	err  error
+}
+
+// pendingRequest is a request that has been written and awaits its response.
+// Abandoned requests stay queued so a late response is absorbed instead of
+// being handed to the next caller.
+type pendingRequest struct {
+	id        string
+	result    chan requestResult
+	abandoned bool
+}
+
 type Client struct {
 	url       string
 	conn      *websocket.Conn
 	mu        sync.RWMutex
+	writeMu   sync.Mutex
 	timeout   time.Duration
 	connected bool
 	reconnect bool
 	closeChan chan struct{}
 	closeOnce sync.Once
+
+	nextID  atomic.Uint64
+	pending map[string]*pendingRequest
+	// order tracks pending requests in send order so responses from workers
+	// that do not echo IDs can be matched first-in first-out.
+	order []*pendingRequest
+	// multiplexed becomes true once the worker echoes a request ID; until
+	// then requests are sent one at a time.
+	multiplexed atomic.Bool
+	serial      chan struct{}
+
+	capabilities map[string]bool
 }
 
 type ClientOption func(*Client)
@@ -76,6 +120,8 @@ func NewClient(url string, opts ...ClientOption) *Client {
 		timeout:   30 * time.Second,
 		reconnect: false,
 		closeChan: make(chan struct{}),
+		pending:   make(map[string]*pendingRequest),
+		serial:    make(chan struct{}, 1),
 	}
 
 	for _, opt := range opts {
@@ -104,6 +150,10 @@ func (c *Client) Connect() error {
 
 	c.conn = conn
 	c.connected = true
+	c.pending = make(map[string]*pendingRequest)
+	c.order = nil
+
+	go c.readLoop(conn)
 
 	return nil
 }
@@ -129,53 +179,173 @@ func (c *Client) IsConnected() bool {
 	return c.connected
 }
 
+// Multiplexed reports whether the worker answers with request IDs.
+func (c *Client) Multiplexed() bool {
+	return c.multiplexed.Load()
+}
+
+// InFlight returns the number of requests awaiting a response.
+func (c *Client) InFlight() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.order)
+}
+
+func (c *Client) readLoop(conn *websocket.Conn) {
+	for {
+		var resp WSResponse
+		if err := conn.ReadJSON(&resp); err != nil {
+			c.fail(conn, fmt.Errorf("failed to read response: %w", err))
+			return
+		}
+		c.dispatch(&resp)
+	}
+}
+
+func (c *Client) dispatch(resp *WSResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var p *pendingRequest
+	if resp.ID != "" {
+		c.multiplexed.Store(true)
+		p = c.pending[resp.ID]
+		if p == nil {
+			logger.Debug("Client: dropping response for unknown request id %s", resp.ID)
+			return
+		}
+		c.removeOrder(p)
+	} else {
+		if len(c.order) == 0 {
+			logger.Debug("Client: dropping unexpected %s response", resp.Type)
+			return
+		}
+		p = c.order[0]
+		c.order = c.order[1:]
+	}
+	delete(c.pending, p.id)
+
+	if p.abandoned {
+		logger.Debug("Client: discarding late response for request %s", p.id)
+		return
+	}
+	p.result <- requestResult{resp: resp}
+}
+
+// fail closes conn and completes every pending request with err.
+func (c *Client) fail(conn *websocket.Conn, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != conn {
+		return
+	}
+
+	c.connected = false
+	conn.Close()
+
+	for _, p := range c.order {
+		if !p.abandoned {
+			p.result <- requestResult{err: err}
+		}
+	}
+	c.pending = make(map[string]*pendingRequest)
+	c.order = nil
+}
+
+// abandon gives up on p without affecting other requests. Workers that echo
+// IDs route a late response by ID, so p is forgotten and the response is
+// dropped as unknown; otherwise p stays queued to absorb it.
+func (c *Client) abandon(p *pendingRequest) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.multiplexed.Load() {
+		p.abandoned = true
+		return
+	}
+	if c.pending[p.id] == p {
+		delete(c.pending, p.id)
+		c.removeOrder(p)
+	}
+}
+
+func (c *Client) removeOrder(p *pendingRequest) {
+	for i, o := range c.order {
+		if o == p {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			return
+		}
+	}
+}
+
 func (c *Client) sendRequest(ctx context.Context, msgType string, data interface{}) (*WSResponse, error) {
 	dataBytes, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal data: %w", err)
 	}
 
-	msg := WSMessage{
-		Type: msgType,
-		Data: dataBytes,
+	if !c.multiplexed.Load() {
+		select {
+		case c.serial <- struct{}{}:
+			defer func() { <-c.serial }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
 
 	reqCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	if !c.connected {
+		c.mu.Unlock()
 		return nil, fmt.Errorf("not connected")
 	}
 
-	if err := c.conn.WriteJSON(msg); err != nil {
-		c.connected = false
-		return nil, fmt.Errorf("failed to send message: %w", err)
+	conn := c.conn
+	p := &pendingRequest{
+		id:     strconv.FormatUint(c.nextID.Add(1), 10),
+		result: make(chan requestResult, 1),
 	}
+	c.pending[p.id] = p
+	c.order = append(c.order, p)
+	c.mu.Unlock()
 
-	responseChan := make(chan *WSResponse, 1)
-	errChan := make(chan error, 1)
+	msg := WSMessage{
+		ID:   p.id,
+		Type: msgType,
+		Data: dataBytes,
+	}
 
-	go func() {
-		var resp WSResponse
-		if err := c.conn.ReadJSON(&resp); err != nil {
-			errChan <- fmt.Errorf("failed to read response: %w", err)
-			return
-		}
-		responseChan <- &resp
-	}()
+	c.writeMu.Lock()
+	err = conn.WriteJSON(msg)
+	c.writeMu.Unlock()
+	if err != nil {
+		err = fmt.Errorf("failed to send message: %w", err)
+		c.fail(conn, err)
+		return nil, err
+	}
 
 	select {
+	case res := <-p.result:
+		return res.resp, res.err
 	case <-reqCtx.Done():
-		c.connected = false
+		if ctx.Err() != nil {
+			// Caller gave up; the connection stays usable for other requests.
+			c.abandon(p)
+			return nil, fmt.Errorf("request canceled: %w", ctx.Err())
+		}
+		if c.multiplexed.Load() {
+			// Only this request is given up; the health check decides
+			// whether the worker is hung.
+			c.abandon(p)
+			return nil, fmt.Errorf("request timeout")
+		}
+		// A serial worker may still answer this request, and that late
+		// response would be handed to the next caller.
+		c.fail(conn, fmt.Errorf("request timeout"))
 		return nil, fmt.Errorf("request timeout")
-	case err := <-errChan:
-		c.connected = false
-		return nil, err
-	case resp := <-responseChan:
-		return resp, nil
 	}
 }
 
@@ -196,9 +366,25 @@ func (c *Client) Health(ctx context.Context) (bool, error) {
 		}
 	}
 
+	capabilities := make(map[string]bool, len(result.Capabilities))
+	for _, capability := range result.Capabilities {
+		capabilities[capability] = true
+	}
+	c.mu.Lock()
+	c.capabilities = capabilities
+	c.mu.Unlock()
+
 	return result.Ready, nil
 }
 
+// Supports reports whether the worker advertised capability in its last
+// health response.
+func (c *Client) Supports(capability string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.capabilities[capability]
+}
+
 func (c *Client) Trans(ctx context.Context, req TransRequest) (string, error) {
 	logger.Debug("Client.Trans: sending request, text length: %d, isHTML: %v, text: %q", len(req.Text), req.HTML, req.Text)
 	resp, err := c.sendRequest(ctx, "trans", req)
@@ -224,6 +410,39 @@ func (c *Client) Trans(ctx context.Context, req TransRequest) (string, error) {
 	return result.TranslatedText, nil
 }
 
+func (c *Client) TransBatch(ctx context.Context, reqs []TransRequest) ([]string, error) {
+	logger.Debug("Client.TransBatch: sending %d requests", len(reqs))
+	resp, err := c.sendRequest(ctx, "trans_batch", TransBatchRequest{Requests: reqs})
+	if err != nil {
+		logger.Debug("Client.TransBatch: sendRequest error: %v", err)
+		return nil, err
+	}
+
+	if resp.Code != 200 {
+		logger.Debug("Client.TransBatch: response code %d: %s", resp.Code, resp.Msg)
+		return nil, fmt.Errorf("trans_batch failed (code %d): %s", resp.Code, resp.Msg)
+	}
+
+	var result TransBatchResponse
+	if resp.Data != nil {
+		if err := json.Unmarshal(resp.Data, &result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
+
+	if len(result.Results) != len(reqs) {
+		return nil, fmt.Errorf("trans_batch returned %d results for %d requests", len(result.Results), len(reqs))
+	}
+
+	texts := make([]string, len(result.Results))
+	for i, r := range result.Results {
+		texts[i] = r.TranslatedText
+	}
+
+	logger.Debug("Client.TransBatch: success, %d results", len(texts))
+	return texts, nil
+}
+
 func (c *Client) Exit(ctx context.Context, req ExitRequest) (*ExitResponse, error) {
 	resp, err := c.sendRequest(ctx, "exit", req)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -357,3 +358,253 @@ func TestClient_MultipleRequests(t *testing.T) {
 		assert.Contains(t, result, "translated:")
 	}
 }
+
+func TestClient_MultiplexedOutOfOrder(t *testing.T) {
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		var msgs []manager.WSMessage
+		for len(msgs) < 3 {
+			var msg manager.WSMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type == "health" {
+				data, _ := json.Marshal(map[string]bool{"ready": true})
+				conn.WriteJSON(manager.WSResponse{ID: msg.ID, Type: "health", Code: 200, Data: data})
+				continue
+			}
+			msgs = append(msgs, msg)
+		}
+
+		// Reply in reverse order to make sure responses are matched by ID
+		for i := len(msgs) - 1; i >= 0; i-- {
+			var req manager.TransRequest
+			json.Unmarshal(msgs[i].Data, &req)
+			data, _ := json.Marshal(map[string]string{"translated_text": "translated: " + req.Text})
+			conn.WriteJSON(manager.WSResponse{ID: msgs[i].ID, Type: "trans", Code: 200, Data: data})
+		}
+
+		var msg manager.WSMessage
+		conn.ReadJSON(&msg)
+	})
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	client := manager.NewClient(wsURL)
+	defer client.Close()
+
+	require.NoError(t, client.Connect())
+
+	ready, err := client.Health(context.Background())
+	require.NoError(t, err)
+	require.True(t, ready)
+	assert.True(t, client.Multiplexed())
+
+	texts := []string{"one", "two", "three"}
+	results := make([]string, len(texts))
+	errs := make([]error, len(texts))
+	done := make(chan int, len(texts))
+
+	for i, text := range texts {
+		go func(i int, text string) {
+			results[i], errs[i] = client.Trans(context.Background(), manager.TransRequest{Text: text})
+			done <- i
+		}(i, text)
+	}
+	for range texts {
+		<-done
+	}
+
+	for i, text := range texts {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "translated: "+text, results[i])
+	}
+	assert.Equal(t, 0, client.InFlight())
+}
+
+func TestClient_CanceledRequestKeepsConnection(t *testing.T) {
+	release := make(chan struct{})
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		for i := 0; i < 2; i++ {
+			var msg manager.WSMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if i == 0 {
+				<-release
+			}
+
+			var req manager.TransRequest
+			json.Unmarshal(msg.Data, &req)
+			data, _ := json.Marshal(map[string]string{"translated_text": "translated: " + req.Text})
+
+			// Legacy worker: no request ID in the response
+			if err := conn.WriteJSON(manager.WSResponse{Type: "trans", Code: 200, Data: data}); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	client := manager.NewClient(wsURL)
+	defer client.Close()
+
+	require.NoError(t, client.Connect())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	_, err := client.Trans(ctx, manager.TransRequest{Text: "slow"})
+	cancel()
+	assert.Error(t, err)
+	assert.True(t, client.IsConnected())
+
+	close(release)
+
+	result, err := client.Trans(context.Background(), manager.TransRequest{Text: "next"})
+	assert.NoError(t, err)
+	assert.Equal(t, "translated: next", result)
+}
+
+func TestClient_MultiplexedTimeoutKeepsOtherRequests(t *testing.T) {
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		var writeMu sync.Mutex
+		for {
+			var msg manager.WSMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			if msg.Type == "health" {
+				data, _ := json.Marshal(map[string]bool{"ready": true})
+				writeMu.Lock()
+				conn.WriteJSON(manager.WSResponse{ID: msg.ID, Type: "health", Code: 200, Data: data})
+				writeMu.Unlock()
+				continue
+			}
+
+			var req manager.TransRequest
+			json.Unmarshal(msg.Data, &req)
+			if req.Text == "hang" {
+				continue
+			}
+			go func(msg manager.WSMessage) {
+				time.Sleep(50 * time.Millisecond)
+				data, _ := json.Marshal(map[string]string{"translated_text": "translated: " + req.Text})
+				writeMu.Lock()
+				conn.WriteJSON(manager.WSResponse{ID: msg.ID, Type: "trans", Code: 200, Data: data})
+				writeMu.Unlock()
+			}(msg)
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	client := manager.NewClient(wsURL, manager.WithTimeout(200*time.Millisecond))
+	defer client.Close()
+
+	require.NoError(t, client.Connect())
+	_, err := client.Health(context.Background())
+	require.NoError(t, err)
+	require.True(t, client.Multiplexed())
+
+	hung := make(chan error, 1)
+	go func() {
+		_, err := client.Trans(context.Background(), manager.TransRequest{Text: "hang"})
+		hung <- err
+	}()
+
+	// Sent before the hung request times out and answered after it
+	time.Sleep(180 * time.Millisecond)
+	result, err := client.Trans(context.Background(), manager.TransRequest{Text: "next"})
+	assert.NoError(t, err)
+	assert.Equal(t, "translated: next", result)
+
+	err = <-hung
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout")
+	assert.True(t, client.IsConnected())
+	assert.Equal(t, 0, client.InFlight())
+}
+
+func TestClient_TransBatch(t *testing.T) {
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		for {
+			var msg manager.WSMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+
+			resp := manager.WSResponse{ID: msg.ID, Type: msg.Type, Code: 200, Msg: "success"}
+			switch msg.Type {
+			case "health":
+				resp.Data, _ = json.Marshal(manager.HealthResponse{
+					Ready:        true,
+					Capabilities: []string{manager.CapabilityBatch},
+				})
+			case "trans_batch":
+				var req manager.TransBatchRequest
+				json.Unmarshal(msg.Data, &req)
+
+				var batch manager.TransBatchResponse
+				for _, r := range req.Requests {
+					batch.Results = append(batch.Results, manager.TransResponse{TranslatedText: "translated: " + r.Text})
+				}
+				resp.Data, _ = json.Marshal(batch)
+			}
+
+			if err := conn.WriteJSON(resp); err != nil {
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	client := manager.NewClient(wsURL)
+	defer client.Close()
+
+	require.NoError(t, client.Connect())
+	assert.False(t, client.Supports(manager.CapabilityBatch))
+
+	_, err := client.Health(context.Background())
+	require.NoError(t, err)
+	assert.True(t, client.Supports(manager.CapabilityBatch))
+
+	results, err := client.TransBatch(context.Background(), []manager.TransRequest{
+		{Text: "Hello"},
+		{Text: "World"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"translated: Hello", "translated: World"}, results)
+}
+
+func TestClient_TransBatch_ResultCountMismatch(t *testing.T) {
+	server := mockWSServer(t, func(conn *websocket.Conn) {
+		var msg manager.WSMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		data, _ := json.Marshal(manager.TransBatchResponse{
+			Results: []manager.TransResponse{{TranslatedText: "only one"}},
+		})
+		conn.WriteJSON(manager.WSResponse{ID: msg.ID, Type: "trans_batch", Code: 200, Data: data})
+	})
+	defer server.Close()
+
+	wsURL := "ws" + server.URL[4:]
+
+	client := manager.NewClient(wsURL)
+	defer client.Close()
+
+	require.NoError(t, client.Connect())
+
+	_, err := client.TransBatch(context.Background(), []manager.TransRequest{
+		{Text: "Hello"},
+		{Text: "World"},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "2 requests")
+}
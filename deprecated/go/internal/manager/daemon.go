@@ -313,6 +313,17 @@ func (w *Worker) Status() string {
 	return "running"
 }
 
+func (w *Worker) PID() int {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if !w.running {
+		return 0
+	}
+
+	return w.pid
+}
+
 func (w *Worker) Logs() []string {
 	w.logMu.RLock()
 	defer w.logMu.RUnlock()
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/metrics"
 )
 
 const (
@@ -22,28 +23,43 @@ type Manager struct {
 	client    *Client
 	mu        sync.RWMutex
 	url       string
-	taskQueue chan struct{} // Token bucket for serializing tasks
+	taskQueue chan struct{} // Token bucket limiting in-flight tasks per worker
 	closed    bool
 	state     int
+
+	maxInFlight int
 }
 
 type ManagerOption func(*Manager)
 
+// WithMaxInFlight sets how many requests may be outstanding on the worker
+// connection at once. Workers that do not echo request IDs are still served
+// one request at a time.
+func WithMaxInFlight(n int) ManagerOption {
+	return func(m *Manager) {
+		if n > 0 {
+			m.maxInFlight = n
+		}
+	}
+}
+
 func NewManager(args *WorkerArgs, opts ...ManagerOption) *Manager {
 
 	url := fmt.Sprintf("ws://%s:%d/ws", args.Host, args.Port)
 
 	m := &Manager{
-		worker:    NewWorker(args),
-		url:       url,
-		taskQueue: make(chan struct{}, 1),
-		state:     StateStopped,
+		worker:      NewWorker(args),
+		url:         url,
+		state:       StateStopped,
+		maxInFlight: 1,
 	}
 
 	for _, opt := range opts {
 		opt(m)
 	}
 
+	m.taskQueue = make(chan struct{}, m.maxInFlight)
+
 	return m
 }
 
@@ -205,6 +221,25 @@ func (m *Manager) Status() string {
 	}
 }
 
+// Port returns the port the worker listens on.
+func (m *Manager) Port() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.worker.args.Port
+}
+
+// PID returns the worker process ID, or 0 if the worker is not running.
+func (m *Manager) PID() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.worker == nil {
+		return 0
+	}
+
+	return m.worker.PID()
+}
+
 func (m *Manager) Logs() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -228,12 +263,57 @@ func (m *Manager) Health(ctx context.Context) (bool, error) {
 }
 
 func (m *Manager) Trans(ctx context.Context, req TransRequest) (string, error) {
+	logger.Debug("Manager.Trans: text length: %d, isHTML: %v", len(req.Text), req.HTML)
+
+	var result string
+	err := m.call(ctx, func(client *Client) error {
+		var err error
+		logger.Debug("Manager.Trans: calling client.Trans")
+		result, err = client.Trans(ctx, req)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	logger.Debug("Manager.Trans: success, result length: %d", len(result))
+	return result, nil
+}
+
+// SupportsBatch reports whether the running worker accepts "trans_batch".
+func (m *Manager) SupportsBatch() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.client != nil && m.client.Supports(CapabilityBatch)
+}
+
+// TransBatch translates all requests in a single round-trip. Callers should
+// check SupportsBatch first; results are in the same order as reqs.
+func (m *Manager) TransBatch(ctx context.Context, reqs []TransRequest) ([]string, error) {
+	logger.Debug("Manager.TransBatch: %d requests", len(reqs))
+
+	var results []string
+	err := m.call(ctx, func(client *Client) error {
+		var err error
+		results, err = client.TransBatch(ctx, reqs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// call runs fn against the worker client once a task slot is available and
+// turns connection failures into an async worker restart.
+func (m *Manager) call(ctx context.Context, fn func(client *Client) error) error {
 	// 1. Check state immediately
 	m.mu.RLock()
 	if m.state != StateRunning {
 		state := m.state
 		m.mu.RUnlock()
-		return "", fmt.Errorf("manager not running (state: %d)", state)
+		return fmt.Errorf("manager not running (state: %d)", state)
 	}
 	m.mu.RUnlock()
 
@@ -242,39 +322,35 @@ func (m *Manager) Trans(ctx context.Context, req TransRequest) (string, error) {
 	case m.taskQueue <- struct{}{}:
 		defer func() { <-m.taskQueue }()
 	case <-ctx.Done():
-		return "", ctx.Err()
+		return ctx.Err()
 	}
 
-	logger.Debug("Manager.Trans: text length: %d, isHTML: %v", len(req.Text), req.HTML)
 	m.mu.RLock()
 	if m.closed {
 		m.mu.RUnlock()
-		return "", fmt.Errorf("manager is closed")
+		return fmt.Errorf("manager is closed")
 	}
 
 	// Double check state after acquiring lock
 	if m.state != StateRunning {
 		m.mu.RUnlock()
-		return "", fmt.Errorf("manager not running (state: %d)", m.state)
+		return fmt.Errorf("manager not running (state: %d)", m.state)
 	}
 
 	client := m.client
-	// worker := m.worker
 	m.mu.RUnlock()
 
 	if client == nil {
-		logger.Error("Manager.Trans: client not initialized")
+		logger.Error("Manager.call: client not initialized")
 		m.TriggerRestartAsync()
-		return "", fmt.Errorf("client not initialized")
+		return fmt.Errorf("client not initialized")
 	}
 
-	logger.Debug("Manager.Trans: calling client.Trans")
-	result, err := client.Trans(ctx, req)
+	err := fn(client)
 	if err == nil {
-		logger.Debug("Manager.Trans: success, result length: %d", len(result))
-		return result, nil
+		return nil
 	}
-	logger.Debug("Manager.Trans: client.Trans error: %v", err)
+	logger.Debug("Manager.call: client error: %v", err)
 
 	errMsg := err.Error()
 	isConnectionError := !client.IsConnected() ||
@@ -289,12 +365,12 @@ func (m *Manager) Trans(ctx context.Context, req TransRequest) (string, error) {
 		strings.Contains(errMsg, "code 503")
 
 	if !isConnectionError {
-		return "", err
+		return err
 	}
 
 	// Trigger async restart and fail this request
 	m.TriggerRestartAsync()
-	return "", fmt.Errorf("worker connection failed, restarting: %w", err)
+	return fmt.Errorf("worker connection failed, restarting: %w", err)
 }
 
 func (m *Manager) TriggerRestartAsync() {
@@ -309,22 +385,30 @@ func (m *Manager) TriggerRestartAsync() {
 	go func() {
 		logger.Info("Async restart triggered for worker on port %d", m.worker.args.Port)
 		if err := m.RestartWorker(); err != nil {
+			metrics.WorkerRestartsTotal.WithLabelValues("failure").Inc()
 			logger.Error("Async restart failed: %v", err)
-			// Ensure we mark as stopped so it can be picked up or retried later if needed?
-			// or maybe we should try again? For now, leave it as stopped/failed.
-			m.mu.Lock()
-			m.state = StateStopped
-			m.mu.Unlock()
 		} else {
+			metrics.WorkerRestartsTotal.WithLabelValues("success").Inc()
 			logger.Info("Async restart completed successfully")
 		}
 	}()
 }
 
-// RestartWorker performs the kill-and-restart logic on the SAME port
-func (m *Manager) RestartWorker() error {
+// RestartWorker performs the kill-and-restart logic on the SAME port.
+// The manager rejects new requests while restarting and is left stopped if
+// the new worker does not become ready.
+func (m *Manager) RestartWorker() (err error) {
+	defer func() {
+		if err != nil {
+			m.mu.Lock()
+			m.state = StateStopped
+			m.mu.Unlock()
+		}
+	}()
+
 	// 1. Kill old worker and cleanup resources
 	m.mu.Lock()
+	m.state = StateRestarting
 	oldWorker := m.worker
 	oldClient := m.client
 
@@ -0,0 +1,453 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	autolinkPattern   = regexp.MustCompile(`^<(?:[A-Za-z][A-Za-z0-9+.\-]{1,31}:[^<>\s]*|[^<>\s@]+@[^<>\s]+)>`)
+	inlineHTMLPattern = regexp.MustCompile(`^(?:<!--[\s\S]*?-->|</?[A-Za-z][A-Za-z0-9\-]*(?:\s[^<>]*)?/?>)`)
+	entityPattern     = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	urlPattern        = regexp.MustCompile(`^(?:https?://|www\.)[^\s<>]+`)
+	hardBreakPattern  = regexp.MustCompile(`^ {2,}\n`)
+	// translatedTag 译文中的元素标签，id 对应 inline.elements 的下标
+	translatedTag = regexp.MustCompile(`<(/?)([A-Za-z]+)(?:\s+id="?(\d+)"?)?\s*/?>`)
+	anyTag        = regexp.MustCompile(`<[^<>]*>`)
+)
+
+// element 行内语法。close 为空的是原子元素（代码、图片、链接地址、HTML 标签等），原样还原
+type element struct {
+	tag   string
+	open  string
+	close string
+	// image 图片的 alt 文本，单独翻译
+	image *inline
+}
+
+func (e *element) atom() bool {
+	return e.tag == "x"
+}
+
+func (e *element) render() string {
+	if e.image != nil {
+		return e.open + e.image.render() + e.close
+	}
+	return e.open
+}
+
+// inline 一段行内 Markdown。强调和链接文字转换为 <b>、<i>、<s>、<a> 标签，
+// 其余语法替换为空的 <x> 元素，按 HTML 翻译后再转换回 Markdown
+type inline struct {
+	source string
+	// raw 原文，多行段落包含后续行的前缀
+	raw      string
+	text     string
+	html     bool
+	elements []*element
+
+	result     string
+	translated bool
+}
+
+func parseInline(source string) *inline {
+	in := &inline{source: source}
+	var sb strings.Builder
+	in.parse(source, &sb)
+	in.text = strings.TrimSpace(sb.String())
+	if len(in.elements) > 0 {
+		in.html = true
+	} else {
+		in.text = html.UnescapeString(in.text)
+	}
+	return in
+}
+
+// render 图片的 alt 文本可能在所在段落之后才写入译文，所以在输出时才还原
+func (in *inline) render() string {
+	if in.translated {
+		return in.restore(in.result)
+	}
+	if in.raw != "" {
+		return in.raw
+	}
+	return in.source
+}
+
+// translatable 去掉标签后仍然包含文字
+func (in *inline) translatable() bool {
+	text := in.text
+	if in.html {
+		text = anyTag.ReplaceAllString(text, "")
+	}
+	return strings.IndexFunc(text, unicode.IsLetter) >= 0
+}
+
+func (in *inline) add(e *element) int {
+	in.elements = append(in.elements, e)
+	return len(in.elements) - 1
+}
+
+func (in *inline) atom(sb *strings.Builder, raw string) {
+	id := in.add(&element{tag: "x", open: raw})
+	sb.WriteString(`<x id="` + strconv.Itoa(id) + `"></x>`)
+}
+
+func (in *inline) pair(sb *strings.Builder, tag, open, close, inner string) {
+	id := in.add(&element{tag: tag, open: open, close: close})
+	sb.WriteString("<" + tag + ` id="` + strconv.Itoa(id) + `">`)
+	in.parse(inner, sb)
+	sb.WriteString("</" + tag + ">")
+}
+
+func (in *inline) parse(s string, sb *strings.Builder) {
+	text := func(t string) {
+		sb.WriteString(html.EscapeString(t))
+	}
+
+	for i := 0; i < len(s); {
+		c := s[i]
+		rest := s[i:]
+
+		switch {
+		case c == '\\' && i+1 < len(s) && (s[i+1] == '\n' || isPunct(s[i+1])):
+			in.atom(sb, s[i:i+2])
+			i += 2
+			continue
+
+		case c == '`':
+			n := runLength(s, i, '`')
+			if end := closingTicks(s, i+n, n); end > 0 {
+				in.atom(sb, s[i:end])
+				i = end
+				continue
+			}
+			text(s[i : i+n])
+			i += n
+			continue
+
+		case c == '!' && strings.HasPrefix(rest, "!["):
+			if closeBracket, end := linkEnd(s, i+1); end > 0 {
+				image := parseInline(s[i+2 : closeBracket])
+				id := in.add(&element{tag: "x", open: "![", close: s[closeBracket:end], image: image})
+				sb.WriteString(`<x id="` + strconv.Itoa(id) + `"></x>`)
+				i = end
+				continue
+			}
+
+		case c == '[':
+			if strings.HasPrefix(rest, "[^") {
+				if end := strings.IndexByte(rest, ']'); end > 0 {
+					in.atom(sb, rest[:end+1])
+					i += end + 1
+					continue
+				}
+			}
+			if closeBracket, end := linkEnd(s, i); end > 0 {
+				in.pair(sb, "a", "[", s[closeBracket:end], s[i+1:closeBracket])
+				i = end
+				continue
+			}
+
+		case c == '<':
+			if m := autolinkPattern.FindString(rest); m != "" {
+				in.atom(sb, m)
+				i += len(m)
+				continue
+			}
+			if m := inlineHTMLPattern.FindString(rest); m != "" {
+				in.atom(sb, m)
+				i += len(m)
+				continue
+			}
+
+		case c == '&':
+			if m := entityPattern.FindString(rest); m != "" {
+				in.atom(sb, m)
+				i += len(m)
+				continue
+			}
+
+		case (c == 'h' || c == 'w') && (i == 0 || !isWordByte(s[i-1])):
+			if m := urlPattern.FindString(rest); m != "" {
+				m = strings.TrimRight(m, ".,:;!?'\")")
+				in.atom(sb, m)
+				i += len(m)
+				continue
+			}
+
+		case c == ' ' && hardBreakPattern.MatchString(rest):
+			m := hardBreakPattern.FindString(rest)
+			in.atom(sb, m)
+			i += len(m)
+			continue
+
+		case c == '\n':
+			sb.WriteString(" ")
+			i++
+			continue
+
+		case c == '*' || c == '_' || c == '~':
+			n := runLength(s, i, c)
+			if end := closingDelimiter(s, i, n); end > 0 {
+				run := s[i : i+n]
+				tag := "b"
+				switch {
+				case c == '~':
+					tag = "s"
+				case n == 1:
+					tag = "i"
+				}
+				in.pair(sb, tag, run, run, s[i+n:end])
+				i = end + n
+				continue
+			}
+			text(s[i : i+n])
+			i += n
+			continue
+		}
+
+		text(s[i : i+1])
+		i++
+	}
+}
+
+// restore 把译文中的标签转换回 Markdown 语法
+func (in *inline) restore(translated string) string {
+	if !in.html {
+		return translated
+	}
+
+	var sb strings.Builder
+	used := make([]bool, len(in.elements))
+	var stack []int
+	// 开始标记写在后面的文字之前、结束标记写在前面的文字之后，使标记紧贴文字（** text** 不是合法的强调）
+	pending := ""
+	trailing := ""
+
+	space := func() {
+		if !strings.HasSuffix(sb.String(), " ") {
+			sb.WriteString(trailing)
+		}
+		trailing = ""
+	}
+	flush := func() {
+		space()
+		sb.WriteString(pending)
+		pending = ""
+	}
+	writeText := func(t string) {
+		t = html.UnescapeString(t)
+		trimmed := strings.TrimLeft(t, " ")
+		if trimmed == "" {
+			if trailing == "" {
+				trailing = t
+			}
+			return
+		}
+		lead := t[:len(t)-len(trimmed)]
+		if lead != "" {
+			trailing = lead
+		}
+		flush()
+		sb.WriteString(trimmed)
+	}
+	closeElement := func(e *element) {
+		sb.WriteString(pending)
+		pending = ""
+		s := sb.String()
+		trimmed := strings.TrimRight(s, " ")
+		sb.Reset()
+		sb.WriteString(trimmed)
+		sb.WriteString(e.close)
+		if trailing == "" {
+			trailing = s[len(trimmed):]
+		}
+	}
+
+	last := 0
+	for _, m := range translatedTag.FindAllStringSubmatchIndex(translated, -1) {
+		writeText(translated[last:m[0]])
+		last = m[1]
+
+		tag := translated[m[4]:m[5]]
+		if m[3] > m[2] {
+			if tag == "x" || len(stack) == 0 {
+				continue
+			}
+			if id := stack[len(stack)-1]; id >= 0 {
+				closeElement(in.elements[id])
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		id := -1
+		if m[6] >= 0 {
+			id, _ = strconv.Atoi(translated[m[6]:m[7]])
+		}
+		if id < 0 || id >= len(in.elements) || used[id] || in.elements[id].tag != tag {
+			// 无法识别的开始标签，对应的结束标签也忽略
+			if tag != "x" {
+				stack = append(stack, -1)
+			}
+			continue
+		}
+		used[id] = true
+		e := in.elements[id]
+		if e.atom() {
+			flush()
+			sb.WriteString(e.render())
+			continue
+		}
+		if pending == "" {
+			space()
+		}
+		pending += e.open
+		stack = append(stack, id)
+	}
+	writeText(translated[last:])
+	for len(stack) > 0 {
+		if id := stack[len(stack)-1]; id >= 0 {
+			closeElement(in.elements[id])
+		}
+		stack = stack[:len(stack)-1]
+	}
+	flush()
+
+	result := sb.String()
+	for i, e := range in.elements {
+		if !used[i] && e.atom() {
+			if result != "" && !strings.HasSuffix(result, " ") {
+				result += " "
+			}
+			result += e.render()
+		}
+	}
+	return result
+}
+
+func runLength(s string, i int, c byte) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == c {
+		n++
+	}
+	return n
+}
+
+// closingTicks 返回与长度为 n 的反引号串配对的结束位置
+func closingTicks(s string, from, n int) int {
+	for i := from; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		m := runLength(s, i, '`')
+		if m == n {
+			return i + n
+		}
+		i += m
+	}
+	return -1
+}
+
+// closingDelimiter 查找与 s[i:i+n] 配对的强调结束标记，返回结束标记的位置
+func closingDelimiter(s string, i, n int) int {
+	c := s[i]
+	if n > 3 || (c == '~' && n != 2) {
+		return -1
+	}
+	after := i + n
+	if after >= len(s) || isSpace(s[after]) {
+		return -1
+	}
+	if c == '_' && i > 0 && isWordByte(s[i-1]) {
+		return -1
+	}
+
+	for j := after + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+			continue
+		case '`':
+			m := runLength(s, j, '`')
+			if end := closingTicks(s, j+m, m); end > 0 {
+				j = end - 1
+			} else {
+				j += m - 1
+			}
+			continue
+		case c:
+		default:
+			continue
+		}
+
+		m := runLength(s, j, c)
+		if m == n && !isSpace(s[j-1]) && (c != '_' || j+m >= len(s) || !isWordByte(s[j+m])) {
+			return j
+		}
+		j += m - 1
+	}
+	return -1
+}
+
+// linkEnd 解析 s[i] 处的 [text](url) 或 [text][ref]，返回 ] 的位置和链接的结束位置
+func linkEnd(s string, i int) (int, int) {
+	closeBracket := matching(s, i, '[', ']')
+	if closeBracket < 0 || closeBracket+1 >= len(s) {
+		return -1, -1
+	}
+	switch s[closeBracket+1] {
+	case '(':
+		if end := matching(s, closeBracket+1, '(', ')'); end > 0 {
+			return closeBracket, end + 1
+		}
+	case '[':
+		if end := strings.IndexByte(s[closeBracket+1:], ']'); end > 0 {
+			return closeBracket, closeBracket + 1 + end + 1
+		}
+	}
+	return -1, -1
+}
+
+// matching 返回与 s[i] 处的 open 配对的 close 位置，跳过转义字符和代码
+func matching(s string, i int, open, close byte) int {
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case '`':
+			m := runLength(s, j, '`')
+			if end := closingTicks(s, j+m, m); end > 0 {
+				j = end - 1
+			} else {
+				j += m - 1
+			}
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
+}
+
+func isPunct(c byte) bool {
+	return c < 0x80 && unicode.IsPunct(rune(c)) || strings.IndexByte("$+<=>^`|~", c) >= 0
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
+
+func isWordByte(c byte) bool {
+	return c >= 0x80 || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
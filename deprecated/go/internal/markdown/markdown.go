@@ -0,0 +1,372 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/textfile"
+)
+
+var (
+	blockquotePattern = regexp.MustCompile(`^(?: {0,3}> ?)+`)
+	fencePattern      = regexp.MustCompile("^[ \t]*(`{3,}|~{3,})")
+	atxPattern        = regexp.MustCompile(`^( {0,3}#{1,6}(?:[ \t]+|$))(.*?)([ \t]+#+[ \t]*|[ \t]*)$`)
+	setextPattern     = regexp.MustCompile(`^ {0,3}(?:=+|-+)[ \t]*$`)
+	thematicPattern   = regexp.MustCompile(`^ {0,3}([-*_])(?:[ \t]*[-*_]){2,}[ \t]*$`)
+	listPattern       = regexp.MustCompile(`^([ \t]*(?:[-*+]|\d{1,9}[.)])(?:[ \t]+|$)(?:\[[ xX]\][ \t]+)?)`)
+	footnotePattern   = regexp.MustCompile(`^ {0,3}\[\^[^\]]+\]:[ \t]*`)
+	linkDefPattern    = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]*\S`)
+	tableDelimiter    = regexp.MustCompile(`^[ \t]*\|?[ \t]*:?-+:?[ \t]*(?:\|[ \t]*:?-+:?[ \t]*)*\|?[ \t]*$`)
+	htmlBlockStart    = regexp.MustCompile(`^ {0,3}<(!--|\?|![A-Za-z]|!\[CDATA\[|/?([A-Za-z][A-Za-z0-9\-]*))`)
+	htmlTagLine       = regexp.MustCompile(`^ {0,3}</?[A-Za-z][A-Za-z0-9\-]*(?:\s[^<>]*)?/?>[ \t]*$`)
+)
+
+// htmlBlockTags CommonMark 中以这些标签开始的行是 HTML 块，直到空行结束
+var htmlBlockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "base": true, "blockquote": true, "body": true,
+	"caption": true, "center": true, "col": true, "colgroup": true, "dd": true, "details": true,
+	"dialog": true, "dir": true, "div": true, "dl": true, "dt": true, "fieldset": true,
+	"figcaption": true, "figure": true, "footer": true, "form": true, "frame": true, "frameset": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "head": true,
+	"header": true, "hr": true, "html": true, "iframe": true, "legend": true, "li": true,
+	"link": true, "main": true, "menu": true, "menuitem": true, "nav": true, "noframes": true,
+	"ol": true, "optgroup": true, "option": true, "p": true, "param": true, "search": true,
+	"section": true, "summary": true, "table": true, "tbody": true, "td": true, "tfoot": true,
+	"th": true, "thead": true, "title": true, "tr": true, "track": true, "ul": true,
+}
+
+// part 文档的一段：原样输出的文本，或者需要翻译的行内内容
+type part struct {
+	raw   string
+	prose *inline
+}
+
+// Unit 一段待翻译的文本，译文通过 Document.Apply 写回
+type Unit struct {
+	Text string
+	HTML bool
+
+	in *inline
+}
+
+// Document 按行解析的 Markdown 文档。只有标题、段落、列表项、表格单元格、脚注和图片的 alt 文本会被翻译，
+// 代码、链接地址、HTML 块和 front matter 原样保留
+type Document struct {
+	parts []part
+	enc   textfile.Encoding
+	// noEOL 最后一行是段落的后续行并且没有换行符，段落之后的换行符需要去掉
+	noEOL bool
+}
+
+// fence 未结束的围栏代码块
+type fence struct {
+	char byte
+	n    int
+}
+
+// Parse 解析 Markdown 文档
+func Parse(src string) *Document {
+	d := &Document{}
+	src, d.enc = textfile.Decode(src)
+
+	lines := strings.SplitAfter(src, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	start := d.frontMatter(lines)
+
+	var (
+		code      *fence
+		htmlEnd   string
+		paragraph *inline
+		inList    bool
+		inTable   bool
+	)
+
+	for i := start; i < len(lines); i++ {
+		line := lines[i]
+		content := strings.TrimSuffix(line, "\n")
+		eol := line[len(content):]
+
+		prefix := blockquotePattern.FindString(content)
+		rest := content[len(prefix):]
+
+		if code != nil {
+			d.raw(line)
+			if m := fencePattern.FindStringSubmatch(rest); m != nil && m[1][0] == code.char && len(m[1]) >= code.n &&
+				strings.TrimSpace(rest[len(m[0]):]) == "" {
+				code = nil
+			}
+			continue
+		}
+
+		if htmlEnd != "" {
+			d.raw(line)
+			if htmlEnd == "\n" && strings.TrimSpace(rest) == "" || htmlEnd != "\n" && strings.Contains(strings.ToLower(rest), htmlEnd) {
+				htmlEnd = ""
+			}
+			continue
+		}
+
+		if strings.TrimSpace(rest) == "" {
+			d.raw(line)
+			paragraph = nil
+			inTable = false
+			continue
+		}
+
+		indented := strings.HasPrefix(rest, "    ") || strings.HasPrefix(rest, "\t")
+		if !indented && !listPattern.MatchString(rest) && paragraph == nil {
+			inList = false
+		}
+
+		if m := fencePattern.FindStringSubmatch(rest); m != nil && (inList || len(m[0])-len(m[1]) <= 3) &&
+			(m[1][0] == '~' || !strings.Contains(rest[len(m[0]):], "`")) {
+			d.raw(line)
+			code = &fence{char: m[1][0], n: len(m[1])}
+			paragraph = nil
+			continue
+		}
+
+		if indented && !inList && paragraph == nil {
+			d.raw(line)
+			continue
+		}
+
+		if end := htmlBlockEnd(rest, paragraph != nil); end != "" {
+			d.raw(line)
+			if end == "\n" || !strings.Contains(strings.ToLower(rest[strings.IndexByte(rest, '<')+1:]), end) {
+				htmlEnd = end
+			}
+			paragraph = nil
+			continue
+		}
+
+		if paragraph != nil && !inTable && setextPattern.MatchString(rest) {
+			d.raw(line)
+			paragraph = nil
+			continue
+		}
+
+		if thematicPattern.MatchString(rest) {
+			d.raw(line)
+			paragraph = nil
+			continue
+		}
+
+		// 列表标记之后可以是标题等其他块，标记并入前缀
+		if m := listPattern.FindString(rest); m != "" && !inTable {
+			prefix += m
+			rest = rest[len(m):]
+			inList = true
+			paragraph = nil
+		}
+
+		if m := atxPattern.FindStringSubmatch(rest); m != nil {
+			d.raw(prefix + m[1])
+			d.prose(m[2])
+			d.raw(m[3] + eol)
+			paragraph = nil
+			continue
+		}
+
+		if m := footnotePattern.FindString(rest); m != "" {
+			prefix += m
+			rest = rest[len(m):]
+			paragraph = nil
+		} else if linkDefPattern.MatchString(rest) && paragraph == nil {
+			d.raw(line)
+			continue
+		}
+
+		if inTable || paragraph == nil && strings.Contains(rest, "|") && i+1 < len(lines) &&
+			tableDelimiter.MatchString(strings.TrimPrefix(strings.TrimSuffix(lines[i+1], "\n"), prefix)) &&
+			strings.Contains(lines[i+1], "|") {
+			if inTable && !strings.Contains(rest, "|") {
+				inTable = false
+			} else {
+				if tableDelimiter.MatchString(rest) {
+					d.raw(line)
+				} else {
+					d.raw(prefix)
+					d.tableRow(rest)
+					d.raw(eol)
+				}
+				inTable = true
+				paragraph = nil
+				continue
+			}
+		}
+
+		if paragraph != nil {
+			// 段落的后续行合并到同一段中翻译，未翻译时按原文输出
+			paragraph.source += "\n" + strings.TrimLeft(rest, " \t")
+			paragraph.raw += "\n" + content
+			d.noEOL = eol == ""
+			continue
+		}
+
+		trimmed := strings.TrimLeft(rest, " \t")
+		d.raw(prefix + rest[:len(rest)-len(trimmed)])
+		paragraph = d.prose(trimmed)
+		paragraph.raw = trimmed
+		d.raw(eol)
+	}
+
+	// 段落内容在合并后才完整，统一解析行内语法
+	for _, p := range d.parts {
+		if p.prose != nil {
+			raw := p.prose.raw
+			*p.prose = *parseInline(p.prose.source)
+			p.prose.raw = raw
+		}
+	}
+	return d
+}
+
+// frontMatter 开头的 YAML（---）或 TOML（+++）front matter 原样保留，返回之后的行号
+func (d *Document) frontMatter(lines []string) int {
+	if len(lines) == 0 {
+		return 0
+	}
+	delimiter := strings.TrimRight(lines[0], " \t\n")
+	if delimiter != "---" && delimiter != "+++" {
+		return 0
+	}
+	for i := 1; i < len(lines); i++ {
+		end := strings.TrimRight(lines[i], " \t\n")
+		if end == delimiter || delimiter == "---" && end == "..." {
+			d.raw(strings.Join(lines[:i+1], ""))
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// htmlBlockEnd 判断行是否开始 HTML 块，返回结束条件："\n" 表示空行，否则为结束标记
+func htmlBlockEnd(line string, inParagraph bool) string {
+	m := htmlBlockStart.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	switch m[1] {
+	case "!--":
+		return "-->"
+	case "?":
+		return "?>"
+	case "![CDATA[":
+		return "]]>"
+	}
+	if strings.HasPrefix(m[1], "!") {
+		return ">"
+	}
+
+	name := strings.ToLower(m[2])
+	switch name {
+	case "pre", "script", "style", "textarea":
+		if !strings.HasPrefix(m[1], "/") {
+			return "</" + name + ">"
+		}
+	}
+	if htmlBlockTags[name] {
+		return "\n"
+	}
+	// 单独一行的其他标签也是 HTML 块，但不能打断段落
+	if !inParagraph && htmlTagLine.MatchString(line) {
+		return "\n"
+	}
+	return ""
+}
+
+func (d *Document) raw(s string) {
+	if s == "" {
+		return
+	}
+	if n := len(d.parts); n > 0 && d.parts[n-1].prose == nil {
+		d.parts[n-1].raw += s
+		return
+	}
+	d.parts = append(d.parts, part{raw: s})
+}
+
+func (d *Document) prose(s string) *inline {
+	in := &inline{source: s}
+	d.parts = append(d.parts, part{prose: in})
+	return in
+}
+
+// tableRow 按未转义的 | 拆分单元格，单元格两侧的空白原样保留
+func (d *Document) tableRow(row string) {
+	start := 0
+	cell := func(end int) {
+		s := row[start:end]
+		trimmed := strings.TrimSpace(s)
+		if trimmed == "" {
+			d.raw(s)
+			return
+		}
+		lead := strings.Index(s, trimmed)
+		d.raw(s[:lead])
+		d.prose(trimmed)
+		d.raw(s[lead+len(trimmed):])
+	}
+
+	for i := 0; i < len(row); i++ {
+		switch row[i] {
+		case '\\':
+			i++
+		case '|':
+			cell(i)
+			d.raw("|")
+			start = i + 1
+		}
+	}
+	cell(len(row))
+}
+
+// Units 返回需要翻译的文本，不包含文字的段落（只有代码、链接或数字）跳过
+func (d *Document) Units() []Unit {
+	var units []Unit
+	var collect func(in *inline)
+	collect = func(in *inline) {
+		if in.translatable() {
+			units = append(units, Unit{Text: in.text, HTML: in.html, in: in})
+		}
+		for _, e := range in.elements {
+			if e.image != nil {
+				collect(e.image)
+			}
+		}
+	}
+	for _, p := range d.parts {
+		if p.prose != nil {
+			collect(p.prose)
+		}
+	}
+	return units
+}
+
+func (d *Document) Apply(units []Unit, results []string) {
+	for i, unit := range units {
+		unit.in.result = results[i]
+		unit.in.translated = true
+	}
+}
+
+func (d *Document) String() string {
+	var sb strings.Builder
+	for _, p := range d.parts {
+		if p.prose != nil {
+			sb.WriteString(p.prose.render())
+		} else {
+			sb.WriteString(p.raw)
+		}
+	}
+
+	out := sb.String()
+	if d.noEOL {
+		out = strings.TrimSuffix(out, "\n")
+	}
+	return d.enc.Encode(out)
+}
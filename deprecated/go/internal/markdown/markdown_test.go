@@ -0,0 +1,144 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var tagPattern = regexp.MustCompile(`<[^<>]*>`)
+
+// upper 模拟翻译：标签之外的文字转为大写
+func upper(d *Document) {
+	units := d.Units()
+	results := make([]string, len(units))
+	for i, u := range units {
+		var sb strings.Builder
+		last := 0
+		for _, m := range tagPattern.FindAllStringIndex(u.Text, -1) {
+			sb.WriteString(strings.ToUpper(u.Text[last:m[0]]))
+			sb.WriteString(u.Text[m[0]:m[1]])
+			last = m[1]
+		}
+		sb.WriteString(strings.ToUpper(u.Text[last:]))
+		results[i] = sb.String()
+	}
+	d.Apply(units, results)
+}
+
+func texts(units []Unit) []string {
+	var out []string
+	for _, u := range units {
+		out = append(out, u.Text)
+	}
+	return out
+}
+
+const sample = "---\ntitle: Hello\n---\n\n" +
+	"# Hello *world* #\n\n" +
+	"Some **bold** text with `code` and a [link](https://example.com \"title\").\nSecond line  \nthird line.\n\n" +
+	"- item one\n- [ ] task _two_\n  continued\n1. ![alt text](img.png) caption\n\n" +
+	"```go\nfunc main() {}\n```\n\n" +
+	"    indented code\n\n" +
+	"> quote here\n> more\n\n" +
+	"| Name | Value |\n|------|------:|\n| foo  | bar \\| baz |\n\n" +
+	"<div>\nhtml block\n</div>\n\n" +
+	"[ref]: https://example.com\n" +
+	"See https://go.dev/doc. and <https://x.y> &copy; 2024.\n\n" +
+	"Title\n=====\n\n" +
+	"[^1]: Footnote text.\n"
+
+func TestParseRoundTrip(t *testing.T) {
+	assert.Equal(t, sample, Parse(sample).String())
+
+	assert.Equal(t, "", Parse("").String())
+	assert.Equal(t, "para\n> continued", Parse("para\n> continued").String())
+}
+
+func TestUnits(t *testing.T) {
+	d := Parse(sample)
+	assert.Equal(t, []string{
+		`Hello <i id="0">world</i>`,
+		`Some <b id="0">bold</b> text with <x id="1"></x> and a <a id="2">link</a>. Second line<x id="3"></x>third line.`,
+		"item one",
+		`task <i id="0">two</i> continued`,
+		`<x id="0"></x> caption`,
+		"alt text",
+		"quote here more",
+		"Name",
+		"Value",
+		"foo",
+		`bar <x id="0"></x> baz`,
+		`See <x id="0"></x>. and <x id="1"></x> <x id="2"></x> 2024.`,
+		"Title",
+		"Footnote text.",
+	}, texts(d.Units()))
+}
+
+func TestTranslate(t *testing.T) {
+	d := Parse(sample)
+	upper(d)
+
+	want := "---\ntitle: Hello\n---\n\n" +
+		"# HELLO *WORLD* #\n\n" +
+		"SOME **BOLD** TEXT WITH `code` AND A [LINK](https://example.com \"title\"). SECOND LINE  \nTHIRD LINE.\n\n" +
+		"- ITEM ONE\n- [ ] TASK _TWO_ CONTINUED\n1. ![ALT TEXT](img.png) CAPTION\n\n" +
+		"```go\nfunc main() {}\n```\n\n" +
+		"    indented code\n\n" +
+		"> QUOTE HERE MORE\n\n" +
+		"| NAME | VALUE |\n|------|------:|\n| FOO  | BAR \\| BAZ |\n\n" +
+		"<div>\nhtml block\n</div>\n\n" +
+		"[ref]: https://example.com\n" +
+		"SEE https://go.dev/doc. AND <https://x.y> &copy; 2024.\n\n" +
+		"TITLE\n=====\n\n" +
+		"[^1]: FOOTNOTE TEXT.\n"
+	assert.Equal(t, want, d.String())
+}
+
+func TestUntouchedBlocks(t *testing.T) {
+	src := "~~~\ncode\n~~~\n\n<!--\ncomment\n-->\n\n<pre>\nkeep\n\nthis\n</pre>\n\n---\n\n+++\n\n12345\n"
+	d := Parse(src)
+	assert.Empty(t, d.Units())
+	assert.Equal(t, src, d.String())
+
+	// 列表中缩进的内容不是代码块
+	d = Parse("- item\n\n    nested paragraph\n")
+	assert.Equal(t, []string{"item", "nested paragraph"}, texts(d.Units()))
+}
+
+func TestInlineEscaping(t *testing.T) {
+	d := Parse("Use a < b && c \\* d\n")
+	units := d.Units()
+	require.Len(t, units, 1)
+	assert.True(t, units[0].HTML)
+	assert.Equal(t, `Use a &lt; b &amp;&amp; c <x id="0"></x> d`, units[0].Text)
+
+	d.Apply(units, []string{`Nutze a &lt; b &amp;&amp; c <x id="0"></x> d`})
+	assert.Equal(t, "Nutze a < b && c \\* d\n", d.String())
+
+	d = Parse("Plain 5 * 3 and snake_case_name.\n")
+	units = d.Units()
+	require.Len(t, units, 1)
+	assert.False(t, units[0].HTML)
+	assert.Equal(t, "Plain 5 * 3 and snake_case_name.", units[0].Text)
+}
+
+func TestRestore(t *testing.T) {
+	in := parseInline("Click **here** to see `code` and [docs](/docs).")
+	require.True(t, in.html)
+
+	// 标签内外的空格移到标记外侧，标签顺序可以变化
+	assert.Equal(t, "Siehe [Doku](/docs) und `code`, klicken Sie **hier**.",
+		in.restore(`Siehe <a id="2">Doku </a> und <x id="1"></x>, klicken Sie <b id="0"> hier</b>.`))
+
+	// 丢失的原子元素追加到末尾，无法识别的标签和它的结束标签被忽略
+	assert.Equal(t, "Klicken **hier** und [Doku](/docs). `code`",
+		in.restore(`Klicken <b id="0">hier</b> und <a id="2">Doku</a><i>.</i>`))
+
+	// 未闭合的元素在末尾闭合
+	assert.Equal(t, "Klicken **hier `code` [Doku](/docs)**",
+		in.restore(`Klicken <b id="0">hier <x id="1"></x> <a id="2">Doku</a>`))
+}
@@ -0,0 +1,288 @@
+package mask
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Matcher 返回文本中需要保护的片段（字节偏移），可以重叠，由 Masker 去重
+type Matcher func(text string) [][2]int
+
+// Regexp 把正则表达式包装为 Matcher
+func Regexp(re *regexp.Regexp) Matcher {
+	return func(text string) [][2]int {
+		var spans [][2]int
+		for _, m := range re.FindAllStringIndex(text, -1) {
+			spans = append(spans, [2]int{m[0], m[1]})
+		}
+		return spans
+	}
+}
+
+// Masker 在翻译前把 Matcher 匹配的片段替换为空的 <x id="N"></x> 元素，翻译后还原。
+// 替换后的文本按 HTML 翻译，worker 会原样保留标签；原文不是 HTML 时先转义，还原时再反转义
+type Masker struct {
+	matchers []Matcher
+}
+
+func New(matchers ...Matcher) *Masker {
+	return &Masker{matchers: matchers}
+}
+
+// Masked 替换后的文本，按 HTML 字段决定翻译模式
+type Masked struct {
+	Text string
+	HTML bool
+
+	tokens []string
+	// offsets 每个标记之前的原文长度占全部原文（不含标记）的比例，用于插回模型丢失的标记
+	offsets []float64
+	escape  bool
+	// base 第一个标记的编号。HTML 原文中已有的 <x> 元素（例如上一层遮盖的标记）编号小于 base，还原时保留
+	base     int
+	existing map[int]bool
+}
+
+var sentinelPattern = regexp.MustCompile(`<x\s+id="?(\d+)"?\s*(?:/>|>\s*</x>)`)
+
+func sentinel(i int) string {
+	return `<x id="` + strconv.Itoa(i) + `"></x>`
+}
+
+// Mask 替换文本中匹配的片段。没有匹配时原样返回，isHTML 不变。
+// HTML 原文中标签内部（如属性值）的匹配会被忽略
+func (m *Masker) Mask(text string, isHTML bool) Masked {
+	spans := m.spans(text, isHTML)
+	if len(spans) == 0 {
+		return Masked{Text: text, HTML: isHTML}
+	}
+
+	escape := func(s string) string { return s }
+	var existing map[int]bool
+	base := 0
+	if isHTML {
+		for _, m := range sentinelPattern.FindAllStringSubmatch(text, -1) {
+			if id, err := strconv.Atoi(m[1]); err == nil {
+				if existing == nil {
+					existing = make(map[int]bool)
+				}
+				existing[id] = true
+				base = max(base, id+1)
+			}
+		}
+	} else {
+		escape = html.EscapeString
+	}
+
+	var sb strings.Builder
+	tokens := make([]string, 0, len(spans))
+	offsets := make([]float64, 0, len(spans))
+	last, plain := 0, 0
+	for _, span := range spans {
+		sb.WriteString(escape(text[last:span[0]]))
+		sb.WriteString(sentinel(base + len(tokens)))
+		plain += span[0] - last
+		tokens = append(tokens, text[span[0]:span[1]])
+		offsets = append(offsets, float64(plain))
+		last = span[1]
+	}
+	sb.WriteString(escape(text[last:]))
+	plain += len(text) - last
+
+	for i := range offsets {
+		if plain > 0 {
+			offsets[i] /= float64(plain)
+		} else {
+			offsets[i] = 1
+		}
+	}
+
+	return Masked{Text: sb.String(), HTML: true, tokens: tokens, offsets: offsets, escape: !isHTML, base: base, existing: existing}
+}
+
+// Replace 返回把标记还原为 fn(原始片段) 的副本，用于术语表等需要替换匹配内容的场景。
+// 原文是 HTML 时替换内容会被转义
+func (ms Masked) Replace(fn func(token string) string) Masked {
+	tokens := make([]string, len(ms.tokens))
+	for i, token := range ms.tokens {
+		tokens[i] = fn(token)
+		if !ms.escape {
+			tokens[i] = html.EscapeString(tokens[i])
+		}
+	}
+	ms.tokens = tokens
+	return ms
+}
+
+// Restore 把译文中的标记还原为原始片段。模型丢失的标记按原文中的相对位置插回，重复的标记只还原一次
+func (ms Masked) Restore(translated string) string {
+	if len(ms.tokens) == 0 {
+		return translated
+	}
+
+	unescape := func(s string) string { return s }
+	if ms.escape {
+		unescape = html.UnescapeString
+	}
+
+	used := make([]bool, len(ms.tokens))
+	var sb strings.Builder
+	last := 0
+	for _, m := range sentinelPattern.FindAllStringSubmatchIndex(translated, -1) {
+		sb.WriteString(unescape(translated[last:m[0]]))
+		last = m[1]
+
+		i, err := strconv.Atoi(translated[m[2]:m[3]])
+		if err != nil {
+			continue
+		}
+		if ms.existing[i] {
+			sb.WriteString(translated[m[0]:m[1]])
+			continue
+		}
+		i -= ms.base
+		if i < 0 || i >= len(ms.tokens) || used[i] {
+			continue
+		}
+		used[i] = true
+		sb.WriteString(ms.tokens[i])
+	}
+	sb.WriteString(unescape(translated[last:]))
+
+	result := sb.String()
+	for i, token := range ms.tokens {
+		if !used[i] {
+			result = reinsert(result, token, ms.offsets[i], !ms.escape)
+		}
+	}
+	return result
+}
+
+// reinsert 把 token 插入 result 中大约 ratio 处：有空白时放在最近的空白之后，
+// 没有空白（如中文、日文）时放在对应的字符位置。不会插入到 HTML 标签内部
+func reinsert(result, token string, ratio float64, isHTML bool) string {
+	if ratio >= 1 || result == "" {
+		if result != "" && !strings.HasSuffix(result, " ") {
+			result += " "
+		}
+		return result + token
+	}
+
+	var tags [][]int
+	if isHTML {
+		tags = tagPattern.FindAllStringIndex(result, -1)
+	}
+	// boundary 调整到标签之后，返回是否可以在 i 处插入
+	boundary := func(i int) (int, bool) {
+		for _, tag := range tags {
+			if tag[0] < i && i < tag[1] {
+				return tag[1], false
+			}
+		}
+		return i, utf8.RuneStart(result[i])
+	}
+
+	if ratio <= 0 {
+		if strings.ContainsAny(result, " \t\n") {
+			return token + " " + result
+		}
+		return token + result
+	}
+
+	target := int(ratio * float64(len(result)))
+	if strings.ContainsAny(result, " \t\n") {
+		best := -1
+		for i := 0; i < len(result); i++ {
+			if c := result[i]; c != ' ' && c != '\t' && c != '\n' {
+				continue
+			}
+			if _, ok := boundary(i); !ok {
+				continue
+			}
+			if best < 0 || abs(i-target) < abs(best-target) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			return result[:best+1] + token + " " + result[best+1:]
+		}
+	}
+
+	for i := target; i < len(result); {
+		j, ok := boundary(i)
+		if ok {
+			return result[:j] + token + result[j:]
+		}
+		if j == i {
+			j++
+		}
+		i = j
+	}
+	return result + token
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// spans 合并所有 Matcher 的结果，重叠时保留先开始的（同时开始取更长的）
+func (m *Masker) spans(text string, isHTML bool) [][2]int {
+	var tags [][]int
+	if isHTML {
+		tags = tagPattern.FindAllStringIndex(text, -1)
+	}
+	// 开始或结束在标签内部的匹配（例如属性值中的链接）不替换，完整包含标签的匹配可以替换
+	inTag := func(span [2]int) bool {
+		for _, tag := range tags {
+			if tag[0] < span[0] && span[0] < tag[1] || tag[0] < span[1] && span[1] < tag[1] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var all [][2]int
+	for _, match := range m.matchers {
+		for _, span := range match(text) {
+			if span[0] < span[1] && !inTag(span) {
+				all = append(all, span)
+			}
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i][0] != all[j][0] {
+			return all[i][0] < all[j][0]
+		}
+		return all[i][1] > all[j][1]
+	})
+
+	var spans [][2]int
+	end := 0
+	for _, span := range all {
+		if span[0] < end {
+			continue
+		}
+		spans = append(spans, span)
+		end = span[1]
+	}
+	return spans
+}
+
+// OnlySentinels 判断 HTML 文本中的标签是否都是 Mask 生成的标记，这样的文本可以像纯文本一样处理
+func OnlySentinels(text string) bool {
+	tags := tagPattern.FindAllStringIndex(text, -1)
+	sentinels := sentinelPattern.FindAllStringIndex(text, -1)
+	// 每个标记包含开始和结束两个标签，自闭合形式只有一个
+	n := 0
+	for _, s := range sentinels {
+		n += len(tagPattern.FindAllStringIndex(text[s[0]:s[1]], -1))
+	}
+	return n == len(tags)
+}
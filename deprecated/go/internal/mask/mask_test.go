@@ -0,0 +1,124 @@
+package mask
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func matches(m Matcher, text string) []string {
+	var out []string
+	for _, span := range m(text) {
+		out = append(out, text[span[0]:span[1]])
+	}
+	return out
+}
+
+func TestPrintf(t *testing.T) {
+	assert.Equal(t, []string{"%s", "%d", "%1$s", "%-5.2f", "%(name)s", "%%", "%ld"},
+		matches(Printf, "%s has %d, %1$s %-5.2f %(name)s %% %ld"))
+	assert.Empty(t, matches(Printf, "100% sure, 50% off"))
+}
+
+func TestBraces(t *testing.T) {
+	assert.Equal(t, []string{"{0}", "{name}", "{}", "{{count}}", "{{- name}}", "%{var}", "${user.id}"},
+		matches(Braces, "{0} {name} {} {{count}} {{- name}} %{var} ${user.id}"))
+	assert.Empty(t, matches(Braces, "{not a placeholder}"))
+}
+
+func TestHTMLTags(t *testing.T) {
+	assert.Equal(t, []string{`<a href="/docs">`, "</a>", "<br/>", "<strong>", "</strong>"},
+		matches(HTMLTags, `Read <a href="/docs">the docs</a><br/> or <strong>ask</strong> if 1 < 2 > 0`))
+}
+
+func TestLinked(t *testing.T) {
+	assert.Equal(t, []string{"$t(common.ok)", "@:message.hello", "@.lower:name", "@:(key.with space)"},
+		matches(Linked, "$t(common.ok) @:message.hello @.lower:name. @:(key.with space) user@example.com"))
+}
+
+func TestICU(t *testing.T) {
+	text := "You have {count, plural, one {# message from {name}} other {# messages}} and {g, select, male {he} other {they}}."
+	assert.Equal(t, []string{
+		"{count, plural, one {# message from {name}} other {# messages}}",
+		"{g, select, male {he} other {they}}",
+	}, matches(ICU, text))
+
+	assert.Equal(t, []string{"{n, plural, other {'{'quoted'}' #}}"}, matches(ICU, "{n, plural, other {'{'quoted'}' #}}"))
+	assert.Empty(t, matches(ICU, "{count, plural, one {unclosed}"))
+}
+
+func TestMaskRestore(t *testing.T) {
+	m := New(Printf, Braces, ICU)
+
+	masked := m.Mask("Hello %s, you have {count, plural, other {# items}} & {n} more", false)
+	assert.True(t, masked.HTML)
+	assert.Equal(t, `Hello <x id="0"></x>, you have <x id="1"></x> &amp; <x id="2"></x> more`, masked.Text)
+
+	restored := masked.Restore(`Hallo <x id="0"></x>, Sie haben <x id="1"/> &amp; <x id="2"></x> mehr`)
+	assert.Equal(t, "Hallo %s, Sie haben {count, plural, other {# items}} & {n} mehr", restored)
+}
+
+func TestMaskRestoreDroppedAndDuplicated(t *testing.T) {
+	m := New(Printf)
+
+	masked := m.Mask("%s and %d", false)
+	assert.Equal(t, "%s und %d", masked.Restore(`<x id="0"></x> und <x id="0"></x><x id="9"></x>`))
+}
+
+func TestMaskNoMatches(t *testing.T) {
+	m := New(Regexp(regexp.MustCompile(`https?://[^\s<]+`)))
+
+	masked := m.Mask("a < b", false)
+	assert.Equal(t, Masked{Text: "a < b"}, masked)
+	assert.Equal(t, "a > b", masked.Restore("a > b"))
+
+	masked = m.Mask("<b>see https://example.com</b>", true)
+	assert.Equal(t, `<b>see <x id="0"></x></b>`, masked.Text)
+	assert.Equal(t, "<b>siehe https://example.com</b>", masked.Restore(`<b>siehe <x id="0"></x></b>`))
+}
+
+func TestMaskHTMLTagsAndExistingSentinels(t *testing.T) {
+	m := New(Braces)
+
+	// 属性中的匹配不替换，原有的 <x> 元素保留
+	masked := m.Mask(`<a title="{name}">{name}</a> <x id="0"></x> <x id="3"/>`, true)
+	assert.Equal(t, `<a title="{name}"><x id="4"></x></a> <x id="0"></x> <x id="3"/>`, masked.Text)
+	assert.Equal(t, `<a title="{name}">{name}</a> <x id="3"></x> <x id="0"></x>`,
+		masked.Restore(`<a title="{name}"><x id="4"></x></a> <x id="3"></x> <x id="0"></x><x id="7"></x>`))
+}
+
+func TestMaskReplace(t *testing.T) {
+	m := New(Regexp(regexp.MustCompile(`AT&T`)))
+	upper := func(token string) string { return token + " Inc." }
+
+	masked := m.Mask("Call AT&T now", false).Replace(upper)
+	assert.Equal(t, `Call <x id="0"></x> now`, masked.Text)
+	assert.Equal(t, "Ruf AT&T Inc. an", masked.Restore(`Ruf <x id="0"></x> an`))
+
+	masked = m.Mask("<b>AT&T</b>", true).Replace(upper)
+	assert.Equal(t, "<b>AT&amp;T Inc.</b>", masked.Restore(`<b><x id="0"></x></b>`))
+}
+
+func TestMaskRestoreReinsertsDropped(t *testing.T) {
+	m := New(URL)
+
+	masked := m.Mask("Docs: https://example.com and more", false)
+	assert.Equal(t, "Doku: https://example.com und mehr", masked.Restore("Doku: und mehr"))
+
+	masked = m.Mask("See https://example.com", false)
+	assert.Equal(t, "请参阅 https://example.com", masked.Restore("请参阅"))
+
+	masked = m.Mask("打开https://example.com查看详情", false)
+	assert.Equal(t, "打开https://example.com查看详情", masked.Restore("打开查看详情"))
+
+	masked = m.Mask(`<b title="a b c">https://example.com</b> more text here`, true)
+	assert.Equal(t, `<b title="a b c">mehr</b> https://example.com Text hier`,
+		masked.Restore(`<b title="a b c">mehr</b> Text hier`))
+}
+
+func TestOnlySentinels(t *testing.T) {
+	assert.True(t, OnlySentinels("plain text"))
+	assert.True(t, OnlySentinels(`see <x id="0"></x> and <x id="1"/> &amp; more`))
+	assert.False(t, OnlySentinels(`<b>bold</b> <x id="0"></x>`))
+}
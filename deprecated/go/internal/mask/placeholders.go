@@ -0,0 +1,80 @@
+package mask
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// printfPattern printf 风格占位符：%s、%d、%1$s、%-5.2f、%(name)s、%%
+	printfPattern = regexp.MustCompile(`%(?:\([A-Za-z0-9_.]+\)|\d+\$)?[-+0#]*(?:\*|\d+)?(?:\.(?:\*|\d+))?(?:hh|ll|[hlLqjzt])?[diouxXeEfFgGaAcspn@%]`)
+	// bracePattern 花括号插值：{0}、{name}、{}、{{count}}、{{- name}}、%{var}、${var}
+	bracePattern = regexp.MustCompile(`\{\{[^{}]+\}\}|[%$]?\{[A-Za-z0-9_.\-]*\}`)
+	// tagPattern HTML/XML 开始、结束和自闭合标签
+	tagPattern = regexp.MustCompile(`</?[A-Za-z][A-Za-z0-9:\-]*(?:\s[^<>]*)?/?>`)
+	// linkedPattern i18next 的 $t(key) 嵌套和 vue-i18n 的 @:key、@.lower:key 链接
+	linkedPattern = regexp.MustCompile(`\$t\([^()]*\)|@(?:\.[a-z]+)?:(?:\([^()]*\)|[A-Za-z0-9_.\-]*[A-Za-z0-9_])`)
+	// icuStart ICU 消息格式的参数开头，如 {count, plural,
+	icuStart = regexp.MustCompile(`\{\s*[A-Za-z0-9_]+\s*,\s*(?:plural|select|selectordinal|number|date|time)\b`)
+)
+
+// Printf 匹配 printf 风格占位符
+var Printf = Regexp(printfPattern)
+
+// Braces 匹配花括号插值
+var Braces = Regexp(bracePattern)
+
+// HTMLTags 匹配 HTML 标签，标签之间的文本仍然翻译
+var HTMLTags = Regexp(tagPattern)
+
+// Linked 匹配 i18n 库引用其他消息的语法
+var Linked = Regexp(linkedPattern)
+
+// ICU 匹配完整的 ICU 消息参数（包括嵌套的花括号），如 {count, plural, one {# item} other {# items}}
+func ICU(text string) [][2]int {
+	var spans [][2]int
+	offset := 0
+	for {
+		loc := icuStart.FindStringIndex(text[offset:])
+		if loc == nil {
+			return spans
+		}
+
+		start := offset + loc[0]
+		end := matchBrace(text, start)
+		if end < 0 {
+			return spans
+		}
+		spans = append(spans, [2]int{start, end})
+		offset = end
+	}
+}
+
+// matchBrace 返回与 text[start] 处的 { 配对的 } 之后的位置，不配对时返回 -1。
+// ICU 中单引号包围的内容不参与计数
+func matchBrace(text string, start int) int {
+	depth := 0
+	quoted := false
+	for i := start; i < len(text); i++ {
+		switch text[i] {
+		case '\'':
+			if strings.HasPrefix(text[i:], "''") {
+				i++
+				continue
+			}
+			quoted = !quoted
+		case '{':
+			if !quoted {
+				depth++
+			}
+		case '}':
+			if !quoted {
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,97 @@
+package mask
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// urlPattern http、https、ftp 链接和 www. 开头的地址，只包含 ASCII 字符，不包括末尾的标点
+	urlPattern = regexp.MustCompile(`\b(?:(?:https?|ftp)://|www\.)[!#-&(-;=?-_a-~]*[A-Za-z0-9/#=&_~%+\-]`)
+	// emailPattern 电子邮件地址
+	emailPattern = regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}\b`)
+	// identifierPattern 代码标识符：`行内代码`、camelCase、snake_case、SCREAMING_SNAKE、函数调用 foo()、os.path.join()
+	identifierPattern = regexp.MustCompile("`[^`\\n]+`" +
+		`|\b[A-Za-z_][A-Za-z0-9_]*(?:\.[A-Za-z_][A-Za-z0-9_]*)*\(\)` +
+		`|\b[a-z][a-z0-9]*(?:[A-Z][a-z0-9]*)+\b` +
+		`|\b[A-Za-z][A-Za-z0-9]*(?:_[A-Za-z0-9]+)+\b`)
+	// numberPattern 带单位的数字：50%、3.5 GB、100km/h、25°C、1,024 px
+	numberPattern = regexp.MustCompile(`\b\d+(?:[.,]\d+)*(?:\s?%|\s?°[CF]\b|\s?(?:[kMGTP]i?B|[kMG]?Hz|[kMG]?W|mAh|km/h|[kcmµμn]?m|[km]?g|ms|mph|px|pt|rem|em|dpi|fps|rpm|[mM]?L|ml)\b)`)
+
+	// openTagPattern 开始标签，捕获标签名和属性
+	openTagPattern = regexp.MustCompile(`<([A-Za-z][A-Za-z0-9\-]*)(\s[^<>]*)?>`)
+	// translateNoPattern translate="no" 属性
+	translateNoPattern = regexp.MustCompile(`(?i)\stranslate\s*=\s*["']?no\b`)
+	// notranslateClassPattern class 属性中包含 notranslate
+	notranslateClassPattern = regexp.MustCompile(`(?i)\sclass\s*=\s*(?:"[^"]*\bnotranslate\b[^"]*"|'[^']*\bnotranslate\b[^']*'|notranslate\b)`)
+)
+
+// voidElements 没有结束标签的 HTML 元素
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// URL 匹配链接地址
+var URL = Regexp(urlPattern)
+
+// Email 匹配电子邮件地址
+var Email = Regexp(emailPattern)
+
+// Identifier 匹配代码标识符和行内代码
+var Identifier = Regexp(identifierPattern)
+
+// Number 匹配带单位的数字和百分比
+var Number = Regexp(numberPattern)
+
+// NoTranslate 匹配 HTML 中标记为不翻译的整个元素：translate="no" 或 class 包含 notranslate。
+// 只适用于 HTML 文本，没有结束标签的元素忽略
+func NoTranslate(text string) [][2]int {
+	var spans [][2]int
+	for _, m := range openTagPattern.FindAllStringSubmatchIndex(text, -1) {
+		attrs := ""
+		if m[4] >= 0 {
+			attrs = text[m[4]:m[5]]
+		}
+		if !translateNoPattern.MatchString(attrs) && !notranslateClassPattern.MatchString(attrs) {
+			continue
+		}
+
+		name := strings.ToLower(text[m[2]:m[3]])
+		if voidElements[name] || strings.HasSuffix(attrs, "/") {
+			spans = append(spans, [2]int{m[0], m[1]})
+			continue
+		}
+		if end := closingTag(text, name, m[1]); end > 0 {
+			spans = append(spans, [2]int{m[0], end})
+		}
+	}
+	return spans
+}
+
+// closingTag 从 offset 开始查找与 name 配对的结束标签，返回其结束位置，找不到时返回 -1
+func closingTag(text, name string, offset int) int {
+	depth := 1
+	for _, loc := range tagPattern.FindAllStringIndex(text[offset:], -1) {
+		tag := text[offset+loc[0] : offset+loc[1]]
+		closing := strings.HasPrefix(tag, "</")
+		tagName := strings.ToLower(strings.TrimLeft(tag, "</"))
+		if i := strings.IndexAny(tagName, " \t\r\n/>"); i >= 0 {
+			tagName = tagName[:i]
+		}
+		if tagName != name {
+			continue
+		}
+
+		switch {
+		case closing:
+			depth--
+			if depth == 0 {
+				return offset + loc[1]
+			}
+		case !strings.HasSuffix(tag, "/>"):
+			depth++
+		}
+	}
+	return -1
+}
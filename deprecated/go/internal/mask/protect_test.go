@@ -0,0 +1,40 @@
+package mask
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLAndEmail(t *testing.T) {
+	assert.Equal(t, []string{"https://example.com/a?b=1&c=2", "www.example.org/docs", "ftp://files.example.com"},
+		matches(URL, "Go to https://example.com/a?b=1&c=2. Or (www.example.org/docs), ftp://files.example.com!"))
+	assert.Equal(t, []string{"support@example.com", "first.last+tag@mail.example.co.uk"},
+		matches(Email, "Mail support@example.com or first.last+tag@mail.example.co.uk."))
+}
+
+func TestIdentifier(t *testing.T) {
+	assert.Equal(t, []string{"`npm install`", "getUserName", "user_id", "MAX_RETRIES", "os.path.join()", "main()"},
+		matches(Identifier, "Run `npm install`, call getUserName with user_id and MAX_RETRIES via os.path.join() or main()."))
+	assert.Empty(t, matches(Identifier, "A normal English sentence, e.g. this one."))
+}
+
+func TestNumber(t *testing.T) {
+	assert.Equal(t, []string{"50%", "3.5 GB", "100km/h", "25°C", "1,024 px", "200 ms"},
+		matches(Number, "50% of 3.5 GB at 100km/h and 25°C, 1,024 px in 200 ms"))
+	assert.Empty(t, matches(Number, "5 minutes and 3 apples"))
+}
+
+func TestNoTranslate(t *testing.T) {
+	text := `Use <span translate="no">Acme <b>Cloud</b></span> and <code class="lang notranslate">a<code>b</code></code>` +
+		`<img class=notranslate src="x.png"> <div translate="yes">x</div> <span translate="no">unclosed`
+	assert.Equal(t, []string{
+		`<span translate="no">Acme <b>Cloud</b></span>`,
+		`<code class="lang notranslate">a<code>b</code></code>`,
+		`<img class=notranslate src="x.png">`,
+	}, matches(NoTranslate, text))
+
+	m := New(NoTranslate, URL)
+	masked := m.Mask(`<p>Ask <span translate="no">Acme</span> at <a href="https://example.com">https://example.com</a></p>`, true)
+	assert.Equal(t, `<p>Ask <x id="0"></x> at <a href="https://example.com"><x id="1"></x></a></p>`, masked.Text)
+}
@@ -1,37 +1,141 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xxnuo/MTranServer/internal/auth"
 	"github.com/xxnuo/MTranServer/internal/logger"
 )
 
+const keyContextKey = "mtranserver.key"
+
+// Auth 使用单一令牌保护路由，可访问所有分组
 func Auth(apiToken string) gin.HandlerFunc {
+	return RequireScope(&auth.Authenticator{
+		APIToken: func() string { return apiToken },
+	}, auth.ScopeAll)
+}
+
+// RequireScope 校验请求令牌并要求密钥可访问指定路由分组，
+// 通过后可用 KeyFromContext 取得密钥
+func RequireScope(a *auth.Authenticator, scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if !a.Enabled(scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Admin API is disabled, set an admin token, an API token or a key with the admin scope to enable it",
+			})
+			c.Abort()
+			return
+		}
 
-		if apiToken == "" {
+		if !a.Required(scope) {
 			c.Next()
 			return
 		}
 
-		token := c.GetHeader("Authorization")
-		if token != "" {
-			token = strings.TrimPrefix(token, "Bearer ")
-		} else {
-			token = c.Query("token")
+		key, err := a.Authenticate(ExtractToken(c))
+		if err != nil {
+			logger.Warn("Unauthorized access attempt from %s to %s: %v", c.ClientIP(), c.Request.URL.Path, err)
+			message := "Unauthorized"
+			if errors.Is(err, auth.ErrKeyExpired) {
+				message = "API key has expired"
+			}
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": message,
+			})
+			c.Abort()
+			return
 		}
 
-		if token != apiToken {
-			logger.Warn("Unauthorized access attempt from %s to %s", c.ClientIP(), c.Request.URL.Path)
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "Unauthorized",
+		if scope != auth.ScopeAll && !key.AllowsScope(scope) {
+			logger.Warn("Key %s is not allowed to access %s", key.Name, c.Request.URL.Path)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Forbidden",
 			})
 			c.Abort()
 			return
 		}
 
+		c.Set(keyContextKey, key)
 		c.Next()
 	}
 }
+
+// KeyFromContext 返回当前请求通过校验的密钥，未启用鉴权时返回 nil
+func KeyFromContext(c *gin.Context) *auth.Key {
+	if v, ok := c.Get(keyContextKey); ok {
+		if key, ok := v.(*auth.Key); ok {
+			return key
+		}
+	}
+	return nil
+}
+
+// ExtractToken 按各插件的约定从请求中取出令牌，依次检查：
+// Authorization 头（支持 Bearer 和 DeepL-Auth-Key 前缀）、KEY 头、
+// Microsoft Translator 使用的 Ocp-Apim-Subscription-Key 头和 Subscription-Key 参数、key 参数、token 参数，
+// 以及 LibreTranslate 使用的 api_key 参数或请求体字段
+func ExtractToken(c *gin.Context) string {
+	if header := strings.TrimSpace(c.GetHeader("Authorization")); header != "" {
+		for _, prefix := range []string{"Bearer ", "DeepL-Auth-Key "} {
+			if len(header) >= len(prefix) && strings.EqualFold(header[:len(prefix)], prefix) {
+				return strings.TrimSpace(header[len(prefix):])
+			}
+		}
+		return header
+	}
+
+	if key := c.GetHeader("KEY"); key != "" {
+		return key
+	}
+	if key := c.GetHeader("Ocp-Apim-Subscription-Key"); key != "" {
+		return key
+	}
+	if key := c.Query("Subscription-Key"); key != "" {
+		return key
+	}
+	if key := c.Query("key"); key != "" {
+		return key
+	}
+	if token := c.Query("token"); token != "" {
+		return token
+	}
+	if key := c.Query("api_key"); key != "" {
+		return key
+	}
+	return bodyAPIKey(c)
+}
+
+// bodyAPIKey 从表单或 JSON 请求体中读取 api_key 字段，不影响处理器读取请求体
+func bodyAPIKey(c *gin.Context) string {
+	switch c.ContentType() {
+	case binding.MIMEPOSTForm:
+		values, err := url.ParseQuery(string(readBody(c)))
+		if err != nil {
+			return ""
+		}
+		return values.Get("api_key")
+	case binding.MIMEMultipartPOSTForm:
+		return c.PostForm("api_key")
+	case binding.MIMEJSON:
+		data := readBody(c)
+		if !bytes.Contains(data, []byte(`"api_key"`)) {
+			return ""
+		}
+		var body struct {
+			APIKey string `json:"api_key"`
+		}
+		if err := json.Unmarshal(data, &body); err != nil {
+			return ""
+		}
+		return body.APIKey
+	}
+	return ""
+}
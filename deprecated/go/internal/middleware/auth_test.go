@@ -1,12 +1,16 @@
 package middleware
 
 import (
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/auth"
 )
 
 func TestAuthWithValidToken(t *testing.T) {
@@ -78,3 +82,135 @@ func TestAuthWithNoToken(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 	assert.Contains(t, w.Body.String(), "Unauthorized")
 }
+
+func TestExtractToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		url     string
+		headers map[string]string
+		want    string
+	}{
+		{"Bearer", "/", map[string]string{"Authorization": "Bearer abc"}, "abc"},
+		{"DeepLAuthKey", "/", map[string]string{"Authorization": "DeepL-Auth-Key abc"}, "abc"},
+		{"RawAuthorization", "/", map[string]string{"Authorization": "abc"}, "abc"},
+		{"KeyHeader", "/", map[string]string{"KEY": "abc"}, "abc"},
+		{"SubscriptionKeyHeader", "/", map[string]string{"Ocp-Apim-Subscription-Key": "abc"}, "abc"},
+		{"SubscriptionKeyQuery", "/?Subscription-Key=abc", nil, "abc"},
+		{"KeyQuery", "/?key=abc", nil, "abc"},
+		{"TokenQuery", "/?token=abc", nil, "abc"},
+		{"HeaderBeforeQuery", "/?token=query", map[string]string{"Authorization": "Bearer header"}, "header"},
+		{"None", "/", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("GET", tt.url, nil)
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+			assert.Equal(t, tt.want, ExtractToken(c))
+		})
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store, err := auth.NewStore([]auth.Key{
+		{Name: "plugins", Token: "plugin-key", Scopes: []string{auth.ScopePlugins}},
+	})
+	require.NoError(t, err)
+	a := &auth.Authenticator{Keys: store}
+
+	r := gin.New()
+	r.GET("/plugin", RequireScope(a, auth.ScopePlugins), func(c *gin.Context) {
+		c.String(http.StatusOK, KeyFromContext(c).Name)
+	})
+	r.GET("/admin", RequireScope(a, auth.ScopeAdmin), func(c *gin.Context) {
+		c.String(http.StatusOK, "admin")
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{"AllowedScope", "/plugin", "plugin-key", http.StatusOK},
+		{"ForbiddenScope", "/admin", "plugin-key", http.StatusForbidden},
+		{"UnknownKey", "/plugin", "other", http.StatusUnauthorized},
+		{"NoKey", "/plugin", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("KEY", tt.token)
+			}
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestRequireScopeWithoutTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	a := &auth.Authenticator{
+		APIToken:   func() string { return "" },
+		AdminToken: func() string { return "" },
+		Keys:       &auth.Store{},
+	}
+
+	r := gin.New()
+	r.GET("/translate", RequireScope(a, auth.ScopeTranslate), func(c *gin.Context) {
+		c.String(http.StatusOK, "translate")
+	})
+	r.GET("/admin/engines", RequireScope(a, auth.ScopeAdmin), func(c *gin.Context) {
+		c.String(http.StatusOK, "admin")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/translate", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "translate stays open without tokens")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin/engines", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code, "admin is disabled without tokens")
+	assert.Contains(t, w.Body.String(), "Admin API is disabled")
+}
+
+func TestExtractTokenFromBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"JSON", "application/json", `{"q": "hello", "api_key": "abc"}`},
+		{"Form", "application/x-www-form-urlencoded", "q=hello&api_key=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request, _ = http.NewRequest("POST", "/libre/translate", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", tt.contentType)
+
+			assert.Equal(t, "abc", ExtractToken(c))
+
+			body, err := io.ReadAll(c.Request.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, string(body), "body must be left intact for the handler")
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/xxnuo/MTranServer/internal/metrics"
+)
+
+func TestMetricsRecordsRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/items/:id", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	counter := metrics.HTTPRequestsTotal.WithLabelValues("GET", "/items/:id", "200")
+	before := testutil.ToFloat64(counter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/items/42", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestMetricsUnmatchedRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(Metrics())
+
+	counter := metrics.HTTPRequestsTotal.WithLabelValues("GET", "unmatched", "404")
+	before := testutil.ToFloat64(counter)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/does-not-exist", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
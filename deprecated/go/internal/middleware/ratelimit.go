@@ -0,0 +1,208 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/metrics"
+	"github.com/xxnuo/MTranServer/internal/ratelimit"
+)
+
+// StatusDeeplQuotaExceeded DeepL API 在字符配额用尽时返回的状态码
+const StatusDeeplQuotaExceeded = 456
+
+// textFields 统计字符数时计入的请求字段（不区分大小写），覆盖各插件接口的文本字段
+var textFields = map[string]bool{
+	"text":      true,
+	"texts":     true,
+	"text_list": true,
+	"q":         true,
+	"content":   true,
+}
+
+const allowContextKey = "mtranserver.allow"
+
+// AllowFunc 逐条计入限流和配额，chars 为本次翻译的字符数
+type AllowFunc func(chars int) ratelimit.Decision
+
+// RateLimit 按 API 密钥（未鉴权时按客户端 IP）限制请求速率和字符数，需放在 RequireScope 之后。
+// 超出速率限制时返回 429，超出每日/每月字符配额时返回 quotaStatus，均带 Retry-After 头。
+// 同一连接上的后续消息（如 WebSocket）可通过 AllowFromContext 逐条计入
+func RateLimit(l *ratelimit.Limiter, defaults func() ratelimit.Limits, quotaStatus int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, override := limitClient(c)
+		limitsFor := func() ratelimit.Limits {
+			return defaults().Override(override)
+		}
+
+		c.Set(allowContextKey, AllowFunc(func(chars int) ratelimit.Decision {
+			limits := limitsFor()
+			if !limits.Enabled() {
+				return ratelimit.Decision{Allowed: true}
+			}
+			decision := l.Allow(client, limits, chars)
+			recordRejection(decision)
+			return decision
+		}))
+
+		limits := limitsFor()
+		if !limits.Enabled() {
+			c.Next()
+			return
+		}
+
+		chars := 0
+		if limits.CountsCharacters() {
+			chars = requestCharacters(c)
+		}
+
+		decision := l.Allow(client, limits, chars)
+		if decision.Allowed {
+			c.Next()
+			return
+		}
+
+		recordRejection(decision)
+		c.Header("Retry-After", strconv.Itoa(RetryAfterSeconds(decision)))
+
+		if decision.QuotaExceeded {
+			logger.Warn("Character quota exceeded for %s on %s", client, c.Request.URL.Path)
+			c.JSON(quotaStatus, gin.H{
+				"error": "Quota exceeded",
+			})
+		} else {
+			logger.Debug("Rate limit exceeded for %s on %s", client, c.Request.URL.Path)
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error": "Too many requests",
+			})
+		}
+		c.Abort()
+	}
+}
+
+// AllowFromContext 返回当前连接的限流函数，未启用 RateLimit 时总是放行
+func AllowFromContext(c *gin.Context) AllowFunc {
+	if v, ok := c.Get(allowContextKey); ok {
+		if allow, ok := v.(AllowFunc); ok {
+			return allow
+		}
+	}
+	return func(int) ratelimit.Decision {
+		return ratelimit.Decision{Allowed: true}
+	}
+}
+
+// RetryAfterSeconds 返回建议的重试等待秒数，至少为 1
+func RetryAfterSeconds(decision ratelimit.Decision) int {
+	retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	return retryAfter
+}
+
+// limitClient 返回限流使用的客户端标识以及密钥自身的限制
+func limitClient(c *gin.Context) (string, ratelimit.Limits) {
+	key := KeyFromContext(c)
+	if key == nil {
+		return "ip:" + c.ClientIP(), ratelimit.Limits{}
+	}
+	return "key:" + key.Name, ratelimit.Limits{
+		RequestsPerSecond:   key.RequestsPerSecond,
+		CharactersPerMinute: key.CharactersPerMinute,
+		DailyCharacters:     key.DailyCharacters,
+		MonthlyCharacters:   key.MonthlyCharacters,
+	}
+}
+
+func recordRejection(decision ratelimit.Decision) {
+	switch {
+	case decision.Allowed:
+	case decision.QuotaExceeded:
+		metrics.RateLimitedTotal.WithLabelValues("quota").Inc()
+	default:
+		metrics.RateLimitedTotal.WithLabelValues("rate").Inc()
+	}
+}
+
+// requestCharacters 统计请求中待翻译文本的字符数，读取后恢复请求体供处理器使用
+func requestCharacters(c *gin.Context) int {
+	chars := countValues(c.Request.URL.Query())
+
+	data := readBody(c)
+	if len(data) == 0 {
+		return chars
+	}
+
+	if c.ContentType() == binding.MIMEPOSTForm {
+		if values, err := url.ParseQuery(string(data)); err == nil {
+			chars += countValues(values)
+		}
+		return chars
+	}
+
+	var body interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return chars
+	}
+	return chars + countText(body, false)
+}
+
+func countValues(values url.Values) int {
+	chars := 0
+	for name, vs := range values {
+		if textFields[strings.ToLower(name)] {
+			for _, v := range vs {
+				chars += utf8.RuneCountInString(v)
+			}
+		}
+	}
+	return chars
+}
+
+// readBody 读取请求体并恢复，后续处理器仍可正常读取
+func readBody(c *gin.Context) []byte {
+	if c.Request.Body == nil {
+		return nil
+	}
+
+	data, err := io.ReadAll(c.Request.Body)
+	c.Request.Body.Close()
+	c.Request.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+func countText(v interface{}, inTextField bool) int {
+	switch v := v.(type) {
+	case string:
+		if inTextField {
+			return utf8.RuneCountInString(v)
+		}
+	case []interface{}:
+		n := 0
+		for _, item := range v {
+			n += countText(item, inTextField)
+		}
+		return n
+	case map[string]interface{}:
+		n := 0
+		for key, item := range v {
+			n += countText(item, textFields[strings.ToLower(key)])
+		}
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/auth"
+	"github.com/xxnuo/MTranServer/internal/ratelimit"
+)
+
+func TestRateLimitRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limits := func() ratelimit.Limits { return ratelimit.Limits{RequestsPerSecond: 1} }
+
+	r := gin.New()
+	r.GET("/test", RateLimit(ratelimit.NewLimiter(), limits, http.StatusTooManyRequests), func(c *gin.Context) {
+		c.String(http.StatusOK, "success")
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test", nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimitQuotaPerKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store, err := auth.NewStore([]auth.Key{
+		{Name: "small", Token: "small-key", DailyCharacters: 10},
+		{Name: "default", Token: "default-key"},
+	})
+	require.NoError(t, err)
+	a := &auth.Authenticator{Keys: store}
+	limits := func() ratelimit.Limits { return ratelimit.Limits{DailyCharacters: 1000} }
+
+	var received string
+	r := gin.New()
+	r.POST("/deepl", RequireScope(a, auth.ScopePlugins), RateLimit(ratelimit.NewLimiter(), limits, StatusDeeplQuotaExceeded), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = string(body)
+		c.String(http.StatusOK, "success")
+	})
+
+	send := func(token, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/deepl", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "DeepL-Auth-Key "+token)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"text": ["你好世界", "hello"], "target_lang": "EN"}`
+	w := send("small-key", body)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, received, "body must still be readable by the handler")
+
+	w = send("small-key", `{"text": ["hello"], "target_lang": "EN"}`)
+	assert.Equal(t, StatusDeeplQuotaExceeded, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "Quota exceeded")
+
+	w = send("default-key", `{"text": ["hello"], "target_lang": "EN"}`)
+	assert.Equal(t, http.StatusOK, w.Code, "other keys have their own quota")
+}
+
+func TestCountText(t *testing.T) {
+	tests := []struct {
+		name string
+		body interface{}
+		want int
+	}{
+		{"Text", map[string]interface{}{"text": "héllo", "from": "en"}, 5},
+		{"TextList", map[string]interface{}{"text_list": []interface{}{"ab", "cd"}}, 4},
+		{"Nested", []interface{}{map[string]interface{}{"Text": "abc"}}, 3},
+		{"Messages", map[string]interface{}{"model": "x", "messages": []interface{}{map[string]interface{}{"role": "user", "content": "abcd"}}}, 4},
+		{"Ignored", map[string]interface{}{"target_lang": "DE"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, countText(tt.body, false))
+		})
+	}
+}
+
+func TestAllowFromContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limits := func() ratelimit.Limits { return ratelimit.Limits{DailyCharacters: 10} }
+
+	var allow AllowFunc
+	r := gin.New()
+	r.GET("/ws", RateLimit(ratelimit.NewLimiter(), limits, http.StatusTooManyRequests), func(c *gin.Context) {
+		allow = AllowFromContext(c)
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/ws", nil)
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, allow)
+
+	assert.True(t, allow(6).Allowed)
+	decision := allow(6)
+	assert.False(t, decision.Allowed)
+	assert.True(t, decision.QuotaExceeded)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	assert.True(t, AllowFromContext(c)(1000).Allowed)
+}
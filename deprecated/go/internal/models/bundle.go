@@ -0,0 +1,471 @@
+package models
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+const (
+	bundleManifestName = "manifest.json"
+	bundleRecordsName  = RecordsFileName
+	bundleVersion      = 1
+)
+
+type BundlePair struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type BundleFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+type BundleManifest struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Pairs     []BundlePair `json:"pairs"`
+	Files     []BundleFile `json:"files"`
+}
+
+// ParseLanguagePair splits a pair such as "en-zh-Hans" into its source and
+// target languages using the known pairs in GlobalRecords.
+func ParseLanguagePair(pair string) (string, string, error) {
+	if GlobalRecords == nil {
+		if err := InitRecords(); err != nil {
+			return "", "", err
+		}
+	}
+
+	for i := 0; i < len(pair); i++ {
+		if pair[i] != '-' {
+			continue
+		}
+		fromLang, toLang := pair[:i], pair[i+1:]
+		if GlobalRecords.HasLanguagePair(fromLang, toLang) {
+			return fromLang, toLang, nil
+		}
+	}
+	return "", "", fmt.Errorf("unknown language pair: %s", pair)
+}
+
+func bundlePairDir(pair BundlePair) string {
+	return fmt.Sprintf("%s_%s", pair.From, pair.To)
+}
+
+// ExportBundle writes the latest model files of the given pairs to a
+// zstd-compressed tar archive together with their records and hashes. Every
+// file is verified against DecompressedHash before it is packed.
+func ExportBundle(dst string, pairs []BundlePair) error {
+	if len(pairs) == 0 {
+		return fmt.Errorf("no language pairs to export")
+	}
+
+	cfg := config.GetConfig()
+
+	manifest := BundleManifest{
+		Version:   bundleVersion,
+		CreatedAt: time.Now().UTC(),
+		Pairs:     pairs,
+	}
+	var records RecordsData
+	var sources []string
+
+	for _, pair := range pairs {
+		latest, err := LatestRecords(pair.From, pair.To)
+		if err != nil {
+			return err
+		}
+		sort.Slice(latest, func(i, j int) bool {
+			return latest[i].FileType < latest[j].FileType
+		})
+
+		for _, record := range latest {
+			name := decompressedName(record)
+			src := filepath.Join(PairDir(cfg.ModelDir, pair.From, pair.To), name)
+
+			info, err := os.Stat(src)
+			if err != nil {
+				return fmt.Errorf("model file %s for %s -> %s is not available, download it first: %w", name, pair.From, pair.To, err)
+			}
+
+			hash, err := utils.ComputeSHA256(src)
+			if err != nil {
+				return err
+			}
+			if record.DecompressedHash != "" && hash != record.DecompressedHash {
+				return fmt.Errorf("model file %s for %s -> %s is corrupted (hash mismatch)", name, pair.From, pair.To)
+			}
+
+			records.Data = append(records.Data, record)
+			manifest.Files = append(manifest.Files, BundleFile{
+				Path:   path.Join(bundlePairDir(pair), name),
+				SHA256: hash,
+				Size:   info.Size(),
+			})
+			sources = append(sources, src)
+		}
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+
+	if err := writeBundle(out, &manifest, &records, sources); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	logger.Info("Exported %d model file(s) for %d language pair(s) to %s", len(sources), len(pairs), dst)
+	return nil
+}
+
+func writeBundle(w io.Writer, manifest *BundleManifest, records *RecordsData, sources []string) error {
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return fmt.Errorf("failed to create zstd writer: %w", err)
+	}
+	tw := tar.NewWriter(zw)
+
+	for _, entry := range []struct {
+		name string
+		v    interface{}
+	}{
+		{bundleManifestName, manifest},
+		{bundleRecordsName, records},
+	} {
+		data, err := json.MarshalIndent(entry.v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{
+			Name:    entry.name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: manifest.CreatedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+
+	for i, file := range manifest.Files {
+		if err := writeBundleFile(tw, file, sources[i], manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+func writeBundleFile(tw *tar.Writer, file BundleFile, src string, modTime time.Time) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    file.Path,
+		Mode:    0644,
+		Size:    file.Size,
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if _, err := io.CopyN(tw, f, file.Size); err != nil {
+		return fmt.Errorf("failed to write %s to bundle: %w", file.Path, err)
+	}
+	return nil
+}
+
+// ImportBundle extracts a bundle created by ExportBundle into a staging
+// directory, verifies every model file against the manifest and the
+// DecompressedHash of the matching local record, and then installs the pairs
+// into ModelDir. Nothing is replaced unless every pair is known locally and
+// all of its files are present and verified.
+func ImportBundle(src string) ([]BundlePair, error) {
+	cfg := config.GetConfig()
+
+	if err := os.MkdirAll(cfg.ModelDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create model directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(cfg.ModelDir, ".import-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	manifest, records, err := extractBundle(src, staging)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkBundlePairs(cfg.ModelDir, manifest.Pairs); err != nil {
+		return nil, err
+	}
+
+	local, err := localBundleRecords(manifest.Pairs, records)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyBundle(staging, manifest, local); err != nil {
+		return nil, err
+	}
+
+	for _, pair := range manifest.Pairs {
+		dst := PairDir(cfg.ModelDir, pair.From, pair.To)
+		if err := os.RemoveAll(dst); err != nil {
+			return nil, fmt.Errorf("failed to replace %s: %w", dst, err)
+		}
+		if err := os.Rename(filepath.Join(staging, bundlePairDir(pair)), dst); err != nil {
+			return nil, fmt.Errorf("failed to install %s -> %s: %w", pair.From, pair.To, err)
+		}
+		logger.Info("Installed model %s -> %s", pair.From, pair.To)
+	}
+
+	return manifest.Pairs, nil
+}
+
+// checkBundlePairs rejects unknown or duplicate pairs and any pair whose
+// install directory would not be a direct child of modelDir.
+func checkBundlePairs(modelDir string, pairs []BundlePair) error {
+	if len(pairs) == 0 {
+		return fmt.Errorf("bundle contains no language pairs")
+	}
+
+	seen := make(map[BundlePair]bool, len(pairs))
+	for _, pair := range pairs {
+		if err := ValidateLanguagePair(pair.From, pair.To); err != nil {
+			return fmt.Errorf("invalid pair in bundle: %w", err)
+		}
+		if filepath.Dir(PairDir(modelDir, pair.From, pair.To)) != filepath.Clean(modelDir) {
+			return fmt.Errorf("invalid pair in bundle: %s -> %s", pair.From, pair.To)
+		}
+		if seen[pair] {
+			return fmt.Errorf("duplicate pair in bundle: %s -> %s", pair.From, pair.To)
+		}
+		seen[pair] = true
+	}
+	return nil
+}
+
+// localBundleRecords maps the bundle path of every file to the matching record
+// in GlobalRecords. The bundle's own records only select which local record a
+// file belongs to, so hashes always come from the local records. Every file
+// type known locally for a pair must be present in the bundle.
+func localBundleRecords(pairs []BundlePair, records *RecordsData) (map[string]RecordItem, error) {
+	listed := make(map[BundlePair]bool, len(pairs))
+	for _, pair := range pairs {
+		listed[pair] = true
+	}
+
+	local := make(map[string]RecordItem)
+	covered := make(map[BundlePair]map[string]bool)
+	for _, record := range records.Data {
+		pair := BundlePair{From: record.SourceLanguage, To: record.TargetLanguage}
+		if !listed[pair] {
+			return nil, fmt.Errorf("bundle record %s is for unlisted pair %s -> %s", record.Attachment.Filename, pair.From, pair.To)
+		}
+
+		match, ok := findLocalRecord(record)
+		if !ok {
+			return nil, fmt.Errorf("bundle record %s (version %s) for %s -> %s is not in the local records, update records.json first",
+				record.Attachment.Filename, record.Version, pair.From, pair.To)
+		}
+
+		local[path.Join(bundlePairDir(pair), decompressedName(match))] = match
+		if covered[pair] == nil {
+			covered[pair] = make(map[string]bool)
+		}
+		covered[pair][match.FileType] = true
+	}
+
+	for _, record := range GlobalRecords.Data {
+		pair := BundlePair{From: record.SourceLanguage, To: record.TargetLanguage}
+		if listed[pair] && !covered[pair][record.FileType] {
+			return nil, fmt.Errorf("bundle is missing the %s file for %s -> %s", record.FileType, pair.From, pair.To)
+		}
+	}
+
+	return local, nil
+}
+
+func findLocalRecord(record RecordItem) (RecordItem, bool) {
+	for _, r := range GlobalRecords.Data {
+		if r.SourceLanguage == record.SourceLanguage && r.TargetLanguage == record.TargetLanguage &&
+			r.FileType == record.FileType && r.Version == record.Version &&
+			r.Attachment.Filename == record.Attachment.Filename {
+			return r, true
+		}
+	}
+	return RecordItem{}, false
+}
+
+func extractBundle(src, staging string) (*BundleManifest, *RecordsData, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	zr, err := zstd.NewReader(f)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create zstd reader: %w", err)
+	}
+	defer zr.Close()
+
+	var manifest *BundleManifest
+	var records *RecordsData
+
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		switch hdr.Name {
+		case bundleManifestName:
+			manifest = &BundleManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, nil, fmt.Errorf("invalid bundle manifest: %w", err)
+			}
+			continue
+		case bundleRecordsName:
+			records = &RecordsData{}
+			if err := json.NewDecoder(tr).Decode(records); err != nil {
+				return nil, nil, fmt.Errorf("invalid bundle records: %w", err)
+			}
+			continue
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, nil, fmt.Errorf("unexpected entry in bundle: %s", hdr.Name)
+		}
+
+		dir, name := path.Split(hdr.Name)
+		dir = strings.TrimSuffix(dir, "/")
+		if dir == "" || strings.Contains(dir, "/") || dir == ".." || name == ".." || path.Clean(hdr.Name) != hdr.Name {
+			return nil, nil, fmt.Errorf("invalid path in bundle: %s", hdr.Name)
+		}
+
+		if err := os.MkdirAll(filepath.Join(staging, dir), 0755); err != nil {
+			return nil, nil, err
+		}
+		out, err := os.Create(filepath.Join(staging, dir, name))
+		if err != nil {
+			return nil, nil, err
+		}
+		_, err = io.Copy(out, tr)
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to extract %s: %w", hdr.Name, err)
+		}
+	}
+
+	if manifest == nil || records == nil {
+		return nil, nil, fmt.Errorf("bundle is missing %s or %s", bundleManifestName, bundleRecordsName)
+	}
+	if manifest.Version != bundleVersion {
+		return nil, nil, fmt.Errorf("unsupported bundle version: %d", manifest.Version)
+	}
+
+	return manifest, records, nil
+}
+
+// verifyBundle checks that the staging directory holds exactly the files in
+// the manifest, that every file belongs to a local record and that its hash
+// matches both the manifest and the record.
+func verifyBundle(staging string, manifest *BundleManifest, records map[string]RecordItem) error {
+	expected := make(map[string]BundleFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		if _, ok := records[file.Path]; !ok {
+			return fmt.Errorf("no record found for %s", file.Path)
+		}
+		expected[file.Path] = file
+	}
+	for p := range records {
+		if _, ok := expected[p]; !ok {
+			return fmt.Errorf("bundle is missing %s", p)
+		}
+	}
+
+	extracted := 0
+	err := filepath.WalkDir(staging, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(staging, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		file, ok := expected[rel]
+		if !ok {
+			return fmt.Errorf("unexpected file in bundle: %s", rel)
+		}
+
+		hash, err := utils.ComputeSHA256(p)
+		if err != nil {
+			return err
+		}
+		decompressedHash := records[rel].DecompressedHash
+		if hash != file.SHA256 || (decompressedHash != "" && hash != decompressedHash) {
+			return fmt.Errorf("hash mismatch for %s", rel)
+		}
+
+		extracted++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if extracted != len(manifest.Files) {
+		return fmt.Errorf("bundle is incomplete: expected %d files, found %d", len(manifest.Files), extracted)
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package models_test
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func TestParseLanguagePair(t *testing.T) {
+	setupFakeModels(t)
+	models.GlobalRecords.Data = append(models.GlobalRecords.Data,
+		models.RecordItem{SourceLanguage: "en", TargetLanguage: "zh-Hans", FileType: "model", Version: "1.0"})
+
+	from, to, err := models.ParseLanguagePair("en-zh-Hans")
+	if err != nil {
+		t.Fatalf("ParseLanguagePair() error = %v", err)
+	}
+	if from != "en" || to != "zh-Hans" {
+		t.Errorf("ParseLanguagePair() = %s, %s, want en, zh-Hans", from, to)
+	}
+
+	if _, _, err := models.ParseLanguagePair("xx-yy"); err == nil {
+		t.Error("expected error for unknown pair")
+	}
+}
+
+func TestExportImportBundle(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	writeModelFile(t, modelDir, "vocab.enja.spm", "vocab")
+	writeModelFile(t, modelDir, "model.enja.v2.bin", "model-v2")
+
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.zst")
+	pairs := []models.BundlePair{{From: "en", To: "ja"}}
+	if err := models.ExportBundle(bundle, pairs); err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+
+	if err := os.RemoveAll(models.PairDir(modelDir, "en", "ja")); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := models.ImportBundle(bundle)
+	if err != nil {
+		t.Fatalf("ImportBundle() error = %v", err)
+	}
+	if len(imported) != 1 || imported[0] != pairs[0] {
+		t.Errorf("ImportBundle() = %v, want %v", imported, pairs)
+	}
+
+	data, err := os.ReadFile(filepath.Join(models.PairDir(modelDir, "en", "ja"), "model.enja.v2.bin"))
+	if err != nil {
+		t.Fatalf("model file not installed: %v", err)
+	}
+	if string(data) != "model-v2" {
+		t.Errorf("model file content = %q", data)
+	}
+
+	entries, _ := os.ReadDir(modelDir)
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".import-") {
+			t.Errorf("staging directory %s was not removed", entry.Name())
+		}
+	}
+}
+
+func TestExportBundleRejectsCorruptedModel(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	writeModelFile(t, modelDir, "vocab.enja.spm", "vocab")
+	writeModelFile(t, modelDir, "model.enja.v2.bin", "corrupted")
+
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.zst")
+	if err := models.ExportBundle(bundle, []models.BundlePair{{From: "en", To: "ja"}}); err == nil {
+		t.Fatal("expected export of corrupted model to fail")
+	}
+	if _, err := os.Stat(bundle); !os.IsNotExist(err) {
+		t.Error("partial bundle was not removed")
+	}
+}
+
+// writeRawBundle writes a bundle with arbitrary contents, bypassing the checks
+// done by ExportBundle.
+func writeRawBundle(t *testing.T, manifest models.BundleManifest, records models.RecordsData, files map[string]string) string {
+	t.Helper()
+
+	bundle := filepath.Join(t.TempDir(), "bundle.tar.zst")
+	f, err := os.Create(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw, err := zstd.NewWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tw := tar.NewWriter(zw)
+
+	add := func(name string, data []byte) {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manifest.Version = 1
+	for name, content := range files {
+		hash := sha256.Sum256([]byte(content))
+		manifest.Files = append(manifest.Files, models.BundleFile{Path: name, SHA256: hex.EncodeToString(hash[:]), Size: int64(len(content))})
+	}
+	data, _ := json.Marshal(manifest)
+	add("manifest.json", data)
+	data, _ = json.Marshal(records)
+	add("records.json", data)
+	for name, content := range files {
+		add(name, []byte(content))
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bundle
+}
+
+func TestImportBundleRejectsInvalidPairs(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	victim := filepath.Join(filepath.Dir(modelDir), "victim")
+	if err := os.MkdirAll(victim, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pair := range []models.BundlePair{
+		{From: "..", To: "/../../victim"},
+		{From: "en", To: "../victim"},
+		{From: "xx", To: "yy"},
+	} {
+		bundle := writeRawBundle(t, models.BundleManifest{Pairs: []models.BundlePair{pair}}, models.RecordsData{}, nil)
+		if _, err := models.ImportBundle(bundle); err == nil {
+			t.Errorf("expected import of pair %v to fail", pair)
+		}
+	}
+
+	if _, err := os.Stat(victim); err != nil {
+		t.Errorf("directory outside ModelDir was removed: %v", err)
+	}
+}
+
+func TestImportBundleRequiresAllFiles(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	writeModelFile(t, modelDir, "vocab.enja.spm", "vocab")
+	writeModelFile(t, modelDir, "model.enja.v2.bin", "model-v2")
+	installed := filepath.Join(models.PairDir(modelDir, "en", "ja"), "model.enja.v2.bin")
+
+	pairs := []models.BundlePair{{From: "en", To: "ja"}}
+	vocab := models.GlobalRecords.Data[2]
+
+	// no files at all
+	bundle := writeRawBundle(t, models.BundleManifest{Pairs: pairs}, models.RecordsData{}, nil)
+	if _, err := models.ImportBundle(bundle); err == nil {
+		t.Error("expected import of a pair without files to fail")
+	}
+
+	// the model file is missing
+	bundle = writeRawBundle(t, models.BundleManifest{Pairs: pairs},
+		models.RecordsData{Data: []models.RecordItem{vocab}},
+		map[string]string{"en_ja/vocab.enja.spm": "vocab"})
+	if _, err := models.ImportBundle(bundle); err == nil {
+		t.Error("expected import of an incomplete pair to fail")
+	}
+
+	// the bundle's records claim a hash that differs from the local record
+	model := models.GlobalRecords.Data[1]
+	model.DecompressedHash = ""
+	bundle = writeRawBundle(t, models.BundleManifest{Pairs: pairs},
+		models.RecordsData{Data: []models.RecordItem{vocab, model}},
+		map[string]string{"en_ja/vocab.enja.spm": "vocab", "en_ja/model.enja.v2.bin": "tampered"})
+	if _, err := models.ImportBundle(bundle); err == nil {
+		t.Error("expected import with a file that does not match the local record to fail")
+	}
+
+	// a record that is not in the local records
+	unknown := model
+	unknown.Version = "3.0"
+	unknown.Attachment.Filename = "model.enja.v3.bin.zst"
+	bundle = writeRawBundle(t, models.BundleManifest{Pairs: pairs},
+		models.RecordsData{Data: []models.RecordItem{vocab, unknown}},
+		map[string]string{"en_ja/vocab.enja.spm": "vocab", "en_ja/model.enja.v3.bin": "model-v3"})
+	if _, err := models.ImportBundle(bundle); err == nil {
+		t.Error("expected import of an unknown record to fail")
+	}
+
+	if data, err := os.ReadFile(installed); err != nil || string(data) != "model-v2" {
+		t.Errorf("installed model was modified by a rejected import: %q, %v", data, err)
+	}
+}
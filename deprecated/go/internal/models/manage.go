@@ -0,0 +1,241 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+const (
+	ModelStatusDownloaded = "downloaded"
+	ModelStatusMissing    = "missing"
+	ModelStatusOutdated   = "outdated"
+)
+
+const (
+	FileStatusOK       = "ok"
+	FileStatusMissing  = "missing"
+	FileStatusMismatch = "mismatch"
+	FileStatusUnknown  = "unknown"
+)
+
+var ErrModelNotFound = errors.New("model not found")
+
+type ModelStatus struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Version string `json:"version"`
+	Status  string `json:"status"`
+	Size    int64  `json:"size"`
+}
+
+type FileVerification struct {
+	File     string `json:"file"`
+	FileType string `json:"file_type"`
+	Status   string `json:"status"`
+}
+
+type VerifyResult struct {
+	From  string             `json:"from"`
+	To    string             `json:"to"`
+	Valid bool               `json:"valid"`
+	Files []FileVerification `json:"files"`
+}
+
+func PairDir(modelDir, fromLang, toLang string) string {
+	return filepath.Join(modelDir, fmt.Sprintf("%s_%s", fromLang, toLang))
+}
+
+// LatestRecords returns the newest record of each file type for a pair.
+func LatestRecords(fromLang, toLang string) ([]RecordItem, error) {
+	return selectRecords(fromLang, toLang, "")
+}
+
+func decompressedName(record RecordItem) string {
+	return strings.TrimSuffix(record.Attachment.Filename, ".zst")
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// ListModels reports the local state of every pair in GlobalRecords. Only file
+// presence is checked; use VerifyModel to compare hashes.
+func ListModels() ([]ModelStatus, error) {
+	if GlobalRecords == nil {
+		if err := InitRecords(); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := config.GetConfig()
+
+	type pair struct{ from, to string }
+	seen := make(map[pair]bool)
+	var pairs []pair
+	for _, record := range GlobalRecords.Data {
+		p := pair{record.SourceLanguage, record.TargetLanguage}
+		if !seen[p] {
+			seen[p] = true
+			pairs = append(pairs, p)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].from != pairs[j].from {
+			return pairs[i].from < pairs[j].from
+		}
+		return pairs[i].to < pairs[j].to
+	})
+
+	result := make([]ModelStatus, 0, len(pairs))
+	for _, p := range pairs {
+		status, err := GetModelStatus(cfg.ModelDir, p.from, p.to)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *status)
+	}
+	return result, nil
+}
+
+func GetModelStatus(modelDir, fromLang, toLang string) (*ModelStatus, error) {
+	latest, err := LatestRecords(fromLang, toLang)
+	if err != nil {
+		return nil, err
+	}
+
+	langPairDir := PairDir(modelDir, fromLang, toLang)
+
+	status := &ModelStatus{
+		From:   fromLang,
+		To:     toLang,
+		Status: ModelStatusDownloaded,
+	}
+
+	versions := make([]string, 0, len(latest))
+	for _, record := range latest {
+		versions = append(versions, record.Version)
+		if fileExists(filepath.Join(langPairDir, decompressedName(record))) {
+			continue
+		}
+
+		if hasOlderFile(langPairDir, fromLang, toLang, record) {
+			if status.Status == ModelStatusDownloaded {
+				status.Status = ModelStatusOutdated
+			}
+			continue
+		}
+		status.Status = ModelStatusMissing
+	}
+	status.Version = utils.GetLargestVersion(versions)
+
+	status.Size = dirSize(langPairDir)
+	return status, nil
+}
+
+func hasOlderFile(langPairDir, fromLang, toLang string, latest RecordItem) bool {
+	for _, record := range GlobalRecords.Data {
+		if record.SourceLanguage != fromLang || record.TargetLanguage != toLang ||
+			record.FileType != latest.FileType || record.Version == latest.Version {
+			continue
+		}
+		if fileExists(filepath.Join(langPairDir, decompressedName(record))) {
+			return true
+		}
+	}
+	return false
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// DeleteModel removes the local directory of a pair.
+func DeleteModel(fromLang, toLang string) error {
+	cfg := config.GetConfig()
+	langPairDir := PairDir(cfg.ModelDir, fromLang, toLang)
+
+	if _, err := os.Stat(langPairDir); os.IsNotExist(err) {
+		return ErrModelNotFound
+	}
+
+	if err := os.RemoveAll(langPairDir); err != nil {
+		return fmt.Errorf("Failed to delete model directory: %w", err)
+	}
+	return nil
+}
+
+// VerifyModel recomputes the hash of every decompressed file of a pair and
+// compares it with DecompressedHash.
+func VerifyModel(fromLang, toLang string) (*VerifyResult, error) {
+	latest, err := LatestRecords(fromLang, toLang)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(latest, func(i, j int) bool {
+		return latest[i].FileType < latest[j].FileType
+	})
+
+	cfg := config.GetConfig()
+	langPairDir := PairDir(cfg.ModelDir, fromLang, toLang)
+
+	result := &VerifyResult{
+		From:  fromLang,
+		To:    toLang,
+		Valid: true,
+		Files: make([]FileVerification, 0, len(latest)),
+	}
+
+	for _, record := range latest {
+		name := decompressedName(record)
+		file := FileVerification{
+			File:     name,
+			FileType: record.FileType,
+			Status:   FileStatusOK,
+		}
+
+		path := filepath.Join(langPairDir, name)
+		switch {
+		case !fileExists(path):
+			file.Status = FileStatusMissing
+		case record.DecompressedHash == "":
+			file.Status = FileStatusUnknown
+		default:
+			hash, err := computeFileHash(path)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to compute hash for %s: %w", name, err)
+			}
+			if hash != record.DecompressedHash {
+				file.Status = FileStatusMismatch
+			}
+		}
+
+		if file.Status == FileStatusMissing || file.Status == FileStatusMismatch {
+			result.Valid = false
+		}
+		result.Files = append(result.Files, file)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package models_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func setupFakeModels(t *testing.T) string {
+	t.Helper()
+
+	oldConfig := config.GlobalConfig
+	oldRecords := models.GlobalRecords
+	t.Cleanup(func() {
+		config.GlobalConfig = oldConfig
+		models.GlobalRecords = oldRecords
+	})
+
+	tmpDir := t.TempDir()
+	config.GlobalConfig = &config.Config{
+		ConfigDir: tmpDir,
+		ModelDir:  filepath.Join(tmpDir, "models"),
+	}
+
+	modelHash := sha256.Sum256([]byte("model-v2"))
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "ja", FileType: "model", Version: "1.0",
+				Attachment: models.Attachment{Filename: "model.enja.v1.bin.zst"}},
+			{SourceLanguage: "en", TargetLanguage: "ja", FileType: "model", Version: "2.0",
+				Attachment:       models.Attachment{Filename: "model.enja.v2.bin.zst"},
+				DecompressedHash: hex.EncodeToString(modelHash[:])},
+			{SourceLanguage: "en", TargetLanguage: "ja", FileType: "vocab", Version: "2.0",
+				Attachment: models.Attachment{Filename: "vocab.enja.spm.zst"}},
+		},
+	}
+
+	return config.GlobalConfig.ModelDir
+}
+
+func writeModelFile(t *testing.T, modelDir, name, content string) {
+	t.Helper()
+
+	dir := models.PairDir(modelDir, "en", "ja")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetModelStatus(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	status, err := models.GetModelStatus(modelDir, "en", "ja")
+	if err != nil {
+		t.Fatalf("GetModelStatus() error = %v", err)
+	}
+	if status.Status != models.ModelStatusMissing {
+		t.Errorf("Status = %s, want %s", status.Status, models.ModelStatusMissing)
+	}
+	if status.Version != "2.0" {
+		t.Errorf("Version = %s, want 2.0", status.Version)
+	}
+
+	writeModelFile(t, modelDir, "vocab.enja.spm", "vocab")
+	writeModelFile(t, modelDir, "model.enja.v1.bin", "model-v1")
+
+	status, _ = models.GetModelStatus(modelDir, "en", "ja")
+	if status.Status != models.ModelStatusOutdated {
+		t.Errorf("Status = %s, want %s", status.Status, models.ModelStatusOutdated)
+	}
+
+	writeModelFile(t, modelDir, "model.enja.v2.bin", "model-v2")
+
+	status, _ = models.GetModelStatus(modelDir, "en", "ja")
+	if status.Status != models.ModelStatusDownloaded {
+		t.Errorf("Status = %s, want %s", status.Status, models.ModelStatusDownloaded)
+	}
+	if want := int64(len("vocab") + len("model-v1") + len("model-v2")); status.Size != want {
+		t.Errorf("Size = %d, want %d", status.Size, want)
+	}
+}
+
+func TestVerifyModel(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	writeModelFile(t, modelDir, "vocab.enja.spm", "vocab")
+	writeModelFile(t, modelDir, "model.enja.v2.bin", "model-v2")
+
+	result, err := models.VerifyModel("en", "ja")
+	if err != nil {
+		t.Fatalf("VerifyModel() error = %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("expected valid model, got %+v", result.Files)
+	}
+
+	writeModelFile(t, modelDir, "model.enja.v2.bin", "corrupted")
+
+	result, _ = models.VerifyModel("en", "ja")
+	if result.Valid {
+		t.Error("expected hash mismatch to invalidate model")
+	}
+	for _, file := range result.Files {
+		if file.FileType == "model" && file.Status != models.FileStatusMismatch {
+			t.Errorf("model file status = %s, want %s", file.Status, models.FileStatusMismatch)
+		}
+	}
+}
+
+func TestDeleteModel(t *testing.T) {
+	modelDir := setupFakeModels(t)
+
+	if err := models.DeleteModel("en", "ja"); !errors.Is(err, models.ErrModelNotFound) {
+		t.Errorf("DeleteModel() error = %v, want ErrModelNotFound", err)
+	}
+
+	writeModelFile(t, modelDir, "vocab.enja.spm", "vocab")
+
+	if err := models.DeleteModel("en", "ja"); err != nil {
+		t.Fatalf("DeleteModel() error = %v", err)
+	}
+	if _, err := os.Stat(models.PairDir(modelDir, "en", "ja")); !os.IsNotExist(err) {
+		t.Error("model directory was not removed")
+	}
+}
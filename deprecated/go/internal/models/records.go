@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	getter "github.com/hashicorp/go-getter"
 	"github.com/xxnuo/MTranServer/data"
 	"github.com/xxnuo/MTranServer/internal/config"
 	"github.com/xxnuo/MTranServer/internal/downloader"
@@ -207,10 +208,15 @@ func computeFileHash(filePath string) (string, error) {
 }
 
 func DownloadModel(toLang string, fromLang string, version string) error {
+	return DownloadModelWithProgress(toLang, fromLang, version, nil)
+}
 
+// selectRecords returns the records to download for a pair. When version is
+// empty the latest version of each file type is selected.
+func selectRecords(fromLang, toLang, version string) ([]RecordItem, error) {
 	if GlobalRecords == nil {
 		if err := InitRecords(); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
@@ -224,28 +230,38 @@ func DownloadModel(toLang string, fromLang string, version string) error {
 	}
 
 	if len(matchedRecords) == 0 {
-		return fmt.Errorf("No model found for %s -> %s (version: %s)", fromLang, toLang, version)
+		return nil, fmt.Errorf("No model found for %s -> %s (version: %s)", fromLang, toLang, version)
 	}
 
-	targetRecords := matchedRecords
-	if version == "" {
+	if version != "" {
+		return matchedRecords, nil
+	}
 
-		fileTypeMap := make(map[string][]RecordItem)
-		for _, record := range matchedRecords {
-			fileTypeMap[record.FileType] = append(fileTypeMap[record.FileType], record)
-		}
+	fileTypeMap := make(map[string][]RecordItem)
+	for _, record := range matchedRecords {
+		fileTypeMap[record.FileType] = append(fileTypeMap[record.FileType], record)
+	}
 
-		targetRecords = []RecordItem{}
-		for _, records := range fileTypeMap {
-			versions := make([]string, len(records))
-			recordMap := make(map[string]RecordItem)
-			for i, r := range records {
-				versions[i] = r.Version
-				recordMap[r.Version] = r
-			}
-			latestVersion := utils.GetLargestVersion(versions)
-			targetRecords = append(targetRecords, recordMap[latestVersion])
+	targetRecords := []RecordItem{}
+	for _, records := range fileTypeMap {
+		versions := make([]string, len(records))
+		recordMap := make(map[string]RecordItem)
+		for i, r := range records {
+			versions[i] = r.Version
+			recordMap[r.Version] = r
 		}
+		latestVersion := utils.GetLargestVersion(versions)
+		targetRecords = append(targetRecords, recordMap[latestVersion])
+	}
+	return targetRecords, nil
+}
+
+// DownloadModelWithProgress downloads a model like DownloadModel and reports
+// transfer progress of each file to progress when it is not nil.
+func DownloadModelWithProgress(toLang string, fromLang string, version string, progress getter.ProgressTracker) error {
+	targetRecords, err := selectRecords(fromLang, toLang, version)
+	if err != nil {
+		return err
 	}
 
 	cfg := config.GetConfig()
@@ -261,6 +277,9 @@ func DownloadModel(toLang string, fromLang string, version string) error {
 	logger.Info("Downloading model files for %s -> %s", fromLang, toLang)
 
 	d := downloader.New(langPairDir)
+	if progress != nil {
+		d.SetProgressFunc(progress)
+	}
 
 	for _, record := range targetRecords {
 		filename := record.Attachment.Filename
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"math"
+	"time"
+)
+
+// bucket 令牌桶，rate 为每秒补充的令牌数，capacity 为桶容量
+type bucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(rate, capacity float64, now time.Time) *bucket {
+	return &bucket{
+		rate:     rate,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     now,
+	}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.capacity, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+// wait 返回取出 n 个令牌前需要等待的时间，0 表示可以立即取出。
+// n 超过桶容量时只要求桶满，超出部分在 take 时记为欠额，由后续请求等待补足
+func (b *bucket) wait(n float64, now time.Time) time.Duration {
+	b.refill(now)
+
+	need := math.Min(n, b.capacity)
+	if b.tokens >= need {
+		return 0
+	}
+	return time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+}
+
+func (b *bucket) take(n float64) {
+	b.tokens -= n
+}
@@ -0,0 +1,154 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// idleTimeout 客户端超过该时间没有请求时丢弃其令牌桶
+const idleTimeout = 10 * time.Minute
+
+// Limits 单个客户端的限制，0 表示不限制
+type Limits struct {
+	RequestsPerSecond   int
+	CharactersPerMinute int
+	DailyCharacters     int
+	MonthlyCharacters   int
+}
+
+// Override 用 o 中大于 0 的值覆盖 l
+func (l Limits) Override(o Limits) Limits {
+	if o.RequestsPerSecond > 0 {
+		l.RequestsPerSecond = o.RequestsPerSecond
+	}
+	if o.CharactersPerMinute > 0 {
+		l.CharactersPerMinute = o.CharactersPerMinute
+	}
+	if o.DailyCharacters > 0 {
+		l.DailyCharacters = o.DailyCharacters
+	}
+	if o.MonthlyCharacters > 0 {
+		l.MonthlyCharacters = o.MonthlyCharacters
+	}
+	return l
+}
+
+// Enabled 判断是否设置了任意限制
+func (l Limits) Enabled() bool {
+	return l.RequestsPerSecond > 0 || l.CharactersPerMinute > 0 || l.DailyCharacters > 0 || l.MonthlyCharacters > 0
+}
+
+// CountsCharacters 判断是否需要统计请求的字符数
+func (l Limits) CountsCharacters() bool {
+	return l.CharactersPerMinute > 0 || l.DailyCharacters > 0 || l.MonthlyCharacters > 0
+}
+
+// Decision 限流结果
+type Decision struct {
+	Allowed       bool
+	RetryAfter    time.Duration
+	QuotaExceeded bool
+}
+
+type clientState struct {
+	limits   Limits
+	requests *bucket
+	chars    *bucket
+	lastSeen time.Time
+}
+
+// Limiter 按客户端（API 密钥或 IP）进行令牌桶限流和字符配额统计
+type Limiter struct {
+	mu        sync.Mutex
+	clients   map[string]*clientState
+	lastSweep time.Time
+
+	Quotas *QuotaStore
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		clients: make(map[string]*clientState),
+		Quotas:  NewQuotaStore(),
+	}
+}
+
+// Default 服务使用的全局限流器
+var Default = NewLimiter()
+
+// Allow 判断客户端是否可以发起一个包含 chars 个字符的请求，允许时扣除令牌并记录配额用量
+func (l *Limiter) Allow(client string, limits Limits, chars int) Decision {
+	return l.allow(client, limits, chars, time.Now().UTC())
+}
+
+func (l *Limiter) allow(client string, limits Limits, chars int, now time.Time) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	state := l.state(client, limits, now)
+	state.lastSeen = now
+
+	var retryAfter time.Duration
+	if state.requests != nil {
+		retryAfter = state.requests.wait(1, now)
+	}
+	if state.chars != nil && chars > 0 {
+		if wait := state.chars.wait(float64(chars), now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+	if retryAfter > 0 {
+		return Decision{RetryAfter: retryAfter}
+	}
+
+	if wait, exceeded := l.Quotas.check(client, chars, limits, now); exceeded {
+		return Decision{RetryAfter: wait, QuotaExceeded: true}
+	}
+
+	if state.requests != nil {
+		state.requests.take(1)
+	}
+	if state.chars != nil {
+		state.chars.take(float64(chars))
+	}
+	if limits.DailyCharacters > 0 || limits.MonthlyCharacters > 0 {
+		l.Quotas.add(client, chars, now)
+	}
+
+	return Decision{Allowed: true}
+}
+
+// state 返回客户端的令牌桶，限制变化（如配置重新加载）时重建
+func (l *Limiter) state(client string, limits Limits, now time.Time) *clientState {
+	state, ok := l.clients[client]
+	if ok && state.limits == limits {
+		return state
+	}
+
+	state = &clientState{limits: limits}
+	if limits.RequestsPerSecond > 0 {
+		rps := float64(limits.RequestsPerSecond)
+		state.requests = newBucket(rps, rps, now)
+	}
+	if limits.CharactersPerMinute > 0 {
+		cpm := float64(limits.CharactersPerMinute)
+		state.chars = newBucket(cpm/60, cpm, now)
+	}
+	l.clients[client] = state
+	return state
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for client, state := range l.clients {
+		if now.Sub(state.lastSeen) > idleTimeout {
+			delete(l.clients, client)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterRequestsPerSecond(t *testing.T) {
+	l := NewLimiter()
+	limits := Limits{RequestsPerSecond: 2}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, l.allow("a", limits, 0, now).Allowed)
+	assert.True(t, l.allow("a", limits, 0, now).Allowed)
+
+	d := l.allow("a", limits, 0, now)
+	assert.False(t, d.Allowed)
+	assert.False(t, d.QuotaExceeded)
+	assert.Equal(t, 500*time.Millisecond, d.RetryAfter)
+
+	assert.True(t, l.allow("b", limits, 0, now).Allowed, "clients are limited independently")
+	assert.True(t, l.allow("a", limits, 0, now.Add(time.Second)).Allowed)
+}
+
+func TestLimiterCharactersPerMinute(t *testing.T) {
+	l := NewLimiter()
+	limits := Limits{CharactersPerMinute: 60}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.True(t, l.allow("a", limits, 50, now).Allowed)
+
+	d := l.allow("a", limits, 20, now)
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 10*time.Second, d.RetryAfter)
+
+	// 超过桶容量的请求在桶满时放行，之后需要等待补足欠额
+	assert.True(t, l.allow("big", limits, 120, now).Allowed)
+	d = l.allow("big", limits, 1, now.Add(30*time.Second))
+	assert.False(t, d.Allowed)
+	assert.Equal(t, 31*time.Second, d.RetryAfter)
+}
+
+func TestLimiterQuotas(t *testing.T) {
+	l := NewLimiter()
+	limits := Limits{DailyCharacters: 100, MonthlyCharacters: 150}
+	now := time.Date(2026, 1, 30, 22, 0, 0, 0, time.UTC)
+
+	assert.True(t, l.allow("a", limits, 100, now).Allowed)
+
+	d := l.allow("a", limits, 1, now)
+	assert.False(t, d.Allowed)
+	assert.True(t, d.QuotaExceeded)
+	assert.Equal(t, 2*time.Hour, d.RetryAfter)
+
+	// 新的一天日配额重置，但月配额继续累计
+	next := time.Date(2026, 1, 31, 23, 0, 0, 0, time.UTC)
+	assert.True(t, l.allow("a", limits, 50, next).Allowed)
+
+	d = l.allow("a", limits, 1, next)
+	assert.False(t, d.Allowed)
+	assert.True(t, d.QuotaExceeded)
+	assert.Equal(t, time.Hour, d.RetryAfter)
+
+	assert.True(t, l.allow("a", limits, 1, next.Add(time.Hour)).Allowed, "monthly quota resets in a new month")
+}
+
+func TestLimiterRejectedRequestDoesNotConsume(t *testing.T) {
+	l := NewLimiter()
+	limits := Limits{RequestsPerSecond: 1, DailyCharacters: 10}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.False(t, l.allow("a", limits, 20, now).Allowed)
+	assert.True(t, l.allow("a", limits, 10, now).Allowed)
+}
+
+func TestLimitsOverride(t *testing.T) {
+	base := Limits{RequestsPerSecond: 5, DailyCharacters: 1000}
+	got := base.Override(Limits{DailyCharacters: 50, MonthlyCharacters: 500})
+
+	assert.Equal(t, Limits{RequestsPerSecond: 5, DailyCharacters: 50, MonthlyCharacters: 500}, got)
+	assert.False(t, Limits{}.Enabled())
+	assert.True(t, got.Enabled())
+	assert.False(t, Limits{RequestsPerSecond: 1}.CountsCharacters())
+}
+
+func TestQuotaStoreSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quota.json")
+	now := time.Now().UTC()
+
+	q := NewQuotaStore()
+	q.add("key:a", 42, now)
+	require.NoError(t, q.Save(path))
+
+	loaded := NewQuotaStore()
+	require.NoError(t, loaded.Load(path))
+
+	usage := loaded.Usage("key:a")
+	assert.Equal(t, int64(42), usage.DayCharacters)
+	assert.Equal(t, int64(42), usage.MonthCharacters)
+	assert.Equal(t, int64(0), loaded.Usage("key:b").DayCharacters)
+}
+
+func TestQuotaStoreSaveRetriesAfterFailure(t *testing.T) {
+	dir := t.TempDir()
+	q := NewQuotaStore()
+	q.add("key:a", 42, time.Now().UTC())
+
+	require.Error(t, q.Save(filepath.Join(dir, "missing", "quota.json")))
+
+	path := filepath.Join(dir, "quota.json")
+	require.NoError(t, q.Save(path))
+
+	loaded := NewQuotaStore()
+	require.NoError(t, loaded.Load(path))
+	assert.Equal(t, int64(42), loaded.Usage("key:a").DayCharacters)
+}
@@ -0,0 +1,161 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	dayLayout   = "2006-01-02"
+	monthLayout = "2006-01"
+)
+
+// QuotaUsage 客户端在当前自然日和自然月（UTC）内已使用的字符数
+type QuotaUsage struct {
+	Day             string `json:"day"`
+	DayCharacters   int64  `json:"day_characters"`
+	Month           string `json:"month"`
+	MonthCharacters int64  `json:"month_characters"`
+}
+
+// roll 进入新的一天或一个月时清零对应计数
+func (u *QuotaUsage) roll(now time.Time) {
+	if day := now.Format(dayLayout); u.Day != day {
+		u.Day = day
+		u.DayCharacters = 0
+	}
+	if month := now.Format(monthLayout); u.Month != month {
+		u.Month = month
+		u.MonthCharacters = 0
+	}
+}
+
+// QuotaStore 按客户端记录字符配额用量，可持久化到文件
+type QuotaStore struct {
+	mu    sync.Mutex
+	usage map[string]*QuotaUsage
+	dirty bool
+}
+
+func NewQuotaStore() *QuotaStore {
+	return &QuotaStore{
+		usage: make(map[string]*QuotaUsage),
+	}
+}
+
+// check 判断再使用 chars 个字符是否超出配额，超出时返回到配额重置的等待时间
+func (q *QuotaStore) check(client string, chars int, limits Limits, now time.Time) (time.Duration, bool) {
+	if limits.DailyCharacters <= 0 && limits.MonthlyCharacters <= 0 {
+		return 0, false
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.get(client, now)
+	if limits.MonthlyCharacters > 0 && u.MonthCharacters+int64(chars) > int64(limits.MonthlyCharacters) {
+		next := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		return next.Sub(now), true
+	}
+	if limits.DailyCharacters > 0 && u.DayCharacters+int64(chars) > int64(limits.DailyCharacters) {
+		next := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+		return next.Sub(now), true
+	}
+	return 0, false
+}
+
+// add 记录客户端使用的字符数
+func (q *QuotaStore) add(client string, chars int, now time.Time) {
+	if chars <= 0 {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	u := q.get(client, now)
+	u.DayCharacters += int64(chars)
+	u.MonthCharacters += int64(chars)
+	q.dirty = true
+}
+
+func (q *QuotaStore) get(client string, now time.Time) *QuotaUsage {
+	u, ok := q.usage[client]
+	if !ok {
+		u = &QuotaUsage{}
+		q.usage[client] = u
+	}
+	u.roll(now)
+	return u
+}
+
+// Usage 返回客户端当前的配额用量
+func (q *QuotaStore) Usage(client string) QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var u QuotaUsage
+	if existing, ok := q.usage[client]; ok {
+		u = *existing
+	}
+	u.roll(time.Now().UTC())
+	return u
+}
+
+// Save 将用量写入文件，自上次保存后没有变化时跳过。写入失败时保留未保存标记，下次重试
+func (q *QuotaStore) Save(path string) error {
+	q.mu.Lock()
+	if !q.dirty {
+		q.mu.Unlock()
+		return nil
+	}
+	data, err := json.Marshal(q.usage)
+	if err != nil {
+		q.mu.Unlock()
+		return fmt.Errorf("failed to marshal quota usage: %w", err)
+	}
+	q.dirty = false
+	q.mu.Unlock()
+
+	if err := writeQuotaFile(path, data); err != nil {
+		q.mu.Lock()
+		q.dirty = true
+		q.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func writeQuotaFile(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write quota file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move quota file: %w", err)
+	}
+	return nil
+}
+
+// Load 从文件恢复用量
+func (q *QuotaStore) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	usage := make(map[string]*QuotaUsage)
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return fmt.Errorf("failed to parse quota file: %w", err)
+	}
+
+	q.mu.Lock()
+	q.usage = usage
+	q.dirty = false
+	q.mu.Unlock()
+	return nil
+}
@@ -7,14 +7,36 @@ import (
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	"github.com/xxnuo/MTranServer/internal/auth"
 	"github.com/xxnuo/MTranServer/internal/config"
 	"github.com/xxnuo/MTranServer/internal/docs"
 	"github.com/xxnuo/MTranServer/internal/handlers"
+	"github.com/xxnuo/MTranServer/internal/metrics"
 	"github.com/xxnuo/MTranServer/internal/middleware"
+	"github.com/xxnuo/MTranServer/internal/ratelimit"
 	"github.com/xxnuo/MTranServer/ui"
 )
 
 func Setup(r *gin.Engine, apiToken string) {
+	adminToken := config.GetConfig().AdminToken
+
+	setup(r, &auth.Authenticator{
+		APIToken:   func() string { return apiToken },
+		AdminToken: func() string { return adminToken },
+		Keys:       auth.DefaultStore,
+	})
+}
+
+// SetupWithConfig 注册路由，令牌在每次请求时从配置读取，支持热更新
+func SetupWithConfig(r *gin.Engine, cfg *config.Config) {
+	setup(r, &auth.Authenticator{
+		APIToken:   cfg.GetAPIToken,
+		AdminToken: cfg.GetAdminToken,
+		Keys:       auth.DefaultStore,
+	})
+}
+
+func setup(r *gin.Engine, authenticator *auth.Authenticator) {
 
 	r.Use(middleware.CORS())
 
@@ -26,21 +48,73 @@ func Setup(r *gin.Engine, apiToken string) {
 	r.GET("/__heartbeat__", handlers.HandleHeartbeat)
 	r.GET("/__lbheartbeat__", handlers.HandleLBHeartbeat)
 
-	auth := r.Group("/")
-	if apiToken != "" {
-		auth.Use(middleware.Auth(apiToken))
+	limits := func() ratelimit.Limits {
+		return ratelimit.Limits(config.GetConfig().GetRateLimits())
 	}
+	rateLimit := middleware.RateLimit(ratelimit.Default, limits, http.StatusTooManyRequests)
+
+	api := r.Group("/")
+	api.Use(middleware.RequireScope(authenticator, auth.ScopeTranslate), rateLimit)
+
+	api.GET("/languages", handlers.HandleLanguages)
+	api.POST("/translate", handlers.HandleTranslate)
+	api.POST("/translate/batch", handlers.HandleTranslateBatch)
+	api.POST("/translate/stream", handlers.HandleTranslateStream)
+	api.POST("/translate/subtitle", handlers.HandleTranslateSubtitle)
+	api.POST("/translate/catalog", handlers.HandleTranslateCatalog)
+	api.POST("/translate/i18n", handlers.HandleTranslateI18n)
+	api.GET("/ws", handlers.HandleWebSocket)
+	api.GET("/cache/stats", handlers.HandleCacheStats)
+
+	api.GET("/glossary-language-pairs", handlers.HandleGlossaryLanguagePairs)
+	api.POST("/glossaries", handlers.HandleCreateGlossary)
+	api.GET("/glossaries", handlers.HandleListGlossaries)
+	api.GET("/glossaries/:id", handlers.HandleGetGlossary)
+	api.PATCH("/glossaries/:id", handlers.HandleUpdateGlossary)
+	api.DELETE("/glossaries/:id", handlers.HandleDeleteGlossary)
+	api.GET("/glossaries/:id/entries", handlers.HandleGlossaryEntries)
+
+	r.GET("/metrics", middleware.RequireScope(authenticator, auth.ScopeMetrics), gin.WrapH(metrics.Handler()))
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.RequireScope(authenticator, auth.ScopeAdmin))
+
+	admin.GET("/engines", handlers.HandleListEngines)
+	admin.POST("/engines/:from/:to", handlers.HandleWarmEngine)
+	admin.DELETE("/engines/:from/:to", handlers.HandleStopEngine)
+	admin.POST("/engines/:from/:to/workers/:port/restart", handlers.HandleRestartWorker)
+	admin.GET("/engines/:from/:to/workers/:port/logs", handlers.HandleWorkerLogs)
+
+	admin.GET("/models", handlers.HandleListModels)
+	admin.POST("/models/:from/:to/download", handlers.HandleDownloadModel)
+	admin.GET("/models/:from/:to/download", handlers.HandleModelDownloadStatus)
+	admin.DELETE("/models/:from/:to", handlers.HandleDeleteModel)
+	admin.POST("/models/:from/:to/verify", handlers.HandleVerifyModel)
+
+	plugins := r.Group("/")
+	plugins.Use(middleware.RequireScope(authenticator, auth.ScopePlugins), rateLimit)
 
-	auth.GET("/languages", handlers.HandleLanguages)
-	auth.POST("/translate", handlers.HandleTranslate)
-	auth.POST("/translate/batch", handlers.HandleTranslateBatch)
+	plugins.POST("/imme", handlers.HandleImmeTranslate)
+	plugins.POST("/kiss", handlers.HandleKissTranslate)
+	plugins.POST("/google/language/translate/v2", handlers.HandleGoogleCompatTranslate)
+	plugins.GET("/google/translate_a/single", handlers.HandleGoogleTranslateSingle)
+	plugins.POST("/hcfy", handlers.HandleHcfyTranslate)
+	plugins.POST("/libre/translate", handlers.HandleLibreTranslate)
+	plugins.POST("/libre/detect", handlers.HandleLibreDetect)
+	plugins.GET("/libre/languages", handlers.HandleLibreLanguages)
+	plugins.POST("/microsoft/translate", handlers.HandleMicrosoftTranslate)
+	plugins.POST("/microsoft/detect", handlers.HandleMicrosoftDetect)
+	plugins.GET("/microsoft/languages", handlers.HandleMicrosoftLanguages)
+	plugins.POST("/v1/chat/completions", handlers.HandleOpenAIChatCompletions)
+	plugins.GET("/v1/models", handlers.HandleOpenAIModels)
 
-	r.POST("/imme", handlers.HandleImmeTranslate(apiToken))
-	r.POST("/kiss", handlers.HandleKissTranslate(apiToken))
-	r.POST("/deepl", handlers.HandleDeeplTranslate(apiToken))
-	r.POST("/google/language/translate/v2", handlers.HandleGoogleCompatTranslate(apiToken))
-	r.GET("/google/translate_a/single", handlers.HandleGoogleTranslateSingle(apiToken))
-	r.POST("/hcfy", handlers.HandleHcfyTranslate(apiToken))
+	// DeepL 客户端通过 456 识别配额用尽
+	deepl := r.Group("/deepl")
+	deepl.Use(
+		middleware.RequireScope(authenticator, auth.ScopePlugins),
+		middleware.RateLimit(ratelimit.Default, limits, middleware.StatusDeeplQuotaExceeded),
+	)
+	deepl.POST("", handlers.HandleDeeplTranslate)
 
 	cfg := config.GetConfig()
 	if cfg.EnableWebUI {
@@ -0,0 +1,218 @@
+package sentence
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Span 一个句子在原文中的字节偏移，不包含首尾空白
+type Span struct {
+	Start int
+	End   int
+}
+
+// abbreviations 以句点结尾但通常不结束句子的缩写（小写，不含最后的句点），按语言代码索引，"" 适用于所有语言
+var abbreviations = map[string]map[string]bool{
+	"": set("mr", "mrs", "ms", "dr", "prof", "sr", "jr", "st", "vs", "etc", "e.g", "i.e", "cf", "al",
+		"inc", "ltd", "co", "corp", "no", "nos", "vol", "fig", "figs", "approx", "dept", "est", "jan", "feb",
+		"mar", "apr", "jun", "jul", "aug", "sep", "sept", "oct", "nov", "dec", "u.s", "u.k", "a.m", "p.m",
+		"ph.d", "mt", "ave", "gen", "gov", "rev", "sgt", "capt", "col", "lt"),
+	"de": set("z.b", "bzw", "usw", "ca", "d.h", "u.a", "vgl", "nr", "str", "evtl", "ggf", "inkl", "zzgl",
+		"bspw", "sog", "geb", "hr", "fr", "abs", "s"),
+	"fr": set("m", "mme", "mlle", "p.ex", "env", "av", "bd", "cf", "p"),
+	"es": set("sr", "sra", "srta", "ud", "uds", "p.ej", "pág", "núm", "dra", "av"),
+	"it": set("sig", "sig.ra", "dott", "ecc", "pag", "n"),
+	"pt": set("sr", "sra", "dr", "dra", "pág", "n.º", "av"),
+	"nl": set("bijv", "d.w.z", "o.a", "mevr", "dhr", "blz", "nr"),
+	"ru": set("т.е", "т.д", "т.п", "т.к", "г", "гг", "см", "др", "стр", "им", "ул", "тыс", "млн", "млрд"),
+}
+
+// ordinalLanguages 数字后的句点表示序数（如德语 1. Mai），不结束句子
+var ordinalLanguages = map[string]bool{"de": true, "da": true, "nb": true, "no": true, "fi": true, "cs": true, "sk": true, "pl": true, "hu": true, "hr": true, "sl": true, "et": true, "lv": true, "tr": true}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
+
+// Split 把文本切分为句子。lang 为源语言代码，用于识别缩写；newlines 为 true 时换行总是结束句子。
+// 句子之间的空白不属于任何句子，调用方按偏移从原文保留
+func Split(text, lang string, newlines bool) []Span {
+	lang, _, _ = strings.Cut(lang, "-")
+
+	var spans []Span
+	start := -1
+	end := func(i int) {
+		if start >= 0 {
+			s, e := trim(text, start, i)
+			if s < e {
+				spans = append(spans, Span{Start: s, End: e})
+			}
+		}
+		start = -1
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		if r == '\n' && newlines {
+			end(i)
+			i += size
+			continue
+		}
+		if start < 0 && !unicode.IsSpace(r) {
+			start = i
+		}
+
+		switch {
+		case isFullWidthStop(r):
+			// 中日文句号之后不需要空白
+			i = skipClosers(text, skipStops(text, i))
+			end(i)
+			continue
+
+		case isStop(r):
+			next := skipClosers(text, skipStops(text, i))
+			if breaksAfter(text, lang, i, next) {
+				end(next)
+			}
+			i = next
+			continue
+		}
+		i += size
+	}
+	end(len(text))
+	return spans
+}
+
+// breaksAfter 判断 text[stop:next] 处的句末标点之后是否结束句子
+func breaksAfter(text, lang string, stop, next int) bool {
+	if next == len(text) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(text[next:])
+	if !unicode.IsSpace(r) {
+		return false
+	}
+
+	// 下一句以小写字母开头时不切分，例如 "Stop!" he said 或 etc. and
+	rest := strings.TrimLeftFunc(text[next:], unicode.IsSpace)
+	if r, _ := utf8.DecodeRuneInString(rest); unicode.IsLower(r) {
+		return false
+	}
+
+	if text[stop] != '.' || strings.HasPrefix(text[stop:next], "..") {
+		return true
+	}
+
+	word := wordBefore(text, stop)
+	if word == "" {
+		return true
+	}
+	lower := strings.ToLower(word)
+	if abbreviations[""][lower] || abbreviations[lang][lower] {
+		return false
+	}
+	// 单个大写字母是姓名缩写，如 J. K. Rowling
+	if r, size := utf8.DecodeRuneInString(word); size == len(word) && unicode.IsUpper(r) {
+		return false
+	}
+	if ordinalLanguages[lang] && isDigits(word) {
+		return false
+	}
+	return true
+}
+
+// wordBefore 返回 end 之前由字母、数字和句点组成的单词，如 e.g、U.S
+func wordBefore(text string, end int) string {
+	start := end
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(text[:start])
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != 'º' {
+			break
+		}
+		start -= size
+	}
+	return strings.Trim(text[start:end], ".")
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isStop(r rune) bool {
+	switch r {
+	case '.', '!', '?', '…', '‼', '⁇', '⁈', '⁉':
+		return true
+	}
+	return false
+}
+
+func isFullWidthStop(r rune) bool {
+	switch r {
+	case '。', '！', '？', '｡', '．':
+		return true
+	}
+	return false
+}
+
+// isCloser 句末标点之后属于同一句的右引号和右括号
+func isCloser(r rune) bool {
+	switch r {
+	case '"', '\'', '”', '’', ')', ']', '}', '»', '›', '」', '』', '）', '】', '〉', '》', '〕':
+		return true
+	}
+	return false
+}
+
+func skipStops(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isStop(r) && !isFullWidthStop(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+func skipClosers(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !isCloser(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+func trim(text string, start, end int) (int, int) {
+	s := text[start:end]
+	trimmed := strings.TrimLeftFunc(s, unicode.IsSpace)
+	start += len(s) - len(trimmed)
+	trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	return start, start + len(trimmed)
+}
+
+// Join 用译文替换原文中的各个句子，保留句子之间的空白
+func Join(text string, spans []Span, results []string) string {
+	var sb strings.Builder
+	last := 0
+	for i, span := range spans {
+		sb.WriteString(text[last:span.Start])
+		sb.WriteString(results[i])
+		last = span.End
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
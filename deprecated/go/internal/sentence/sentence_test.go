@@ -0,0 +1,60 @@
+package sentence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sentences(text, lang string, newlines bool) []string {
+	var out []string
+	for _, span := range Split(text, lang, newlines) {
+		out = append(out, text[span.Start:span.End])
+	}
+	return out
+}
+
+func TestSplitEnglish(t *testing.T) {
+	text := "Mr. Smith arrived at 3.30 p.m. yesterday. He met Dr. J. K. Jones, e.g. at the U.S. office! Was it fun? \"Yes.\" She said \"stop!\" and left... Then silence"
+	assert.Equal(t, []string{
+		"Mr. Smith arrived at 3.30 p.m. yesterday.",
+		"He met Dr. J. K. Jones, e.g. at the U.S. office!",
+		"Was it fun?",
+		"\"Yes.\"",
+		"She said \"stop!\" and left...",
+		"Then silence",
+	}, sentences(text, "en", true))
+}
+
+func TestSplitLanguageAbbreviations(t *testing.T) {
+	assert.Equal(t, []string{"Wir treffen uns am 1. Mai z.B. im Büro.", "Bis dann."},
+		sentences("Wir treffen uns am 1. Mai z.B. im Büro. Bis dann.", "de", true))
+	assert.Equal(t, []string{"We met on day 1.", "Mai was there."},
+		sentences("We met on day 1. Mai was there.", "en", true))
+	assert.Equal(t, []string{"Это т.е. пример.", "Второе предложение."},
+		sentences("Это т.е. пример. Второе предложение.", "ru", true))
+}
+
+func TestSplitCJK(t *testing.T) {
+	assert.Equal(t, []string{"今天天气很好。", "我们去公园吧！", "「好的。」", "他说"},
+		sentences("今天天气很好。我们去公园吧！「好的。」他说", "zh-Hans", true))
+	assert.Equal(t, []string{"これはペンです。", "あれは本ですか？"},
+		sentences("これはペンです。 あれは本ですか？", "ja", true))
+}
+
+func TestSplitNewlines(t *testing.T) {
+	text := "First line\nstill first.  Second.\n\n  Third"
+	assert.Equal(t, []string{"First line", "still first.", "Second.", "Third"}, sentences(text, "en", true))
+	assert.Equal(t, []string{"First line\nstill first.", "Second.", "Third"}, sentences(text, "en", false))
+}
+
+func TestSplitEmpty(t *testing.T) {
+	assert.Empty(t, Split("", "en", true))
+	assert.Empty(t, Split(" \n\t ", "en", true))
+}
+
+func TestJoin(t *testing.T) {
+	text := "  One.  Two!\nThree "
+	spans := Split(text, "en", true)
+	assert.Equal(t, "  Eins.  Zwei!\nDrei ", Join(text, spans, []string{"Eins.", "Zwei!", "Drei"}))
+}
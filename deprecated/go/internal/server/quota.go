@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/ratelimit"
+)
+
+const (
+	quotaFileName     = "quota.json"
+	quotaSaveInterval = time.Minute
+)
+
+func quotaPath(cfg *config.Config) string {
+	return filepath.Join(cfg.ConfigDir, quotaFileName)
+}
+
+// loadQuotas 从配置目录恢复字符配额用量
+func loadQuotas(cfg *config.Config) {
+	path := quotaPath(cfg)
+	if err := ratelimit.Default.Quotas.Load(path); err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to load quota usage from %s: %v", path, err)
+		}
+		return
+	}
+	logger.Debug("Loaded quota usage from %s", path)
+}
+
+// saveQuotas 将字符配额用量写入配置目录
+func saveQuotas(cfg *config.Config) {
+	if err := ratelimit.Default.Quotas.Save(quotaPath(cfg)); err != nil {
+		logger.Error("Failed to save quota usage: %v", err)
+	}
+}
+
+// persistQuotas 定期保存配额用量，避免进程异常退出时丢失
+func persistQuotas(ctx context.Context, cfg *config.Config) {
+	ticker := time.NewTicker(quotaSaveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			saveQuotas(cfg)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/xxnuo/MTranServer/internal/auth"
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+)
+
+const configPollInterval = 5 * time.Second
+
+// watchConfig 在收到 SIGHUP 或配置文件、密钥文件修改时重新加载配置
+func watchConfig(ctx context.Context, cfg *config.Config) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	lastMod := configModTime(cfg.ConfigFile)
+	lastKeysMod := configModTime(cfg.GetKeysFile())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			logger.Info("Received SIGHUP, reloading configuration")
+			lastMod = configModTime(cfg.ConfigFile)
+			reloadConfig(cfg)
+			lastKeysMod = configModTime(cfg.GetKeysFile())
+		case <-ticker.C:
+			if mod := configModTime(cfg.ConfigFile); cfg.ConfigFile != "" && !mod.Equal(lastMod) {
+				lastMod = mod
+				logger.Info("Config file %s changed, reloading configuration", cfg.ConfigFile)
+				reloadConfig(cfg)
+				lastKeysMod = configModTime(cfg.GetKeysFile())
+				continue
+			}
+			if mod := configModTime(cfg.GetKeysFile()); !mod.Equal(lastKeysMod) {
+				lastKeysMod = mod
+				logger.Info("Keys file %s changed, reloading API keys", cfg.GetKeysFile())
+				reloadKeys(cfg)
+			}
+		}
+	}
+}
+
+func reloadConfig(cfg *config.Config) {
+	if err := config.Reload(); err != nil {
+		logger.Error("Failed to reload configuration, keeping current settings: %v", err)
+		return
+	}
+
+	logger.SetLevel(cfg.GetLogLevel())
+	reloadKeys(cfg)
+	logger.Info("Configuration reloaded")
+}
+
+func reloadKeys(cfg *config.Config) {
+	if err := auth.DefaultStore.LoadFile(cfg.GetKeysFile()); err != nil {
+		logger.Error("Failed to reload API keys, keeping current keys: %v", err)
+	}
+}
+
+func configModTime(path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
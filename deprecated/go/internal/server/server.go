@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/xxnuo/MTranServer/internal/auth"
 	"github.com/xxnuo/MTranServer/internal/config"
 	"github.com/xxnuo/MTranServer/internal/logger"
 	"github.com/xxnuo/MTranServer/internal/manager"
@@ -36,14 +37,23 @@ func Run() error {
 		return fmt.Errorf("failed to initialize worker binary: %w", err)
 	}
 
+	if err := auth.DefaultStore.LoadFile(cfg.GetKeysFile()); err != nil {
+		return fmt.Errorf("failed to load API keys: %w", err)
+	}
+
+	services.InitCache()
+	services.InitGlossaries()
+	loadQuotas(cfg)
+
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
 
 	r.Use(middleware.Recovery())
 	r.Use(middleware.Logger())
+	r.Use(middleware.Metrics())
 
-	routes.Setup(r, cfg.APIToken)
+	routes.SetupWithConfig(r, cfg)
 
 	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
 	srv := &http.Server{
@@ -51,6 +61,11 @@ func Run() error {
 		Handler: r,
 	}
 
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go watchConfig(watchCtx, cfg)
+	go persistQuotas(watchCtx, cfg)
+
 	shutdownDone := make(chan struct{})
 
 	go func() {
@@ -64,6 +79,8 @@ func Run() error {
 		defer cancel()
 
 		services.CleanupAllEngines()
+		services.SaveCache()
+		saveQuotas(cfg)
 
 		if err := srv.Shutdown(ctx); err != nil {
 			logger.Error("Server forced to shutdown: %v", err)
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/manager"
+)
+
+var (
+	ErrEngineNotFound = errors.New("engine not found")
+	ErrWorkerNotFound = errors.New("worker not found")
+	ErrWorkerBusy     = errors.New("worker is already restarting")
+)
+
+// WorkerStatus 单个 worker 的运行状态
+type WorkerStatus struct {
+	Port  int    `json:"port"`
+	PID   int    `json:"pid"`
+	State string `json:"state"`
+}
+
+// EngineStatus 语言对引擎池的运行状态
+type EngineStatus struct {
+	Pair         string         `json:"pair"`
+	From         string         `json:"from"`
+	To           string         `json:"to"`
+	LastUsed     time.Time      `json:"last_used"`
+	IdleDeadline time.Time      `json:"idle_deadline"`
+	Workers      []WorkerStatus `json:"workers"`
+}
+
+// ListEngines 返回当前已加载的引擎池，按语言对排序
+func ListEngines() []EngineStatus {
+	cfg := config.GetConfig()
+
+	engMu.RLock()
+	infos := make([]*EngineInfo, 0, len(engines))
+	for _, info := range engines {
+		infos = append(infos, info)
+	}
+	engMu.RUnlock()
+
+	result := make([]EngineStatus, 0, len(infos))
+	for _, info := range infos {
+		info.mu.Lock()
+		status := EngineStatus{
+			Pair:         fmt.Sprintf("%s-%s", info.FromLang, info.ToLang),
+			From:         info.FromLang,
+			To:           info.ToLang,
+			LastUsed:     info.LastUsed,
+			IdleDeadline: info.LastUsed.Add(time.Duration(cfg.GetWorkerIdleTimeout(info.FromLang, info.ToLang)) * time.Second),
+		}
+		managers := append([]*manager.Manager(nil), info.Managers...)
+		info.mu.Unlock()
+
+		status.Workers = make([]WorkerStatus, 0, len(managers))
+		for _, m := range managers {
+			if m == nil {
+				continue
+			}
+			status.Workers = append(status.Workers, WorkerStatus{
+				Port:  m.Port(),
+				PID:   m.PID(),
+				State: m.Status(),
+			})
+		}
+		result = append(result, status)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Pair < result[j].Pair
+	})
+	return result
+}
+
+// WarmEngine 预先启动翻译所需的引擎池，需要中转时同时启动两段引擎
+func WarmEngine(fromLang, toLang string) error {
+	if fromLang == toLang {
+		return fmt.Errorf("source and target language are the same: %s", fromLang)
+	}
+
+	if !needsPivotTranslation(fromLang, toLang) {
+		_, err := getOrCreateSingleEngine(fromLang, toLang)
+		return err
+	}
+
+	if _, err := getOrCreateSingleEngine(fromLang, "en"); err != nil {
+		return err
+	}
+	_, err := getOrCreateSingleEngine("en", toLang)
+	return err
+}
+
+// StopEngine 停止并移除指定语言对的引擎池
+func StopEngine(fromLang, toLang string) error {
+	key := fmt.Sprintf("%s-%s", fromLang, toLang)
+
+	engMu.Lock()
+	info, ok := engines[key]
+	if ok {
+		delete(engines, key)
+	}
+	engMu.Unlock()
+
+	if !ok {
+		return ErrEngineNotFound
+	}
+
+	info.mu.Lock()
+	if info.stopTimer != nil {
+		info.stopTimer.Stop()
+	}
+	info.mu.Unlock()
+
+	for _, m := range info.Managers {
+		if m != nil {
+			if err := m.Cleanup(); err != nil {
+				logger.Error("Failed to cleanup manager: %v", err)
+			}
+		}
+	}
+
+	logger.Info("Engine %s stopped by admin request", key)
+	return nil
+}
+
+// RestartEngineWorker 重启引擎池中监听指定端口的 worker
+func RestartEngineWorker(fromLang, toLang string, port int) error {
+	m, err := findWorker(fromLang, toLang, port)
+	if err != nil {
+		return err
+	}
+
+	if m.Status() == "restarting" {
+		return ErrWorkerBusy
+	}
+
+	logger.Info("Restarting worker on port %d for %s -> %s by admin request", port, fromLang, toLang)
+	return m.RestartWorker()
+}
+
+// GetWorkerLogs 返回指定 worker 最近的日志
+func GetWorkerLogs(fromLang, toLang string, port int) ([]string, error) {
+	m, err := findWorker(fromLang, toLang, port)
+	if err != nil {
+		return nil, err
+	}
+	return m.Logs(), nil
+}
+
+func findWorker(fromLang, toLang string, port int) (*manager.Manager, error) {
+	info := getEngineInfo(fromLang, toLang)
+	if info == nil {
+		return nil, ErrEngineNotFound
+	}
+
+	info.mu.Lock()
+	defer info.mu.Unlock()
+
+	for _, m := range info.Managers {
+		if m != nil && m.Port() == port {
+			return m, nil
+		}
+	}
+	return nil, ErrWorkerNotFound
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/manager"
+)
+
+// maxBatchItems 限制单条 trans_batch 消息携带的文本数量
+const maxBatchItems = 64
+
+// TranslateBatch 批量翻译多个文本，结果顺序与输入一致。
+// 有效语言对相同的文本合并为一次 worker 调用，包含多种语言的文本单独翻译
+func TranslateBatch(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, error) {
+	logger.Debug("TranslateBatch: %s -> %s, count: %d, isHTML: %v", fromLang, toLang, len(texts), isHTML)
+
+	results := make([]string, len(texts))
+
+	cache := getTranslationCache()
+	var version string
+	if cache != nil {
+		version = modelFingerprint(toLang)
+	}
+
+	groups := make(map[string][]int)
+	var groupOrder []string
+
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			results[i] = text
+			continue
+		}
+
+		if cache != nil {
+			if result, ok := cache.Get(cacheKey(fromLang, toLang, text, isHTML), version); ok {
+				results[i] = result
+				continue
+			}
+		}
+
+		lang, segments, err := resolveSourceLanguage(fromLang, text)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+
+		if len(segments) > 0 {
+			result, complete, err := translateWithPivot(ctx, fromLang, toLang, text, isHTML)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i, err)
+			}
+			if cache != nil && complete {
+				cache.Set(cacheKey(fromLang, toLang, text, isHTML), version, result)
+			}
+			results[i] = result
+			continue
+		}
+
+		if lang == toLang {
+			results[i] = text
+			continue
+		}
+
+		if _, ok := groups[lang]; !ok {
+			groupOrder = append(groupOrder, lang)
+		}
+		groups[lang] = append(groups[lang], i)
+	}
+
+	for _, lang := range groupOrder {
+		indexes := groups[lang]
+		batch := make([]string, len(indexes))
+		for j, idx := range indexes {
+			batch[j] = texts[idx]
+		}
+
+		translated, err := translateSegmentBatch(ctx, lang, toLang, batch, isHTML)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, idx := range indexes {
+			results[idx] = translated[j]
+			if cache != nil {
+				cache.Set(cacheKey(fromLang, toLang, texts[idx], isHTML), version, translated[j])
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func translateSegmentBatch(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, error) {
+	if fromLang == toLang {
+		return texts, nil
+	}
+
+	return translateProtected(ctx, texts, isHTML, func(ctx context.Context, texts []string, isHTML bool) ([]string, error) {
+		return translatePivotBatch(ctx, fromLang, toLang, texts, isHTML)
+	})
+}
+
+// translatePivotBatch 批量翻译，没有直接模型时经英语中转
+func translatePivotBatch(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, error) {
+	if !needsPivotTranslation(fromLang, toLang) {
+		return translateSingleLanguageBatch(ctx, fromLang, toLang, texts, isHTML)
+	}
+
+	// Pivot Translation
+	intermediate, err := translateSingleLanguageBatch(ctx, fromLang, "en", texts, isHTML)
+	if err != nil {
+		return nil, err
+	}
+	return translateSingleLanguageBatch(ctx, "en", toLang, intermediate, isHTML)
+}
+
+// translateSingleLanguageBatch 批量翻译，包含多个句子的文本拆分为句子一起发送
+func translateSingleLanguageBatch(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, error) {
+	pieces, join := splitSentenceBatch(ctx, fromLang, texts, isHTML)
+	translated, err := translateSingleLanguagePieces(ctx, fromLang, toLang, pieces, isHTML)
+	if err != nil {
+		return nil, err
+	}
+	return join(translated), nil
+}
+
+// translateSingleLanguagePieces 按 maxBatchItems 分组发送 trans_batch 请求，worker 不支持时逐条翻译
+func translateSingleLanguagePieces(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, error) {
+	results := make([]string, 0, len(texts))
+
+	for start := 0; start < len(texts); start += maxBatchItems {
+		end := min(start+maxBatchItems, len(texts))
+		chunk := texts[start:end]
+
+		m, err := getOrCreateSingleEngine(fromLang, toLang)
+		if err != nil {
+			return nil, err
+		}
+
+		if !m.SupportsBatch() {
+			logger.Debug("translateSingleLanguagePieces: worker has no batch support, translating %d items individually", len(chunk))
+			translated, err := translateEachSingleLanguage(ctx, fromLang, toLang, chunk, isHTML)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, translated...)
+			continue
+		}
+
+		reqs := make([]manager.TransRequest, len(chunk))
+		for i, text := range chunk {
+			reqs[i] = manager.TransRequest{Text: text, HTML: isHTML}
+		}
+
+		translated, err := m.TransBatch(ctx, reqs)
+		if err == nil {
+			for _, text := range chunk {
+				recordTranslation(fromLang, toLang, text, nil)
+			}
+		} else {
+			if !isConnectionError(err) {
+				for _, text := range chunk {
+					recordTranslation(fromLang, toLang, text, err)
+				}
+				return nil, err
+			}
+			logger.Warn("Batch translation failed (%s -> %s): %v, falling back to per-item translation", fromLang, toLang, err)
+			translated, err = translateEachSingleLanguage(ctx, fromLang, toLang, chunk, isHTML)
+			if err != nil {
+				return nil, err
+			}
+		}
+		results = append(results, translated...)
+	}
+
+	return results, nil
+}
+
+func translateEachSingleLanguage(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, error) {
+	results := make([]string, len(texts))
+	for i, text := range texts {
+		result, err := translateSingleLanguageRequest(ctx, fromLang, toLang, text, isHTML)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+const cacheFileName = "cache.json"
+
+type cacheEntry struct {
+	Key      string    `json:"key"`
+	Result   string    `json:"result"`
+	Version  string    `json:"version"`
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+type CacheStats struct {
+	Enabled   bool    `json:"enabled"`
+	Size      int     `json:"size"`
+	Capacity  int     `json:"capacity"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	HitRate   float64 `json:"hit_rate"`
+}
+
+// TranslationCache 是翻译结果的 LRU 缓存，条目按模型版本失效
+type TranslationCache struct {
+	mu        sync.Mutex
+	capacity  int
+	ttl       time.Duration
+	ll        *list.List
+	items     map[string]*list.Element
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+var (
+	translationCache *TranslationCache
+	cacheOnce        sync.Once
+
+	fingerprintMu      sync.Mutex
+	fingerprintRecords *models.RecordsData
+	fingerprints       map[string]string
+)
+
+func NewTranslationCache(capacity int, ttl time.Duration) *TranslationCache {
+	return &TranslationCache{
+		capacity: capacity,
+		ttl:      ttl,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func cacheKey(fromLang, toLang, text string, isHTML bool) string {
+	h := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("%s|%s|%t|%s", fromLang, toLang, isHTML, hex.EncodeToString(h[:]))
+}
+
+func (tc *TranslationCache) Get(key, version string) (string, bool) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	el, ok := tc.items[key]
+	if !ok {
+		tc.misses.Add(1)
+		return "", false
+	}
+
+	entry := el.Value.(*cacheEntry)
+	if entry.Version != version || (!entry.ExpireAt.IsZero() && time.Now().After(entry.ExpireAt)) {
+		tc.removeElement(el)
+		tc.misses.Add(1)
+		return "", false
+	}
+
+	tc.ll.MoveToFront(el)
+	tc.hits.Add(1)
+	return entry.Result, true
+}
+
+func (tc *TranslationCache) Set(key, version, result string) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	var expireAt time.Time
+	if tc.ttl > 0 {
+		expireAt = time.Now().Add(tc.ttl)
+	}
+
+	if el, ok := tc.items[key]; ok {
+		entry := el.Value.(*cacheEntry)
+		entry.Result = result
+		entry.Version = version
+		entry.ExpireAt = expireAt
+		tc.ll.MoveToFront(el)
+		return
+	}
+
+	tc.items[key] = tc.ll.PushFront(&cacheEntry{
+		Key:      key,
+		Result:   result,
+		Version:  version,
+		ExpireAt: expireAt,
+	})
+
+	for tc.ll.Len() > tc.capacity {
+		tc.removeElement(tc.ll.Back())
+		tc.evictions.Add(1)
+	}
+}
+
+func (tc *TranslationCache) removeElement(el *list.Element) {
+	tc.ll.Remove(el)
+	delete(tc.items, el.Value.(*cacheEntry).Key)
+}
+
+func (tc *TranslationCache) Len() int {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.ll.Len()
+}
+
+func (tc *TranslationCache) Purge() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.ll.Init()
+	tc.items = make(map[string]*list.Element)
+}
+
+func (tc *TranslationCache) Stats() CacheStats {
+	hits := tc.hits.Load()
+	misses := tc.misses.Load()
+
+	stats := CacheStats{
+		Enabled:   true,
+		Size:      tc.Len(),
+		Capacity:  tc.capacity,
+		Hits:      hits,
+		Misses:    misses,
+		Evictions: tc.evictions.Load(),
+	}
+	if hits+misses > 0 {
+		stats.HitRate = float64(hits) / float64(hits+misses)
+	}
+	return stats
+}
+
+// Save 将未过期的条目按最近使用顺序写入文件
+func (tc *TranslationCache) Save(path string) error {
+	tc.mu.Lock()
+	now := time.Now()
+	entries := make([]cacheEntry, 0, tc.ll.Len())
+	for el := tc.ll.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*cacheEntry)
+		if !entry.ExpireAt.IsZero() && now.After(entry.ExpireAt) {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+	tc.mu.Unlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to move cache file: %w", err)
+	}
+	return nil
+}
+
+func (tc *TranslationCache) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var entries []cacheEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to parse cache file: %w", err)
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	now := time.Now()
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if !entry.ExpireAt.IsZero() && now.After(entry.ExpireAt) {
+			continue
+		}
+		if el, ok := tc.items[entry.Key]; ok {
+			tc.removeElement(el)
+		}
+		tc.items[entry.Key] = tc.ll.PushFront(&entry)
+		for tc.ll.Len() > tc.capacity {
+			tc.removeElement(tc.ll.Back())
+		}
+	}
+	return nil
+}
+
+func getTranslationCache() *TranslationCache {
+	cacheOnce.Do(func() {
+		cfg := config.GetConfig()
+		if cfg.CacheSize <= 0 {
+			logger.Debug("Translation cache disabled")
+			return
+		}
+		ttl := time.Duration(cfg.CacheTTL) * time.Second
+		translationCache = NewTranslationCache(cfg.CacheSize, ttl)
+		logger.Debug("Translation cache enabled, capacity: %d, ttl: %v", cfg.CacheSize, ttl)
+	})
+	return translationCache
+}
+
+// InitCache 初始化翻译缓存，启用持久化时从配置目录恢复
+func InitCache() {
+	tc := getTranslationCache()
+	if tc == nil {
+		return
+	}
+
+	cfg := config.GetConfig()
+	if !cfg.CachePersist {
+		return
+	}
+
+	path := filepath.Join(cfg.ConfigDir, cacheFileName)
+	if err := tc.Load(path); err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to load translation cache from %s: %v", path, err)
+		}
+		return
+	}
+	logger.Info("Loaded %d cached translations from %s", tc.Len(), path)
+}
+
+// SaveCache 在启用持久化时将翻译缓存写入配置目录
+func SaveCache() {
+	tc := getTranslationCache()
+	if tc == nil {
+		return
+	}
+
+	cfg := config.GetConfig()
+	if !cfg.CachePersist {
+		return
+	}
+
+	path := filepath.Join(cfg.ConfigDir, cacheFileName)
+	if err := tc.Save(path); err != nil {
+		logger.Error("Failed to save translation cache: %v", err)
+		return
+	}
+	logger.Debug("Saved translation cache to %s", path)
+}
+
+func GetCacheStats() CacheStats {
+	tc := getTranslationCache()
+	if tc == nil {
+		return CacheStats{}
+	}
+	return tc.Stats()
+}
+
+// modelFingerprint 计算所有可能参与翻译到 toLang 的模型版本摘要，
+// 包括直接翻译到 toLang 的模型和经英语中转时用到的 xx -> en 模型
+func modelFingerprint(toLang string) string {
+	records := models.GlobalRecords
+	if records == nil {
+		return ""
+	}
+
+	fingerprintMu.Lock()
+	defer fingerprintMu.Unlock()
+
+	if fingerprintRecords != records {
+		fingerprintRecords = records
+		fingerprints = make(map[string]string)
+	}
+	if fp, ok := fingerprints[toLang]; ok {
+		return fp
+	}
+
+	var parts []string
+	for _, record := range records.Data {
+		if record.TargetLanguage == toLang || record.TargetLanguage == "en" {
+			parts = append(parts, fmt.Sprintf("%s-%s:%s:%s",
+				record.SourceLanguage, record.TargetLanguage, record.FileType, record.Version))
+		}
+	}
+	sort.Strings(parts)
+
+	h := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	fp := hex.EncodeToString(h[:8])
+	fingerprints[toLang] = fp
+	return fp
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+func TestTranslationCache_GetSet(t *testing.T) {
+	tc := NewTranslationCache(10, 0)
+	key := cacheKey("en", "zh-Hans", "Hello", false)
+
+	_, ok := tc.Get(key, "v1")
+	assert.False(t, ok)
+
+	tc.Set(key, "v1", "你好")
+	result, ok := tc.Get(key, "v1")
+	assert.True(t, ok)
+	assert.Equal(t, "你好", result)
+
+	stats := tc.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Size)
+}
+
+func TestTranslationCache_KeyIncludesHTMLFlag(t *testing.T) {
+	assert.NotEqual(t,
+		cacheKey("en", "zh-Hans", "<b>Hi</b>", false),
+		cacheKey("en", "zh-Hans", "<b>Hi</b>", true))
+}
+
+func TestTranslationCache_Eviction(t *testing.T) {
+	tc := NewTranslationCache(2, 0)
+	tc.Set("a", "", "A")
+	tc.Set("b", "", "B")
+
+	_, ok := tc.Get("a", "")
+	require.True(t, ok)
+
+	tc.Set("c", "", "C")
+
+	_, ok = tc.Get("b", "")
+	assert.False(t, ok, "least recently used entry should be evicted")
+	_, ok = tc.Get("a", "")
+	assert.True(t, ok)
+	_, ok = tc.Get("c", "")
+	assert.True(t, ok)
+	assert.Equal(t, uint64(1), tc.Stats().Evictions)
+}
+
+func TestTranslationCache_TTL(t *testing.T) {
+	tc := NewTranslationCache(10, 20*time.Millisecond)
+	tc.Set("a", "", "A")
+
+	time.Sleep(40 * time.Millisecond)
+
+	_, ok := tc.Get("a", "")
+	assert.False(t, ok)
+	assert.Equal(t, 0, tc.Len())
+}
+
+func TestTranslationCache_VersionMismatch(t *testing.T) {
+	tc := NewTranslationCache(10, 0)
+	tc.Set("a", "v1", "A")
+
+	_, ok := tc.Get("a", "v2")
+	assert.False(t, ok)
+	assert.Equal(t, 0, tc.Len())
+}
+
+func TestTranslationCache_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), cacheFileName)
+
+	tc := NewTranslationCache(10, time.Hour)
+	tc.Set("a", "v1", "A")
+	tc.Set("b", "v1", "B")
+	require.NoError(t, tc.Save(path))
+
+	loaded := NewTranslationCache(1, time.Hour)
+	require.NoError(t, loaded.Load(path))
+
+	assert.Equal(t, 1, loaded.Len())
+	result, ok := loaded.Get("b", "v1")
+	assert.True(t, ok, "most recently used entry should survive reload")
+	assert.Equal(t, "B", result)
+}
+
+func TestModelFingerprint(t *testing.T) {
+	original := models.GlobalRecords
+	defer func() { models.GlobalRecords = original }()
+
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "de", FileType: "model", Version: "1.0"},
+			{SourceLanguage: "fr", TargetLanguage: "en", FileType: "model", Version: "1.0"},
+			{SourceLanguage: "en", TargetLanguage: "ja", FileType: "model", Version: "1.0"},
+		},
+	}
+	before := modelFingerprint("de")
+	beforeJa := modelFingerprint("ja")
+
+	models.GlobalRecords = &models.RecordsData{
+		Data: []models.RecordItem{
+			{SourceLanguage: "en", TargetLanguage: "de", FileType: "model", Version: "1.1"},
+			{SourceLanguage: "fr", TargetLanguage: "en", FileType: "model", Version: "1.0"},
+			{SourceLanguage: "en", TargetLanguage: "ja", FileType: "model", Version: "1.0"},
+		},
+	}
+
+	assert.NotEqual(t, before, modelFingerprint("de"))
+	assert.Equal(t, beforeJa, modelFingerprint("ja"))
+}
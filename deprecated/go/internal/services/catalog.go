@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+
+	"github.com/xxnuo/MTranServer/internal/catalog"
+	"github.com/xxnuo/MTranServer/internal/mask"
+)
+
+// catalogMasker 保护翻译目录中的 printf、花括号和 ICU 占位符
+var catalogMasker = mask.New(mask.ICU, mask.Printf, mask.Braces)
+
+// TranslateCatalog 翻译 PO/XLIFF 目录中的条目，all 为 false 时只翻译未翻译的条目。
+// 占位符在翻译前替换为标记，翻译后还原。返回翻译的条目数
+func TranslateCatalog(ctx context.Context, fromLang, toLang string, c catalog.Catalog, all bool) (int, error) {
+	units := c.Units(all)
+	if len(units) == 0 {
+		return 0, nil
+	}
+
+	texts := make([]string, len(units))
+	isHTML := make([]bool, len(units))
+	for i, unit := range units {
+		texts[i] = unit.Text
+		isHTML[i] = unit.HTML
+	}
+
+	results, err := translateMasked(ctx, fromLang, toLang, catalogMasker, texts, isHTML)
+	if err != nil {
+		return 0, err
+	}
+
+	c.Apply(units, results)
+	return len(units), nil
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/sentence"
+)
+
+// MaxChunkLength 流式翻译时单个分段的最大字节数，超过时在句末继续切分
+const MaxChunkLength = 1000
+
+// Chunk 长文本切分出的一段，Start/End 为在原文中的字节偏移
+type Chunk struct {
+	Index int
+	Text  string
+	Start int
+	End   int
+}
+
+// SplitChunks 按行切分文本，超过 maxLen 的行再按 sentence.Split 的句子边界切分，
+// 同一行中相邻的句子合并为不超过 maxLen 的分段，单句超长时保持完整。lang 为源语言代码，用于识别缩写。
+// 空行和首尾空白不属于任何分段，调用方按偏移从原文保留
+func SplitChunks(text, lang string, maxLen int) []Chunk {
+	var chunks []Chunk
+	for _, span := range sentence.Split(text, lang, true) {
+		if n := len(chunks); n > 0 {
+			last := &chunks[n-1]
+			sameLine := !strings.Contains(text[last.End:span.Start], "\n")
+			if sameLine && (maxLen <= 0 || span.End-last.Start <= maxLen) {
+				last.End = span.End
+				last.Text = text[last.Start:last.End]
+				continue
+			}
+		}
+		chunks = append(chunks, Chunk{
+			Index: len(chunks),
+			Text:  text[span.Start:span.End],
+			Start: span.Start,
+			End:   span.End,
+		})
+	}
+	return chunks
+}
+
+// JoinChunks 用译文替换原文中各分段，保留分段之间的空白
+func JoinChunks(text string, chunks []Chunk, results []string) string {
+	spans := make([]sentence.Span, len(chunks))
+	for i, chunk := range chunks {
+		spans[i] = sentence.Span{Start: chunk.Start, End: chunk.End}
+	}
+	return sentence.Join(text, spans, results)
+}
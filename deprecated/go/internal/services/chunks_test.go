@@ -0,0 +1,70 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func chunkTexts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	return texts
+}
+
+func TestSplitChunksByLine(t *testing.T) {
+	text := "  First line.\n\nSecond line\r\n\tThird line  \n"
+	chunks := SplitChunks(text, "en", MaxChunkLength)
+
+	assert.Equal(t, []string{"First line.", "Second line", "Third line"}, chunkTexts(chunks))
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+}
+
+func TestSplitChunksLongLine(t *testing.T) {
+	text := "One two three. Four five six! Seven eight nine? Ten."
+	chunks := SplitChunks(text, "en", 20)
+
+	assert.Equal(t, []string{"One two three.", "Four five six!", "Seven eight nine?", "Ten."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+}
+
+func TestSplitChunksCJK(t *testing.T) {
+	text := "第一句话。第二句话！第三句话？"
+	chunks := SplitChunks(text, "en", 20)
+
+	assert.Equal(t, []string{"第一句话。", "第二句话！", "第三句话？"}, chunkTexts(chunks))
+}
+
+func TestSplitChunksKeepsDecimalsAndLongSentences(t *testing.T) {
+	long := "Word " + strings.Repeat("word ", 9) + "end."
+	chunks := SplitChunks("Pi is 3.14 exactly. "+long, "en", 30)
+
+	assert.Equal(t, []string{"Pi is 3.14 exactly.", long}, chunkTexts(chunks))
+}
+
+func TestSplitChunksAbbreviationsAndQuotes(t *testing.T) {
+	text := `He said "Stop." Then Mr. Smith left. Das ist z.B. gut.`
+	chunks := SplitChunks(text, "de", 25)
+
+	assert.Equal(t, []string{`He said "Stop."`, "Then Mr. Smith left.", "Das ist z.B. gut."}, chunkTexts(chunks))
+}
+
+func TestSplitChunksEmpty(t *testing.T) {
+	assert.Empty(t, SplitChunks("", "en", MaxChunkLength))
+	assert.Empty(t, SplitChunks(" \n\t\n", "en", MaxChunkLength))
+}
+
+func TestJoinChunks(t *testing.T) {
+	text := "  Hello.\n\nWorld.  \n"
+	chunks := SplitChunks(text, "en", MaxChunkLength)
+
+	assert.Equal(t, "  你好。\n\n世界。  \n", JoinChunks(text, chunks, []string{"你好。", "世界。"}))
+}
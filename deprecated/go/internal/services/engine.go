@@ -13,6 +13,7 @@ import (
 	"github.com/xxnuo/MTranServer/internal/config"
 	"github.com/xxnuo/MTranServer/internal/logger"
 	"github.com/xxnuo/MTranServer/internal/manager"
+	"github.com/xxnuo/MTranServer/internal/metrics"
 	"github.com/xxnuo/MTranServer/internal/models"
 	"github.com/xxnuo/MTranServer/internal/utils"
 )
@@ -45,6 +46,7 @@ func canCreateNewWorker() bool {
 		logger.Debug("Cannot determine available memory, allowing worker creation")
 		return true
 	}
+	metrics.AvailableMemoryBytes.Set(float64(availableMB * 1024 * 1024))
 
 	requiredMB := uint64(workerMemoryMB + reservedMemoryMB)
 	canCreate := availableMB >= requiredMB
@@ -66,7 +68,7 @@ func (ei *EngineInfo) resetIdleTimer() {
 	}
 
 	cfg := config.GetConfig()
-	timeout := time.Duration(cfg.WorkerIdleTimeout) * time.Second
+	timeout := time.Duration(cfg.GetWorkerIdleTimeout(ei.FromLang, ei.ToLang)) * time.Second
 
 	ei.stopTimer = time.AfterFunc(timeout, func() {
 		defer func() {
@@ -177,7 +179,7 @@ func getOrCreateSingleEngine(fromLang, toLang string) (*manager.Manager, error)
 		return nil, fmt.Errorf("failed to create work directory: %w", err)
 	}
 
-	numWorkers := cfg.WorkersPerLanguage
+	numWorkers := cfg.GetWorkersPerLanguage(fromLang, toLang)
 	if numWorkers <= 0 {
 		numWorkers = 1
 	}
@@ -194,11 +196,11 @@ func getOrCreateSingleEngine(fromLang, toLang string) (*manager.Manager, error)
 
 		args := manager.NewWorkerArgs()
 		args.Port = port
-		args.LogLevel = cfg.LogLevel
+		args.LogLevel = cfg.GetLogLevel()
 		args.WorkDir = langPairDir
 		args.ModelDir = langPairDir
 
-		m := manager.NewManager(args)
+		m := manager.NewManager(args, manager.WithMaxInFlight(cfg.GetWorkerMaxInFlight(fromLang, toLang)))
 
 		if err := m.Start(); err != nil {
 			for _, m := range managers {
@@ -275,6 +277,21 @@ func translateSegment(ctx context.Context, fromLang, toLang, text string, isHTML
 		return text, nil
 	}
 
+	results, err := translateProtected(ctx, []string{text}, isHTML, func(ctx context.Context, texts []string, isHTML bool) ([]string, error) {
+		result, err := translatePivotText(ctx, fromLang, toLang, texts[0], isHTML)
+		if err != nil {
+			return nil, err
+		}
+		return []string{result}, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return results[0], nil
+}
+
+// translatePivotText 翻译单个文本，没有直接模型时经英语中转
+func translatePivotText(ctx context.Context, fromLang, toLang, text string, isHTML bool) (string, error) {
 	if !needsPivotTranslation(fromLang, toLang) {
 		return translateSingleLanguageText(ctx, fromLang, toLang, text, isHTML)
 	}
@@ -294,68 +311,113 @@ func translateSegment(ctx context.Context, fromLang, toLang, text string, isHTML
 func TranslateWithPivot(ctx context.Context, fromLang, toLang, text string, isHTML bool) (string, error) {
 	logger.Debug("TranslateWithPivot: %s -> %s, text length: %d, isHTML: %v", fromLang, toLang, len(text), isHTML)
 
-	if fromLang != "auto" && len(text) <= 128 {
-		if fromLang == toLang {
-			return text, nil
-		}
-		return translateSegment(ctx, fromLang, toLang, text, isHTML)
+	cache := getTranslationCache()
+	if cache == nil {
+		result, _, err := translateWithPivot(ctx, fromLang, toLang, text, isHTML)
+		return result, err
 	}
 
-	segments := DetectMultipleLanguages(text)
-	if len(segments) <= 1 {
-		var effectiveFromLang string
-		if len(segments) == 1 {
-			effectiveFromLang = segments[0].Language
-		} else if fromLang == "auto" {
-			detected := DetectLanguage(text)
-			if detected == "" {
-				return "", fmt.Errorf("failed to detect source language")
-			}
-			effectiveFromLang = detected
-		} else {
-			effectiveFromLang = fromLang
-		}
+	key := cacheKey(fromLang, toLang, text, isHTML)
+	version := modelFingerprint(toLang)
+	if result, ok := cache.Get(key, version); ok {
+		logger.Debug("TranslateWithPivot: cache hit for %s -> %s", fromLang, toLang)
+		return result, nil
+	}
+
+	result, complete, err := translateWithPivot(ctx, fromLang, toLang, text, isHTML)
+	if err != nil {
+		return "", err
+	}
+	if complete {
+		cache.Set(key, version, result)
+	}
+	return result, nil
+}
+
+// translateWithPivot 执行实际翻译，complete 为 false 表示部分片段翻译失败并保留了原文
+func translateWithPivot(ctx context.Context, fromLang, toLang, text string, isHTML bool) (result string, complete bool, err error) {
+	effectiveFromLang, segments, err := resolveSourceLanguage(fromLang, text)
+	if err != nil {
+		return "", false, err
+	}
+	if len(segments) == 0 {
 		if effectiveFromLang == toLang {
-			return text, nil
+			return text, true, nil
 		}
-		return translateSegment(ctx, effectiveFromLang, toLang, text, isHTML)
+		result, err = translateSegment(ctx, effectiveFromLang, toLang, text, isHTML)
+		return result, err == nil, err
 	}
 
 	logger.Debug("Detected %d language segments", len(segments))
-	var result strings.Builder
+	var sb strings.Builder
 	lastEnd := 0
+	complete = true
 
 	for _, seg := range segments {
 		if seg.Start > lastEnd {
-			result.WriteString(text[lastEnd:seg.Start])
+			sb.WriteString(text[lastEnd:seg.Start])
 		}
 
 		if seg.Language == toLang {
-			result.WriteString(seg.Text)
+			sb.WriteString(seg.Text)
 		} else {
 			translated, err := translateSegment(ctx, seg.Language, toLang, seg.Text, isHTML)
 			if err != nil {
 				logger.Error("Failed to translate segment: %v", err)
-				result.WriteString(seg.Text)
+				sb.WriteString(seg.Text)
+				complete = false
 			} else {
-				result.WriteString(translated)
+				sb.WriteString(translated)
 			}
 		}
 		lastEnd = seg.End
 	}
 
 	if lastEnd < len(text) {
-		result.WriteString(text[lastEnd:])
+		sb.WriteString(text[lastEnd:])
 	}
 
-	return result.String(), nil
+	return sb.String(), complete, nil
 }
 
+// resolveSourceLanguage 确定文本的有效源语言。
+// 文本包含多种语言时返回各语言片段，此时 lang 为空
+func resolveSourceLanguage(fromLang, text string) (lang string, segments []TextSegment, err error) {
+	if fromLang != "auto" && len(text) <= 128 {
+		return fromLang, nil, nil
+	}
+
+	detected := DetectMultipleLanguages(text)
+	if len(detected) > 1 {
+		return "", detected, nil
+	}
+	if len(detected) == 1 {
+		return detected[0].Language, nil, nil
+	}
+	if fromLang == "auto" {
+		lang = DetectLanguage(text)
+		if lang == "" {
+			return "", nil, fmt.Errorf("failed to detect source language")
+		}
+		return lang, nil, nil
+	}
+	return fromLang, nil, nil
+}
+
+// translateSingleLanguageText 翻译单个文本，包含多个句子时按句子并行翻译
 func translateSingleLanguageText(ctx context.Context, fromLang, toLang, text string, isHTML bool) (string, error) {
+	if spans := sentenceSpans(ctx, fromLang, text, isHTML); spans != nil {
+		return translateSentences(ctx, fromLang, toLang, text, spans, isHTML)
+	}
+	return translateSingleLanguageRequest(ctx, fromLang, toLang, text, isHTML)
+}
+
+// translateSingleLanguageRequest 把文本作为一个请求发送给 worker，连接错误时换一个 worker 重试
+func translateSingleLanguageRequest(ctx context.Context, fromLang, toLang, text string, isHTML bool) (string, error) {
 	// 1. Get initial manager (will ensure pool is created)
 	m, err := getOrCreateSingleEngine(fromLang, toLang)
 	if err != nil {
-		logger.Error("translateSingleLanguageText: failed to get engine: %v", err)
+		logger.Error("translateSingleLanguageRequest: failed to get engine: %v", err)
 		return "", err
 	}
 
@@ -381,7 +443,7 @@ func translateSingleLanguageText(ctx context.Context, fromLang, toLang, text str
 			}
 		}
 
-		logger.Debug("translateSingleLanguageText: attempting translation (try %d/%d)", i+1, maxRetries)
+		logger.Debug("translateSingleLanguageRequest: attempting translation (try %d/%d)", i+1, maxRetries)
 		var result string
 		if isHTML {
 			result, err = m.TranslateHTML(ctx, text)
@@ -390,6 +452,7 @@ func translateSingleLanguageText(ctx context.Context, fromLang, toLang, text str
 		}
 
 		if err == nil {
+			recordTranslation(fromLang, toLang, text, nil)
 			return result, nil
 		}
 
@@ -407,6 +470,7 @@ func translateSingleLanguageText(ctx context.Context, fromLang, toLang, text str
 		}
 
 		// If it's not a connection error (e.g. invalid request), return immediately
+		recordTranslation(fromLang, toLang, text, err)
 		return "", err
 	}
 
@@ -416,8 +480,10 @@ func translateSingleLanguageText(ctx context.Context, fromLang, toLang, text str
 		logger.Warn("All translation attempts failed. Last error: %v. Trying segmented translation.", lastErr)
 		segResult, segErr := translateWithSegments(ctx, fromLang, toLang, text, isHTML)
 		if segErr != nil {
+			recordTranslation(fromLang, toLang, text, lastErr)
 			return "", lastErr // Return the main error
 		}
+		recordTranslation(fromLang, toLang, text, nil)
 		return segResult, nil
 	}
 
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xxnuo/MTranServer/internal/catalog"
+	"github.com/xxnuo/MTranServer/internal/i18n"
+	"github.com/xxnuo/MTranServer/internal/subtitle"
+)
+
+// File 解析后的待翻译文件，翻译后按原格式输出
+type File interface {
+	// Format 文件格式名称，如 srt、po、json
+	Format() string
+	ContentType() string
+	// Translate 翻译文件中的文本并写回，返回翻译的文本数
+	Translate(ctx context.Context, fromLang, toLang string) (int, error)
+	Marshal() ([]byte, error)
+}
+
+// FileOptions 整文件翻译的选项，只对对应类型的文件生效
+type FileOptions struct {
+	// Merge 字幕：合并跨字幕的句子翻译
+	Merge bool
+	// All 翻译目录：翻译全部条目，而不只是未翻译的条目
+	All bool
+	// Existing 资源文件：已有的目标语言文件，只翻译其中缺少的键
+	Existing string
+}
+
+// FileType 一类可整文件翻译的文件
+type FileType struct {
+	// Name 类型名称，用于错误信息和日志
+	Name string
+	// Unit 翻译文本的计数单位，用于日志
+	Unit string
+
+	parse       func(data string, opts FileOptions) (File, error)
+	parseFormat func(name string) (string, error)
+}
+
+var (
+	SubtitleFile = &FileType{
+		Name: "subtitle",
+		Unit: "cues",
+		parse: func(data string, opts FileOptions) (File, error) {
+			f, err := subtitle.Parse(data)
+			if err != nil {
+				return nil, err
+			}
+			return &subtitleFile{f: f, merge: opts.Merge}, nil
+		},
+		parseFormat: func(name string) (string, error) {
+			f, err := subtitle.ParseFormat(name)
+			return string(f), err
+		},
+	}
+
+	CatalogFile = &FileType{
+		Name: "catalog",
+		Unit: "entries",
+		parse: func(data string, opts FileOptions) (File, error) {
+			c, err := catalog.Parse(data)
+			if err != nil {
+				return nil, err
+			}
+			return &catalogFile{c: c, all: opts.All}, nil
+		},
+		parseFormat: func(name string) (string, error) {
+			f, err := catalog.ParseFormat(name)
+			return string(f), err
+		},
+	}
+
+	I18nFile = &FileType{
+		Name: "i18n file",
+		Unit: "strings",
+		parse: func(data string, opts FileOptions) (File, error) {
+			f, err := i18n.Parse(data)
+			if err != nil {
+				return nil, err
+			}
+			var existing *i18n.File
+			if opts.Existing != "" {
+				if existing, err = i18n.Parse(opts.Existing); err != nil {
+					return nil, fmt.Errorf("existing file: %w", err)
+				}
+			}
+			return &i18nFile{f: f, existing: existing}, nil
+		},
+		parseFormat: func(name string) (string, error) {
+			f, err := i18n.ParseFormat(name)
+			return string(f), err
+		},
+	}
+)
+
+// ParseFormat 解析格式名称或扩展名，返回规范的格式名称
+func (t *FileType) ParseFormat(name string) (string, error) {
+	return t.parseFormat(name)
+}
+
+// Parse 解析文件内容，format 不为空时要求内容是该格式
+func (t *FileType) Parse(data, format string, opts FileOptions) (File, error) {
+	f, err := t.parse(data, opts)
+	if err != nil {
+		return nil, err
+	}
+	if format != "" {
+		want, err := t.ParseFormat(format)
+		if err != nil {
+			return nil, err
+		}
+		if want != f.Format() {
+			return nil, fmt.Errorf("content is not %s", want)
+		}
+	}
+	return f, nil
+}
+
+type subtitleFile struct {
+	f     *subtitle.File
+	merge bool
+}
+
+func (s *subtitleFile) Format() string      { return string(s.f.Format) }
+func (s *subtitleFile) ContentType() string { return s.f.Format.ContentType() }
+
+func (s *subtitleFile) Translate(ctx context.Context, fromLang, toLang string) (int, error) {
+	return TranslateSubtitle(ctx, fromLang, toLang, s.f, s.merge)
+}
+
+func (s *subtitleFile) Marshal() ([]byte, error) { return []byte(s.f.String()), nil }
+
+type catalogFile struct {
+	c   catalog.Catalog
+	all bool
+}
+
+func (c *catalogFile) Format() string      { return string(c.c.Format()) }
+func (c *catalogFile) ContentType() string { return c.c.Format().ContentType() }
+
+func (c *catalogFile) Translate(ctx context.Context, fromLang, toLang string) (int, error) {
+	return TranslateCatalog(ctx, fromLang, toLang, c.c, c.all)
+}
+
+func (c *catalogFile) Marshal() ([]byte, error) { return []byte(c.c.String()), nil }
+
+type i18nFile struct {
+	f        *i18n.File
+	existing *i18n.File
+}
+
+func (i *i18nFile) Format() string      { return string(i.f.Format) }
+func (i *i18nFile) ContentType() string { return i.f.Format.ContentType() }
+
+func (i *i18nFile) Translate(ctx context.Context, fromLang, toLang string) (int, error) {
+	return TranslateI18n(ctx, fromLang, toLang, i.f, i.existing)
+}
+
+func (i *i18nFile) Marshal() ([]byte, error) { return i.f.Marshal() }
@@ -0,0 +1,29 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileTypeParse(t *testing.T) {
+	srt := "\ufeff1\r\n00:00:01,000 --> 00:00:02,000\r\nHello\r\n"
+	f, err := SubtitleFile.Parse(srt, ".SRT", FileOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "srt", f.Format())
+	assert.Equal(t, "application/x-subrip; charset=utf-8", f.ContentType())
+
+	data, err := f.Marshal()
+	require.NoError(t, err)
+	assert.Equal(t, srt, string(data))
+
+	_, err = SubtitleFile.Parse(srt, "webvtt", FileOptions{})
+	assert.EqualError(t, err, "content is not vtt")
+
+	_, err = CatalogFile.Parse("msgid \"a\"\nmsgstr \"\"\n", "json", FileOptions{})
+	assert.EqualError(t, err, "unsupported catalog format: json")
+
+	_, err = I18nFile.Parse(`{"a": "Apple"}`, "", FileOptions{Existing: `{"a": }`})
+	assert.ErrorContains(t, err, "existing file: invalid JSON")
+}
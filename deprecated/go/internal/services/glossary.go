@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/glossary"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/mask"
+)
+
+const glossariesFileName = "glossaries.json"
+
+var (
+	glossaryStore *glossary.Store
+	glossaryOnce  sync.Once
+)
+
+// Glossaries 返回保存在配置目录中的术语表存储
+func Glossaries() *glossary.Store {
+	glossaryOnce.Do(func() {
+		cfg := config.GetConfig()
+		glossaryStore = glossary.NewStore(filepath.Join(cfg.ConfigDir, glossariesFileName))
+	})
+	return glossaryStore
+}
+
+// InitGlossaries 从配置目录恢复术语表
+func InitGlossaries() {
+	store := Glossaries()
+	if err := store.Load(); err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("Failed to load glossaries: %v", err)
+		}
+		return
+	}
+	logger.Debug("Loaded %d glossaries", len(store.List()))
+}
+
+// translateGlossary 批量翻译并强制使用术语表中的译文：源词条先替换为标记，翻译后还原为对应的目标词条。
+// g 为 nil 时直接翻译，errs[i] 为第 i 个文本的错误
+func translateGlossary(ctx context.Context, fromLang, toLang string, g *glossary.Glossary, texts []string, isHTML []bool) ([]string, []error) {
+	if g == nil {
+		return translateManyByMode(ctx, fromLang, toLang, texts, isHTML)
+	}
+
+	masker := mask.New(g.Match)
+	masked := make([]mask.Masked, len(texts))
+	maskedTexts := make([]string, len(texts))
+	maskedHTML := make([]bool, len(texts))
+	for i, text := range texts {
+		masked[i] = masker.Mask(text, isHTML[i]).Replace(g.Target)
+		maskedTexts[i] = masked[i].Text
+		maskedHTML[i] = masked[i].HTML
+	}
+
+	results, errs := translateManyByMode(ctx, fromLang, toLang, maskedTexts, maskedHTML)
+	for i := range results {
+		if errs[i] == nil {
+			results[i] = masked[i].Restore(results[i])
+		}
+	}
+	return results, errs
+}
+
+// TranslateWithGlossary 使用术语表翻译单个文本，g 为 nil 时等同于 TranslateWithPivot
+func TranslateWithGlossary(ctx context.Context, fromLang, toLang, text string, isHTML bool, g *glossary.Glossary) (string, error) {
+	if g == nil {
+		return TranslateWithPivot(ctx, fromLang, toLang, text, isHTML)
+	}
+	results, errs := translateGlossary(ctx, fromLang, toLang, g, []string{text}, []bool{isHTML})
+	if errs[0] != nil {
+		return "", errs[0]
+	}
+	return results[0], nil
+}
+
+// TranslateManyWithGlossary 使用术语表并行翻译多个文本，g 为 nil 时等同于 TranslateMany
+func TranslateManyWithGlossary(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool, g *glossary.Glossary) ([]string, []error) {
+	if g == nil {
+		return TranslateMany(ctx, fromLang, toLang, texts, isHTML)
+	}
+	modes := make([]bool, len(texts))
+	for i := range modes {
+		modes[i] = isHTML
+	}
+	return translateGlossary(ctx, fromLang, toLang, g, texts, modes)
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+
+	"github.com/xxnuo/MTranServer/internal/i18n"
+	"github.com/xxnuo/MTranServer/internal/mask"
+	"github.com/xxnuo/MTranServer/internal/utils"
+)
+
+// i18nMasker 保护资源文件中的插值、ICU 参数、消息引用和 HTML 标签
+var i18nMasker = mask.New(mask.ICU, mask.Braces, mask.Linked, mask.HTMLTags)
+
+// TranslateI18n 翻译 JSON/YAML 资源文件中的字符串值。existing 为已有的目标语言文件时，
+// 只翻译其中缺少的键，已有的译文原样保留。返回翻译的字符串数
+func TranslateI18n(ctx context.Context, fromLang, toLang string, f *i18n.File, existing *i18n.File) (int, error) {
+	// Rails 风格的文件以语言代码为根键，源语言的根键改为目标语言
+	if key, ok := f.RootKey(); ok {
+		targetKey, hasTarget := "", false
+		if existing != nil {
+			targetKey, hasTarget = existing.RootKey()
+		}
+		if hasTarget && utils.NormalizeLanguageCode(targetKey) == toLang {
+			existing.SetLocaleRoot(targetKey)
+			f.SetLocaleRoot(targetKey)
+		} else if fromLang != "auto" && utils.NormalizeLanguageCode(key) == fromLang {
+			f.SetLocaleRoot(toLang)
+		}
+	}
+
+	if existing != nil {
+		f.Merge(existing)
+	}
+
+	units := f.Units()
+	if len(units) == 0 {
+		return 0, nil
+	}
+
+	texts := make([]string, len(units))
+	for i, unit := range units {
+		texts[i] = unit.Text
+	}
+
+	results, err := translateMasked(ctx, fromLang, toLang, i18nMasker, texts, make([]bool, len(units)))
+	if err != nil {
+		return 0, err
+	}
+
+	f.Apply(units, results)
+	return len(units), nil
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"sort"
+
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+// SupportedTargets 返回每个源语言可翻译到的目标语言（已排序），
+// 包括经英语中转可达的语言对。记录未初始化时返回 nil
+func SupportedTargets() map[string][]string {
+	if models.GlobalRecords == nil {
+		return nil
+	}
+
+	direct := make(map[string]map[string]bool)
+	for _, record := range models.GlobalRecords.Data {
+		if direct[record.SourceLanguage] == nil {
+			direct[record.SourceLanguage] = make(map[string]bool)
+		}
+		direct[record.SourceLanguage][record.TargetLanguage] = true
+		if direct[record.TargetLanguage] == nil {
+			direct[record.TargetLanguage] = make(map[string]bool)
+		}
+	}
+
+	result := make(map[string][]string, len(direct))
+	for source, targets := range direct {
+		reachable := make(map[string]bool, len(targets))
+		for target := range targets {
+			reachable[target] = true
+		}
+		if source != "en" && targets["en"] {
+			for target := range direct["en"] {
+				if target != source {
+					reachable[target] = true
+				}
+			}
+		}
+
+		list := make([]string, 0, len(reachable))
+		for target := range reachable {
+			list = append(list, target)
+		}
+		sort.Strings(list)
+		result[source] = list
+	}
+	return result
+}
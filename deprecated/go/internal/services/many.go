@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+)
+
+// TranslateMany 把多个文本分块并行分配到引擎池的 worker 翻译，并发数不超过池中的 worker 数量。
+// 结果顺序与输入一致，errs[i] 为第 i 个文本的错误；某一块整体失败时逐条重试以确定失败的文本
+func TranslateMany(ctx context.Context, fromLang, toLang string, texts []string, isHTML bool) ([]string, []error) {
+	workers := poolSize(fromLang, toLang)
+	logger.Debug("TranslateMany: %s -> %s, count: %d, workers: %d", fromLang, toLang, len(texts), workers)
+
+	batch := func(ctx context.Context, texts []string) ([]string, error) {
+		return TranslateBatch(ctx, fromLang, toLang, texts, isHTML)
+	}
+	single := func(ctx context.Context, text string) (string, error) {
+		return TranslateWithPivot(ctx, fromLang, toLang, text, isHTML)
+	}
+	return translateMany(ctx, texts, workers, batch, single)
+}
+
+// FirstError 返回 TranslateMany 的第一个错误并注明文本序号，全部成功时返回 nil
+func FirstError(errs []error) error {
+	for i, err := range errs {
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// poolSize 返回语言对引擎池的 worker 数量，引擎尚未创建时按配置计算。需要中转时取第一段的引擎池
+func poolSize(fromLang, toLang string) int {
+	if fromLang != "auto" && needsPivotTranslation(fromLang, toLang) {
+		toLang = "en"
+	}
+	if info := getEngineInfo(fromLang, toLang); info != nil && len(info.Managers) > 0 {
+		return len(info.Managers)
+	}
+	if n := config.GetConfig().GetWorkersPerLanguage(fromLang, toLang); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// translateMany 把 texts 平均分为最多 workers 块并行调用 batch，每块不超过 maxBatchItems 个文本
+func translateMany(ctx context.Context, texts []string, workers int,
+	batch func(context.Context, []string) ([]string, error),
+	single func(context.Context, string) (string, error)) ([]string, []error) {
+
+	results := make([]string, len(texts))
+	errs := make([]error, len(texts))
+	if len(texts) == 0 {
+		return results, errs
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	size := (len(texts) + workers - 1) / workers
+	if size > maxBatchItems {
+		size = maxBatchItems
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(texts); start += size {
+		end := min(start+size, len(texts))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				for i := start; i < end; i++ {
+					errs[i] = ctx.Err()
+				}
+				return
+			}
+			defer func() { <-sem }()
+
+			translated, err := batch(ctx, texts[start:end])
+			if err == nil {
+				copy(results[start:end], translated)
+				return
+			}
+			if end-start == 1 || ctx.Err() != nil {
+				for i := start; i < end; i++ {
+					errs[i] = err
+				}
+				return
+			}
+
+			logger.Warn("Batch of %d items failed: %v, translating items individually", end-start, err)
+			for i := start; i < end; i++ {
+				results[i], errs[i] = single(ctx, texts[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results, errs
+}
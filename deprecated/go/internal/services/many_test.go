@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslateManyOrderAndParallelism(t *testing.T) {
+	texts := make([]string, 200)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("text %d", i)
+	}
+
+	var running, peak, calls atomic.Int32
+	batch := func(ctx context.Context, texts []string) ([]string, error) {
+		calls.Add(1)
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		assert.LessOrEqual(t, len(texts), maxBatchItems)
+		time.Sleep(10 * time.Millisecond)
+
+		out := make([]string, len(texts))
+		for i, text := range texts {
+			out[i] = strings.ToUpper(text)
+		}
+		return out, nil
+	}
+	single := func(ctx context.Context, text string) (string, error) {
+		t.Fatal("single should not be called")
+		return "", nil
+	}
+
+	results, errs := translateMany(context.Background(), texts, 3, batch, single)
+	for i := range texts {
+		assert.Equal(t, strings.ToUpper(texts[i]), results[i])
+		assert.NoError(t, errs[i])
+	}
+	assert.NoError(t, FirstError(errs))
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestTranslateManyPerItemErrors(t *testing.T) {
+	errBad := errors.New("bad item")
+	batch := func(ctx context.Context, texts []string) ([]string, error) {
+		for _, text := range texts {
+			if text == "bad" {
+				return nil, errBad
+			}
+		}
+		return texts, nil
+	}
+	single := func(ctx context.Context, text string) (string, error) {
+		if text == "bad" {
+			return "", errBad
+		}
+		return "[" + text + "]", nil
+	}
+
+	results, errs := translateMany(context.Background(), []string{"a", "bad", "c", "d"}, 2, batch, single)
+	assert.Equal(t, []string{"[a]", "", "c", "d"}, results)
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], errBad)
+	assert.NoError(t, errs[2])
+
+	err := FirstError(errs)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errBad)
+	assert.Contains(t, err.Error(), "item 1")
+}
+
+func TestTranslateManyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	batch := func(ctx context.Context, texts []string) ([]string, error) {
+		return nil, ctx.Err()
+	}
+	single := func(ctx context.Context, text string) (string, error) {
+		t.Fatal("single should not be called after cancellation")
+		return "", nil
+	}
+
+	_, errs := translateMany(ctx, []string{"a", "b", "c"}, 1, batch, single)
+	for _, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled)
+	}
+
+	results, errs := translateMany(context.Background(), nil, 4, batch, single)
+	assert.Empty(t, results)
+	assert.NoError(t, FirstError(errs))
+}
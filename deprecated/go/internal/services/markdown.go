@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+
+	"github.com/xxnuo/MTranServer/internal/glossary"
+	"github.com/xxnuo/MTranServer/internal/markdown"
+)
+
+// TranslateMarkdown 翻译 Markdown 文档中的正文，代码、链接地址、HTML 块和 front matter 保持不变，
+// 其余内容按原格式输出。g 不为 nil 时正文中的术语使用术语表中的译文
+func TranslateMarkdown(ctx context.Context, fromLang, toLang, text string, g *glossary.Glossary) (string, error) {
+	doc := markdown.Parse(text)
+	units := doc.Units()
+	if len(units) == 0 {
+		return text, nil
+	}
+
+	texts := make([]string, len(units))
+	isHTML := make([]bool, len(units))
+	for i, unit := range units {
+		texts[i] = unit.Text
+		isHTML[i] = unit.HTML
+	}
+
+	results, errs := translateGlossary(ctx, fromLang, toLang, g, texts, isHTML)
+	if err := FirstError(errs); err != nil {
+		return "", err
+	}
+
+	doc.Apply(units, results)
+	return doc.String(), nil
+}
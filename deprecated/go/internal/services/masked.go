@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+
+	"github.com/xxnuo/MTranServer/internal/mask"
+)
+
+// translateByMode 按 isHTML 把文本分为纯文本和 HTML 两批调用 TranslateMany，结果按原顺序返回
+func translateByMode(ctx context.Context, fromLang, toLang string, texts []string, isHTML []bool) ([]string, error) {
+	results, errs := translateManyByMode(ctx, fromLang, toLang, texts, isHTML)
+	if err := FirstError(errs); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// translateManyByMode 与 translateByMode 相同，但分别返回每个文本的错误
+func translateManyByMode(ctx context.Context, fromLang, toLang string, texts []string, isHTML []bool) ([]string, []error) {
+	results := make([]string, len(texts))
+	errs := make([]error, len(texts))
+	for _, html := range []bool{false, true} {
+		var indexes []int
+		var batch []string
+		for i, text := range texts {
+			if isHTML[i] == html {
+				indexes = append(indexes, i)
+				batch = append(batch, text)
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		translated, batchErrs := TranslateMany(ctx, fromLang, toLang, batch, html)
+		for j, idx := range indexes {
+			results[idx] = translated[j]
+			errs[idx] = batchErrs[j]
+		}
+	}
+	return results, errs
+}
+
+// translateMasked 用 masker 保护文本中的占位符后批量翻译，译文中的标记还原为原始片段。
+// isHTML 为每个文本原本的翻译模式，替换后的文本按 HTML 翻译
+func translateMasked(ctx context.Context, fromLang, toLang string, masker *mask.Masker, texts []string, isHTML []bool) ([]string, error) {
+	masked := make([]mask.Masked, len(texts))
+	maskedTexts := make([]string, len(texts))
+	maskedHTML := make([]bool, len(texts))
+	for i, text := range texts {
+		masked[i] = masker.Mask(text, isHTML[i])
+		maskedTexts[i] = masked[i].Text
+		maskedHTML[i] = masked[i].HTML
+	}
+
+	results, err := translateByMode(ctx, fromLang, toLang, maskedTexts, maskedHTML)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i] = masked[i].Restore(results[i])
+	}
+	return results, nil
+}
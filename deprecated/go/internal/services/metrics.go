@@ -0,0 +1,86 @@
+package services
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/xxnuo/MTranServer/internal/metrics"
+)
+
+var (
+	enginesLoadedDesc = prometheus.NewDesc(
+		"mtranserver_engines_loaded",
+		"Number of language pair engines currently loaded.",
+		nil, nil)
+	enginePoolWorkersDesc = prometheus.NewDesc(
+		"mtranserver_engine_pool_workers",
+		"Number of workers in each language pair engine pool.",
+		[]string{"pair"}, nil)
+	workerStateDesc = prometheus.NewDesc(
+		"mtranserver_worker_state",
+		"Current state of each worker, 1 for the active state.",
+		[]string{"pair", "port", "state"}, nil)
+	cacheEntriesDesc = prometheus.NewDesc(
+		"mtranserver_cache_entries",
+		"Number of entries in the translation cache.",
+		nil, nil)
+	cacheHitsDesc = prometheus.NewDesc(
+		"mtranserver_cache_hits_total",
+		"Total number of translation cache hits.",
+		nil, nil)
+	cacheMissesDesc = prometheus.NewDesc(
+		"mtranserver_cache_misses_total",
+		"Total number of translation cache misses.",
+		nil, nil)
+)
+
+// engineCollector 在抓取时读取引擎池和缓存状态
+type engineCollector struct{}
+
+func init() {
+	metrics.Registry.MustRegister(engineCollector{})
+}
+
+func (engineCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- enginesLoadedDesc
+	ch <- enginePoolWorkersDesc
+	ch <- workerStateDesc
+	ch <- cacheEntriesDesc
+	ch <- cacheHitsDesc
+	ch <- cacheMissesDesc
+}
+
+func (engineCollector) Collect(ch chan<- prometheus.Metric) {
+	engMu.RLock()
+	ch <- prometheus.MustNewConstMetric(enginesLoadedDesc, prometheus.GaugeValue, float64(len(engines)))
+	for key, info := range engines {
+		ch <- prometheus.MustNewConstMetric(enginePoolWorkersDesc, prometheus.GaugeValue, float64(len(info.Managers)), key)
+		for _, m := range info.Managers {
+			if m == nil {
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(workerStateDesc, prometheus.GaugeValue, 1,
+				key, strconv.Itoa(m.Port()), m.Status())
+		}
+	}
+	engMu.RUnlock()
+
+	if tc := getTranslationCache(); tc != nil {
+		stats := tc.Stats()
+		ch <- prometheus.MustNewConstMetric(cacheEntriesDesc, prometheus.GaugeValue, float64(stats.Size))
+		ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+		ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	}
+}
+
+func recordTranslation(fromLang, toLang, text string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	metrics.TranslationsTotal.WithLabelValues(fromLang, toLang, result).Inc()
+	if err == nil {
+		metrics.TranslatedCharactersTotal.WithLabelValues(fromLang, toLang).Add(float64(utf8.RuneCountInString(text)))
+	}
+}
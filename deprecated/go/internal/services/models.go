@@ -0,0 +1,178 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/models"
+)
+
+const (
+	DownloadStateRunning   = "running"
+	DownloadStateCompleted = "completed"
+	DownloadStateFailed    = "failed"
+)
+
+var (
+	ErrOfflineMode      = errors.New("offline mode enabled, model download is disabled")
+	ErrModelDownloading = errors.New("model is being downloaded")
+)
+
+// ModelDownload 后台模型下载任务的状态快照
+type ModelDownload struct {
+	From       string     `json:"from"`
+	To         string     `json:"to"`
+	State      string     `json:"state"`
+	Downloaded int64      `json:"downloaded"`
+	Total      int64      `json:"total"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+type downloadJob struct {
+	mu         sync.Mutex
+	from       string
+	to         string
+	state      string
+	downloaded atomic.Int64
+	total      int64
+	err        error
+	startedAt  time.Time
+	finishedAt time.Time
+}
+
+var (
+	downloads   = make(map[string]*downloadJob)
+	downloadsMu sync.Mutex
+)
+
+func (j *downloadJob) snapshot() ModelDownload {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	d := ModelDownload{
+		From:       j.from,
+		To:         j.to,
+		State:      j.state,
+		Downloaded: j.downloaded.Load(),
+		Total:      j.total,
+		StartedAt:  j.startedAt,
+	}
+	if j.err != nil {
+		d.Error = j.err.Error()
+	}
+	if !j.finishedAt.IsZero() {
+		finishedAt := j.finishedAt
+		d.FinishedAt = &finishedAt
+	}
+	return d
+}
+
+// TrackProgress 实现 getter.ProgressTracker，累计所有文件已下载的字节数
+func (j *downloadJob) TrackProgress(src string, currentSize, totalSize int64, stream io.ReadCloser) io.ReadCloser {
+	j.downloaded.Add(currentSize)
+	return &progressReader{ReadCloser: stream, job: j}
+}
+
+type progressReader struct {
+	io.ReadCloser
+	job *downloadJob
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.job.downloaded.Add(int64(n))
+	return n, err
+}
+
+// StartModelDownload 在后台下载指定语言对的模型，同一语言对正在下载时返回现有任务
+func StartModelDownload(fromLang, toLang string) (ModelDownload, error) {
+	if config.GetConfig().EnableOfflineMode {
+		return ModelDownload{}, ErrOfflineMode
+	}
+
+	records, err := models.LatestRecords(fromLang, toLang)
+	if err != nil {
+		return ModelDownload{}, err
+	}
+
+	key := fmt.Sprintf("%s-%s", fromLang, toLang)
+
+	downloadsMu.Lock()
+	defer downloadsMu.Unlock()
+
+	if job, ok := downloads[key]; ok {
+		if d := job.snapshot(); d.State == DownloadStateRunning {
+			return d, nil
+		}
+	}
+
+	job := &downloadJob{
+		from:      fromLang,
+		to:        toLang,
+		state:     DownloadStateRunning,
+		startedAt: time.Now(),
+	}
+	for _, record := range records {
+		job.total += record.Attachment.Size
+	}
+	downloads[key] = job
+
+	go func() {
+		logger.Info("Background download started for %s -> %s", fromLang, toLang)
+		err := models.DownloadModelWithProgress(toLang, fromLang, "", job)
+
+		job.mu.Lock()
+		job.finishedAt = time.Now()
+		if err != nil {
+			job.state = DownloadStateFailed
+			job.err = err
+		} else {
+			job.state = DownloadStateCompleted
+			job.downloaded.Store(job.total)
+		}
+		job.mu.Unlock()
+
+		if err != nil {
+			logger.Error("Background download failed for %s -> %s: %v", fromLang, toLang, err)
+		} else {
+			logger.Info("Background download completed for %s -> %s", fromLang, toLang)
+		}
+	}()
+
+	return job.snapshot(), nil
+}
+
+// GetModelDownload 返回指定语言对最近一次后台下载任务的状态
+func GetModelDownload(fromLang, toLang string) (ModelDownload, bool) {
+	key := fmt.Sprintf("%s-%s", fromLang, toLang)
+
+	downloadsMu.Lock()
+	job, ok := downloads[key]
+	downloadsMu.Unlock()
+
+	if !ok {
+		return ModelDownload{}, false
+	}
+	return job.snapshot(), true
+}
+
+// DeleteModel 停止使用该模型的引擎后删除本地模型文件
+func DeleteModel(fromLang, toLang string) error {
+	if d, ok := GetModelDownload(fromLang, toLang); ok && d.State == DownloadStateRunning {
+		return ErrModelDownloading
+	}
+
+	if err := StopEngine(fromLang, toLang); err != nil && !errors.Is(err, ErrEngineNotFound) {
+		return err
+	}
+
+	return models.DeleteModel(fromLang, toLang)
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/mask"
+)
+
+// protectMatchers 配置中内置保护规则对应的 Matcher
+var protectMatchers = map[string]mask.Matcher{
+	"url":        mask.URL,
+	"email":      mask.Email,
+	"identifier": mask.Identifier,
+	"number":     mask.Number,
+	"template":   mask.Braces,
+	"printf":     mask.Printf,
+}
+
+var (
+	protectMu   sync.Mutex
+	protectKey  string
+	protectText *mask.Masker
+	protectHTML *mask.Masker
+)
+
+// protectMasker 返回按当前配置构建的保护层，配置热更新后重新构建。
+// HTML 文本额外保护 translate="no" 和 class="notranslate" 的元素
+func protectMasker(isHTML bool) *mask.Masker {
+	rules, patterns := config.GetConfig().GetProtect()
+	key := strings.Join(rules, ",") + "\x00" + strings.Join(patterns, "\x00")
+
+	protectMu.Lock()
+	defer protectMu.Unlock()
+
+	if protectText == nil || key != protectKey {
+		var matchers []mask.Matcher
+		for _, rule := range rules {
+			if match, ok := protectMatchers[rule]; ok {
+				matchers = append(matchers, match)
+			}
+		}
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				logger.Warn("Ignoring invalid protect pattern %q: %v", pattern, err)
+				continue
+			}
+			matchers = append(matchers, mask.Regexp(re))
+		}
+
+		protectKey = key
+		protectText = mask.New(matchers...)
+		protectHTML = mask.New(append([]mask.Matcher{mask.NoTranslate}, matchers...)...)
+	}
+
+	if isHTML {
+		return protectHTML
+	}
+	return protectText
+}
+
+// translateProtected 用保护层替换不应翻译的片段后调用 translate，译文中的标记还原为原文，
+// 模型丢失的标记按原文位置插回。替换后的文本按 HTML 翻译
+func translateProtected(ctx context.Context, texts []string, isHTML bool, translate func(ctx context.Context, texts []string, isHTML bool) ([]string, error)) ([]string, error) {
+	masker := protectMasker(isHTML)
+	masked := make([]mask.Masked, len(texts))
+	for i, text := range texts {
+		masked[i] = masker.Mask(text, isHTML)
+	}
+
+	results := make([]string, len(texts))
+	for _, html := range []bool{isHTML, !isHTML} {
+		var indexes []int
+		var batch []string
+		for i, m := range masked {
+			if m.HTML == html {
+				indexes = append(indexes, i)
+				batch = append(batch, m.Text)
+			}
+		}
+		if len(batch) == 0 {
+			continue
+		}
+
+		translated, err := translate(ctx, batch, html)
+		if err != nil {
+			return nil, err
+		}
+		for j, idx := range indexes {
+			results[idx] = masked[idx].Restore(translated[j])
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xxnuo/MTranServer/internal/config"
+)
+
+func TestTranslateProtected(t *testing.T) {
+	cfg := config.GetConfig()
+	oldProtect, oldPatterns := cfg.Protect, cfg.ProtectPatterns
+	cfg.Protect, cfg.ProtectPatterns = "url,email", []string{`TICKET-\d+`}
+	t.Cleanup(func() { cfg.Protect, cfg.ProtectPatterns = oldProtect, oldPatterns })
+
+	// 假翻译会改写所有单词，受保护的片段不应被修改
+	replacer := strings.NewReplacer("see", "siehe", "mail", "Mail an", "plain text", "Klartext", "fix", "behebe",
+		"Use", "Nutze", " or ", " oder ", "example", "Beispiel", "TICKET", "Ticket", "Acme", "ACME", "Beta", "BETA")
+	var calls []bool
+	translate := func(ctx context.Context, texts []string, isHTML bool) ([]string, error) {
+		calls = append(calls, isHTML)
+		results := make([]string, len(texts))
+		for i, text := range texts {
+			results[i] = replacer.Replace(text)
+		}
+		return results, nil
+	}
+
+	results, err := translateProtected(context.Background(), []string{
+		"see https://example.com/Path & mail a@example.com",
+		"plain text",
+		"fix TICKET-42",
+	}, false, translate)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"siehe https://example.com/Path & Mail an a@example.com",
+		"Klartext",
+		"behebe TICKET-42",
+	}, results)
+	assert.Equal(t, []bool{false, true}, calls)
+
+	calls = nil
+	results, err = translateProtected(context.Background(), []string{
+		`<p>Use <span translate="no">Acme</span> or <b class="notranslate">Beta</b></p>`,
+	}, true, translate)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`<p>Nutze <span translate="no">Acme</span> oder <b class="notranslate">Beta</b></p>`}, results)
+	assert.Equal(t, []bool{true}, calls)
+}
+
+func TestTranslateProtectedDefaultConfig(t *testing.T) {
+	cfg := config.GetConfig()
+	oldProtect, oldPatterns := cfg.Protect, cfg.ProtectPatterns
+	cfg.Protect, cfg.ProtectPatterns = config.DefaultProtect, nil
+	t.Cleanup(func() { cfg.Protect, cfg.ProtectPatterns = oldProtect, oldPatterns })
+
+	texts := []string{"See https://example.com, 3.5 km away, in fooBar & {name}."}
+	var got []string
+	var html []bool
+	translate := func(ctx context.Context, texts []string, isHTML bool) ([]string, error) {
+		got = append(got, texts...)
+		html = append(html, isHTML)
+		return texts, nil
+	}
+
+	results, err := translateProtected(context.Background(), texts, false, translate)
+	require.NoError(t, err)
+	assert.Equal(t, texts, got)
+	assert.Equal(t, []bool{false}, html)
+	assert.Equal(t, texts, results)
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xxnuo/MTranServer/internal/config"
+	"github.com/xxnuo/MTranServer/internal/logger"
+	"github.com/xxnuo/MTranServer/internal/mask"
+	"github.com/xxnuo/MTranServer/internal/sentence"
+)
+
+// 分句模式，取值与 DeepL 的 split_sentences 相同
+const (
+	SplitSentencesOff        = "0"
+	SplitSentencesOn         = "1"
+	SplitSentencesNoNewlines = "nonewlines"
+)
+
+type splitSentencesKey struct{}
+
+// ValidSplitSentences 判断分句模式是否合法，空字符串表示使用默认值
+func ValidSplitSentences(mode string) bool {
+	switch mode {
+	case "", SplitSentencesOff, SplitSentencesOn, SplitSentencesNoNewlines:
+		return true
+	}
+	return false
+}
+
+// WithSplitSentences 返回使用指定分句模式的 context，mode 为空时使用配置中的默认值
+func WithSplitSentences(ctx context.Context, mode string) context.Context {
+	if mode == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, splitSentencesKey{}, mode)
+}
+
+func splitSentencesMode(ctx context.Context) string {
+	if mode, ok := ctx.Value(splitSentencesKey{}).(string); ok {
+		return mode
+	}
+	return config.GetConfig().GetSplitSentences()
+}
+
+// sentenceSpans 返回文本中的句子。不分句、只有一句或文本包含 HTML 标签（遮盖标记除外）时返回 nil
+func sentenceSpans(ctx context.Context, fromLang, text string, isHTML bool) []sentence.Span {
+	mode := splitSentencesMode(ctx)
+	if mode == SplitSentencesOff || isHTML && !mask.OnlySentinels(text) {
+		return nil
+	}
+
+	spans := sentence.Split(text, fromLang, mode != SplitSentencesNoNewlines)
+	if len(spans) <= 1 {
+		return nil
+	}
+	return spans
+}
+
+// translateSentences 把各个句子并行分配到引擎池的 worker 翻译，按原文的空白拼接。
+// 个别句子翻译失败时保留原文，全部失败时返回错误
+func translateSentences(ctx context.Context, fromLang, toLang, text string, spans []sentence.Span, isHTML bool) (string, error) {
+	parallel := config.GetConfig().GetWorkerMaxInFlight(fromLang, toLang)
+	if info := getEngineInfo(fromLang, toLang); info != nil && len(info.Managers) > 0 {
+		parallel *= len(info.Managers)
+	}
+	logger.Debug("translateSentences: %s -> %s, %d sentences, parallel: %d", fromLang, toLang, len(spans), parallel)
+
+	results := make([]string, len(spans))
+	errs := make([]error, len(spans))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for i, span := range spans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-sem }()
+			results[i], errs[i] = translateSingleLanguageRequest(ctx, fromLang, toLang, text[span.Start:span.End], isHTML)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var firstErr error
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			results[i] = text[spans[i].Start:spans[i].End]
+		}
+	}
+	if failed == len(spans) {
+		return "", firstErr
+	}
+	if failed > 0 {
+		logger.Warn("%d of %d sentences failed to translate (%s -> %s), keeping source text: %v", failed, len(spans), fromLang, toLang, firstErr)
+	}
+	return sentence.Join(text, spans, results), nil
+}
+
+// splitSentenceBatch 把批量文本拆分为句子，返回所有句子和把句子译文拼回各个文本的函数
+func splitSentenceBatch(ctx context.Context, fromLang string, texts []string, isHTML bool) ([]string, func([]string) []string) {
+	spans := make([][]sentence.Span, len(texts))
+	pieces := make([]string, 0, len(texts))
+	for i, text := range texts {
+		spans[i] = sentenceSpans(ctx, fromLang, text, isHTML)
+		if spans[i] == nil {
+			pieces = append(pieces, text)
+			continue
+		}
+		for _, span := range spans[i] {
+			pieces = append(pieces, text[span.Start:span.End])
+		}
+	}
+
+	join := func(translated []string) []string {
+		results := make([]string, len(texts))
+		next := 0
+		for i, text := range texts {
+			if spans[i] == nil {
+				results[i] = translated[next]
+				next++
+				continue
+			}
+			results[i] = sentence.Join(text, spans[i], translated[next:next+len(spans[i])])
+			next += len(spans[i])
+		}
+		return results
+	}
+	return pieces, join
+}
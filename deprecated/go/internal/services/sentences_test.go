@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentenceSpansModes(t *testing.T) {
+	text := "First line\nstill first. Second."
+
+	on := WithSplitSentences(context.Background(), SplitSentencesOn)
+	assert.Len(t, sentenceSpans(on, "en", text, false), 3)
+
+	noNewlines := WithSplitSentences(context.Background(), SplitSentencesNoNewlines)
+	assert.Len(t, sentenceSpans(noNewlines, "en", text, false), 2)
+
+	off := WithSplitSentences(context.Background(), SplitSentencesOff)
+	assert.Nil(t, sentenceSpans(off, "en", text, false))
+
+	assert.Nil(t, sentenceSpans(on, "en", "Only one sentence.", false))
+	assert.Nil(t, sentenceSpans(on, "en", "<p>One.</p> <p>Two.</p>", true))
+	assert.Len(t, sentenceSpans(on, "en", `See <x id="0"></x>. Then go.`, true), 2)
+}
+
+func TestSplitSentenceBatch(t *testing.T) {
+	ctx := WithSplitSentences(context.Background(), SplitSentencesOn)
+	texts := []string{"One. Two!", "Single", "  今天很好。明天见。 "}
+
+	pieces, join := splitSentenceBatch(ctx, "en", texts, false)
+	assert.Equal(t, []string{"One.", "Two!", "Single", "今天很好。", "明天见。"}, pieces)
+
+	translated := make([]string, len(pieces))
+	for i, piece := range pieces {
+		translated[i] = "[" + strings.ToUpper(piece) + "]"
+	}
+	assert.Equal(t, []string{"[ONE.] [TWO!]", "[SINGLE]", "  [今天很好。][明天见。] "}, join(translated))
+}
+
+func TestValidSplitSentences(t *testing.T) {
+	for _, mode := range []string{"", "0", "1", "nonewlines"} {
+		assert.True(t, ValidSplitSentences(mode), mode)
+	}
+	assert.False(t, ValidSplitSentences("true"))
+}
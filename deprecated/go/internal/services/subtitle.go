@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/xxnuo/MTranServer/internal/subtitle"
+)
+
+// TranslateSubtitle 翻译字幕中的对白文本，时间轴、cue settings、样式块和行结构保持不变。
+// merge 为 true 时把跨字幕的句子合并翻译以获得完整上下文。返回翻译的文本数
+func TranslateSubtitle(ctx context.Context, fromLang, toLang string, f *subtitle.File, merge bool) (int, error) {
+	units := f.Units(merge)
+
+	// 含样式标签的文本按 HTML 翻译，其余按纯文本翻译
+	texts := make([]string, len(units))
+	isHTML := make([]bool, len(units))
+	for i, unit := range units {
+		texts[i] = unit.Text
+		isHTML[i] = unit.HTML
+	}
+
+	results, err := translateByMode(ctx, fromLang, toLang, texts, isHTML)
+	if err != nil {
+		return 0, err
+	}
+
+	f.Apply(units, results)
+	return len(units), nil
+}
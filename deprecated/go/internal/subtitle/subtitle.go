@@ -0,0 +1,166 @@
+package subtitle
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/xxnuo/MTranServer/internal/textfile"
+)
+
+// Format 字幕格式
+type Format string
+
+const (
+	FormatSRT Format = "srt"
+	FormatVTT Format = "vtt"
+)
+
+const (
+	vttHeader = "WEBVTT"
+	arrow     = "-->"
+)
+
+var ErrNoCues = errors.New("no subtitle cues found")
+
+var formats = textfile.NewFormats("subtitle",
+	textfile.Format{Name: string(FormatSRT), ContentType: "application/x-subrip; charset=utf-8"},
+	textfile.Format{Name: string(FormatVTT), Aliases: []string{"webvtt"}, ContentType: "text/vtt; charset=utf-8"},
+)
+
+// ParseFormat 解析格式名称，支持 srt、vtt 和 webvtt
+func ParseFormat(name string) (Format, error) {
+	f, err := formats.Parse(name)
+	return Format(f), err
+}
+
+// ContentType 返回格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	return formats.ContentType(string(f))
+}
+
+// Cue 一条字幕。Timing 为完整的时间轴行（包括 WebVTT 的 cue settings），Lines 为对白文本行
+type Cue struct {
+	Identifier string
+	Timing     string
+	Lines      []string
+}
+
+// Block 文件中以空行分隔的一段。Cue 为空时是原样保留的块，如 WEBVTT 头、NOTE、STYLE、REGION
+type Block struct {
+	Cue *Cue
+	Raw []string
+}
+
+// File 解析后的字幕文件，String 时按原格式输出，并保留 BOM 和 CRLF 换行
+type File struct {
+	Format Format
+	Blocks []Block
+
+	enc textfile.Encoding
+}
+
+// Parse 解析 SRT 或 WebVTT 字幕，以 WEBVTT 开头的视为 WebVTT
+func Parse(data string) (*File, error) {
+	f := &File{Format: FormatSRT}
+
+	data, f.enc = textfile.Decode(data)
+	data = strings.ReplaceAll(data, "\r", "\n")
+
+	if strings.HasPrefix(data, vttHeader) {
+		f.Format = FormatVTT
+	}
+
+	cues := 0
+	for _, lines := range splitBlocks(data) {
+		if cue := parseCue(lines); cue != nil {
+			f.Blocks = append(f.Blocks, Block{Cue: cue})
+			cues++
+		} else {
+			f.Blocks = append(f.Blocks, Block{Raw: lines})
+		}
+	}
+
+	if cues == 0 {
+		return nil, ErrNoCues
+	}
+	return f, nil
+}
+
+// splitBlocks 按空行切分，连续空行视为一个分隔
+func splitBlocks(data string) [][]string {
+	var blocks [][]string
+	var current []string
+
+	for _, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+// parseCue 时间轴行只能是块的第一行或第二行（前面为标识符），否则不是字幕
+func parseCue(lines []string) *Cue {
+	for i := 0; i < len(lines) && i < 2; i++ {
+		if !strings.Contains(lines[i], arrow) {
+			continue
+		}
+		if i == 0 && strings.HasPrefix(lines[0], vttHeader) {
+			return nil
+		}
+
+		cue := &Cue{Timing: lines[i], Lines: append([]string(nil), lines[i+1:]...)}
+		if i == 1 {
+			cue.Identifier = lines[0]
+		}
+		return cue
+	}
+	return nil
+}
+
+// Cues 返回文件中的所有字幕
+func (f *File) Cues() []*Cue {
+	var cues []*Cue
+	for _, b := range f.Blocks {
+		if b.Cue != nil {
+			cues = append(cues, b.Cue)
+		}
+	}
+	return cues
+}
+
+func (f *File) String() string {
+	var sb strings.Builder
+	for i, b := range f.Blocks {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		lines := b.Raw
+		if b.Cue != nil {
+			lines = b.Cue.lines()
+		}
+		for _, line := range lines {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+
+	return f.enc.Encode(sb.String())
+}
+
+func (c *Cue) lines() []string {
+	lines := make([]string, 0, len(c.Lines)+2)
+	if c.Identifier != "" {
+		lines = append(lines, c.Identifier)
+	}
+	lines = append(lines, c.Timing)
+	return append(lines, c.Lines...)
+}
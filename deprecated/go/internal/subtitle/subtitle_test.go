@@ -0,0 +1,166 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const srtSample = `1
+00:00:01,000 --> 00:00:02,500
+<i>Hello there.</i>
+
+2
+00:00:03,000 --> 00:00:05,000
+{\an8}How are
+you today?
+`
+
+const vttSample = `WEBVTT - Example
+
+STYLE
+::cue { color: yellow; }
+
+NOTE This is a comment
+
+intro
+00:00:01.000 --> 00:00:02.000 align:start position:10%
+Good morning.
+
+00:00:03.000 --> 00:00:04.000
+<v Roger>See you.
+`
+
+func translateAll(f *File, merge bool, translate func(string) string) {
+	units := f.Units(merge)
+	results := make([]string, len(units))
+	for i, unit := range units {
+		results[i] = translate(unit.Text)
+	}
+	f.Apply(units, results)
+}
+
+func TestParseSRT(t *testing.T) {
+	f, err := Parse(srtSample)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatSRT, f.Format)
+	cues := f.Cues()
+	require.Len(t, cues, 2)
+	assert.Equal(t, "1", cues[0].Identifier)
+	assert.Equal(t, "00:00:01,000 --> 00:00:02,500", cues[0].Timing)
+	assert.Equal(t, []string{"<i>Hello there.</i>"}, cues[0].Lines)
+	assert.Equal(t, []string{`{\an8}How are`, "you today?"}, cues[1].Lines)
+
+	assert.Equal(t, srtSample, f.String())
+}
+
+func TestParseVTT(t *testing.T) {
+	f, err := Parse(vttSample)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatVTT, f.Format)
+	cues := f.Cues()
+	require.Len(t, cues, 2)
+	assert.Equal(t, "intro", cues[0].Identifier)
+	assert.Equal(t, "00:00:01.000 --> 00:00:02.000 align:start position:10%", cues[0].Timing)
+	assert.Empty(t, cues[1].Identifier)
+
+	assert.Equal(t, vttSample, f.String())
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := Parse("just some text\n\nmore text\n")
+	assert.ErrorIs(t, err, ErrNoCues)
+
+	_, err = Parse("WEBVTT\n\nNOTE only\n")
+	assert.ErrorIs(t, err, ErrNoCues)
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"srt": FormatSRT, ".SRT": FormatSRT, "vtt": FormatVTT, "webvtt": FormatVTT} {
+		got, err := ParseFormat(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	_, err := ParseFormat("ass")
+	assert.Error(t, err)
+}
+
+func TestUnitsPerLine(t *testing.T) {
+	f, err := Parse(srtSample)
+	require.NoError(t, err)
+
+	units := f.Units(false)
+	require.Len(t, units, 3)
+	assert.Equal(t, Unit{Text: "<i>Hello there.</i>", HTML: true, lines: units[0].lines}, units[0])
+	assert.Equal(t, "How are", units[1].Text)
+	assert.False(t, units[1].HTML)
+	assert.Equal(t, "you today?", units[2].Text)
+
+	translateAll(f, false, strings.ToUpper)
+	assert.Equal(t, `1
+00:00:01,000 --> 00:00:02,500
+<I>HELLO THERE.</I>
+
+2
+00:00:03,000 --> 00:00:05,000
+{\an8}HOW ARE
+YOU TODAY?
+`, f.String())
+}
+
+func TestUnitsMerge(t *testing.T) {
+	data := `1
+00:00:01,000 --> 00:00:02,000
+I think that we
+
+2
+00:00:02,000 --> 00:00:03,000
+should go home.
+
+3
+00:00:04,000 --> 00:00:05,000
+- Really?
+- Yes.
+`
+	f, err := Parse(data)
+	require.NoError(t, err)
+
+	units := f.Units(true)
+	require.Len(t, units, 3)
+	assert.Equal(t, "I think that we should go home.", units[0].Text)
+	assert.Equal(t, "- Really?", units[1].Text)
+	assert.Equal(t, "- Yes.", units[2].Text)
+
+	translateAll(f, true, func(s string) string {
+		if s == "I think that we should go home." {
+			return "Ich denke, wir sollten nach Hause gehen."
+		}
+		return s
+	})
+
+	cues := f.Cues()
+	assert.Equal(t, []string{"Ich denke, wir sollten"}, cues[0].Lines)
+	assert.Equal(t, []string{"nach Hause gehen."}, cues[1].Lines)
+	assert.Equal(t, []string{"- Really?", "- Yes."}, cues[2].Lines)
+}
+
+func TestSplitProportional(t *testing.T) {
+	assert.Equal(t, []string{"我认为我们", "应该回家。"}, splitProportional("我认为我们应该回家。", []int{5, 5}))
+	assert.Equal(t, []string{"你好，", "世界。"}, splitProportional("你好，世界。", []int{1, 1}))
+	assert.Equal(t, []string{"<b>one two</b>", "three"}, splitProportional("<b>one two</b> three", []int{3, 1}))
+	assert.Equal(t, []string{"only", ""}, splitProportional("only", []int{1, 1}))
+}
+
+func TestEndsSentence(t *testing.T) {
+	assert.True(t, endsSentence("Hello."))
+	assert.True(t, endsSentence("<i>Hello!</i>"))
+	assert.True(t, endsSentence(`"Really?"`))
+	assert.True(t, endsSentence("你好。"))
+	assert.False(t, endsSentence("I think that"))
+	assert.False(t, endsSentence("Wait,"))
+}
@@ -0,0 +1,264 @@
+package subtitle
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxMergedCues 合并时一组最多包含的字幕数
+const maxMergedCues = 5
+
+// assTagPrefix 行首的 ASS 覆盖标签，如 {\an8}，不参与翻译
+var assTagPrefix = regexp.MustCompile(`^(?:\{\\[^}]*\}\s*)+`)
+
+// Unit 一个翻译单元，译文写回 lines 指向的字幕行
+type Unit struct {
+	Text string
+	// HTML 文本包含 <i>、<font> 等样式标签时按 HTML 翻译以保留标签
+	HTML bool
+
+	lines []lineRef
+}
+
+type lineRef struct {
+	cue    *Cue
+	line   int
+	prefix string
+	weight int
+}
+
+// Units 返回需要翻译的文本单元。默认每行一个单元；merge 为 true 时，
+// 把未在句末结束的相邻字幕合并为一个单元，翻译后按原文长度比例拆回各行
+func (f *File) Units(merge bool) []Unit {
+	var units []Unit
+	var group []lineRef
+
+	flush := func() {
+		if len(group) > 0 {
+			units = append(units, newUnit(group))
+			group = nil
+		}
+	}
+
+	cues := f.Cues()
+	groupCues := 0
+	for _, cue := range cues {
+		refs := cueLines(cue)
+		if len(refs) == 0 {
+			flush()
+			groupCues = 0
+			continue
+		}
+
+		if !merge {
+			for _, ref := range refs {
+				units = append(units, newUnit([]lineRef{ref}))
+			}
+			continue
+		}
+
+		// 对话行（以 - 开头）各自独立，不与前后合并
+		if isDialogue(cue) {
+			flush()
+			for _, ref := range refs {
+				units = append(units, newUnit([]lineRef{ref}))
+			}
+			groupCues = 0
+			continue
+		}
+
+		group = append(group, refs...)
+		groupCues++
+		if endsSentence(cue.Lines[refs[len(refs)-1].line]) || groupCues >= maxMergedCues {
+			flush()
+			groupCues = 0
+		}
+	}
+	flush()
+
+	return units
+}
+
+// Apply 把各单元的译文写回字幕，results 与 Units 的返回值一一对应
+func (f *File) Apply(units []Unit, results []string) {
+	for i, unit := range units {
+		result := strings.Join(strings.Fields(strings.ReplaceAll(results[i], "\n", " ")), " ")
+		if len(unit.lines) == 1 {
+			ref := unit.lines[0]
+			ref.cue.Lines[ref.line] = ref.prefix + result
+			continue
+		}
+
+		weights := make([]int, len(unit.lines))
+		for j, ref := range unit.lines {
+			weights[j] = ref.weight
+		}
+		parts := splitProportional(result, weights)
+		for j, ref := range unit.lines {
+			if parts[j] == "" {
+				continue
+			}
+			ref.cue.Lines[ref.line] = ref.prefix + parts[j]
+		}
+	}
+}
+
+func cueLines(cue *Cue) []lineRef {
+	var refs []lineRef
+	for i, line := range cue.Lines {
+		prefix := assTagPrefix.FindString(line)
+		text := strings.TrimSpace(line[len(prefix):])
+		if text == "" {
+			continue
+		}
+		refs = append(refs, lineRef{
+			cue:    cue,
+			line:   i,
+			prefix: prefix,
+			weight: utf8.RuneCountInString(text),
+		})
+	}
+	return refs
+}
+
+func newUnit(refs []lineRef) Unit {
+	texts := make([]string, len(refs))
+	for i, ref := range refs {
+		line := ref.cue.Lines[ref.line]
+		texts[i] = strings.TrimSpace(line[len(ref.prefix):])
+	}
+	text := strings.Join(texts, " ")
+	return Unit{
+		Text:  text,
+		HTML:  strings.Contains(text, "<"),
+		lines: refs,
+	}
+}
+
+func isDialogue(cue *Cue) bool {
+	for _, line := range cue.Lines {
+		if strings.HasPrefix(strings.TrimSpace(assTagPrefix.ReplaceAllString(line, "")), "-") {
+			return true
+		}
+	}
+	return false
+}
+
+// endsSentence 判断一行是否在句末结束，忽略末尾的样式标签和引号
+func endsSentence(line string) bool {
+	line = strings.TrimSpace(stripTags(line))
+	line = strings.TrimRight(line, `"'”’」』)]`)
+	r, _ := utf8.DecodeLastRuneInString(line)
+	switch r {
+	case '.', '!', '?', '…', '♪', '。', '！', '？':
+		return true
+	}
+	return false
+}
+
+func stripTags(s string) string {
+	var sb strings.Builder
+	inTag := false
+	for _, r := range s {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>' && inTag:
+			inTag = false
+		case !inTag:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// splitProportional 按权重把文本切成 len(weights) 段。有空格的文本在空格处切分，
+// 没有空格的中日韩文本可在任意字符后切分，但不把标点切到下一段，也不切开标签。
+// 无法切分时后面的段为空，Apply 会保留这些行的原文
+func splitProportional(text string, weights []int) []string {
+	parts := make([]string, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+
+	runes := []rune(text)
+	breaks := breakCandidates(runes)
+
+	start := 0
+	cumulative := 0
+	for i := 0; i < len(weights)-1; i++ {
+		cumulative += weights[i]
+		ideal := len(runes) * cumulative / max(total, 1)
+
+		end := len(runes)
+		best := -1
+		for _, b := range breaks {
+			if b <= start || b >= len(runes) {
+				continue
+			}
+			if best < 0 || abs(b-ideal) < abs(best-ideal) {
+				best = b
+			}
+		}
+		if best >= 0 {
+			end = best
+		}
+
+		parts[i] = strings.TrimSpace(string(runes[start:end]))
+		start = end
+	}
+	parts[len(weights)-1] = strings.TrimSpace(string(runes[start:]))
+
+	return parts
+}
+
+// breakCandidates 返回可切分的位置（切分点之前的字符数）
+func breakCandidates(runes []rune) []int {
+	hasSpace := false
+	for _, r := range runes {
+		if r == ' ' {
+			hasSpace = true
+			break
+		}
+	}
+
+	var breaks []int
+	inTag := false
+	for i, r := range runes {
+		switch {
+		case r == '<':
+			inTag = true
+		case r == '>':
+			inTag = false
+		}
+		if inTag || i == len(runes)-1 {
+			continue
+		}
+
+		switch {
+		case r == ' ':
+			breaks = append(breaks, i+1)
+		case !hasSpace && (isWide(r) || isWide(runes[i+1])) && !unicode.IsPunct(runes[i+1]):
+			breaks = append(breaks, i+1)
+		}
+	}
+	return breaks
+}
+
+func isWide(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || unicode.IsPunct(r) && r >= 0x3000
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package textfile
+
+import (
+	"fmt"
+	"strings"
+)
+
+const bom = "\ufeff"
+
+// Encoding 文本文件的 BOM 和换行风格。解析前由 Decode 去掉，输出时由 Encode 恢复
+type Encoding struct {
+	BOM  bool
+	CRLF bool
+}
+
+// Decode 去掉 UTF-8 BOM 并把 CRLF 换行统一为 LF，返回原文件的编码风格
+func Decode(data string) (string, Encoding) {
+	var enc Encoding
+	if rest, ok := strings.CutPrefix(data, bom); ok {
+		enc.BOM = true
+		data = rest
+	}
+	if strings.Contains(data, "\r\n") {
+		enc.CRLF = true
+		data = strings.ReplaceAll(data, "\r\n", "\n")
+	}
+	return data, enc
+}
+
+// Encode 把 LF 换行的文本按原文件的 BOM 和换行风格输出
+func (e Encoding) Encode(s string) string {
+	if e.CRLF {
+		s = strings.ReplaceAll(s, "\n", "\r\n")
+	}
+	if e.BOM {
+		s = bom + s
+	}
+	return s
+}
+
+// HasPrefix 判断去掉 BOM 和开头空白后的内容是否以 prefix 开头，用于识别格式
+func HasPrefix(data, prefix string) bool {
+	return strings.HasPrefix(strings.TrimLeft(strings.TrimPrefix(data, bom), " \t\r\n"), prefix)
+}
+
+// Format 一种文件格式。Aliases 为其他名称或扩展名
+type Format struct {
+	Name        string
+	Aliases     []string
+	ContentType string
+}
+
+// Formats 一类文件支持的格式，第一个为默认格式
+type Formats struct {
+	kind    string
+	formats []Format
+}
+
+// NewFormats 创建格式表，kind 用于错误信息，如 subtitle
+func NewFormats(kind string, formats ...Format) *Formats {
+	return &Formats{kind: kind, formats: formats}
+}
+
+// Parse 按名称、别名或扩展名（可带 .）查找格式，不区分大小写，返回格式名称
+func (fs *Formats) Parse(name string) (string, error) {
+	key := strings.ToLower(strings.TrimPrefix(name, "."))
+	for _, f := range fs.formats {
+		if key == f.Name {
+			return f.Name, nil
+		}
+		for _, alias := range f.Aliases {
+			if key == alias {
+				return f.Name, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unsupported %s format: %s", fs.kind, name)
+}
+
+// ContentType 返回格式对应的 MIME 类型，未知格式返回默认格式的类型
+func (fs *Formats) ContentType(name string) string {
+	for _, f := range fs.formats {
+		if f.Name == name {
+			return f.ContentType
+		}
+	}
+	return fs.formats[0].ContentType
+}
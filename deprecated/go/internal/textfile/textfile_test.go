@@ -0,0 +1,44 @@
+package textfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodingRoundTrip(t *testing.T) {
+	for _, data := range []string{
+		"a\nb\n",
+		bom + "a\nb\n",
+		"a\r\nb\r\n",
+		bom + "a\r\nb\r\n",
+	} {
+		text, enc := Decode(data)
+		assert.Equal(t, "a\nb\n", text)
+		assert.Equal(t, data, enc.Encode(text))
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	assert.True(t, HasPrefix(bom+"\r\n  <xliff>", "<"))
+	assert.False(t, HasPrefix("msgid \"<\"", "<"))
+}
+
+func TestFormats(t *testing.T) {
+	formats := NewFormats("test",
+		Format{Name: "srt", ContentType: "application/x-subrip"},
+		Format{Name: "vtt", Aliases: []string{"webvtt"}, ContentType: "text/vtt"},
+	)
+
+	for name, want := range map[string]string{"srt": "srt", ".SRT": "srt", "webvtt": "vtt", ".vtt": "vtt"} {
+		got, err := formats.Parse(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	_, err := formats.Parse("ass")
+	assert.EqualError(t, err, "unsupported test format: ass")
+
+	assert.Equal(t, "text/vtt", formats.ContentType("vtt"))
+	assert.Equal(t, "application/x-subrip", formats.ContentType("ass"))
+}